@@ -14,9 +14,20 @@ import (
 )
 
 func main() {
+	defer func() {
+		if r := recover(); r != nil {
+			exitAfterPanic(app.LogPanic(r))
+		}
+	}()
+
 	// Define global flags
 	todoDir := flag.String("d", "", "Path to todo directory (overrides config file and env vars)")
 	flag.StringVar(todoDir, "todo-dir", "", "Path to todo directory (overrides config file and env vars)")
+	// Output flags are handled by the CLI; accept them before the command too
+	quiet := flag.Bool("q", false, "Only print errors and requested output")
+	flag.BoolVar(quiet, "quiet", false, "Only print errors and requested output")
+	verbose := flag.Bool("v", false, "Print extra detail, such as the files touched")
+	flag.BoolVar(verbose, "verbose", false, "Print extra detail, such as the files touched")
 
 	// Parse flags, but stop at first non-flag argument (the subcommand)
 	flag.Parse()
@@ -50,6 +61,13 @@ func main() {
 	args := flag.Args()
 
 	if len(args) > 0 {
+		if *quiet {
+			args = append([]string{"--quiet"}, args...)
+		}
+		if *verbose {
+			args = append([]string{"--verbose"}, args...)
+		}
+
 		// CLI mode
 		exitCode := cli.Run(args, svc)
 		os.Exit(exitCode)
@@ -58,9 +76,22 @@ func main() {
 	// TUI mode
 	logs.Logger.Println("Starting app in TUI mode")
 	appModel := app.NewAppModelWithService(svc)
-	p := tea.NewProgram(appModel)
+	safeModel := app.NewRecoverModel(appModel)
+	p := tea.NewProgram(safeModel)
+	safeModel.OnPanic(p.Quit)
 	if _, err := p.Run(); err != nil {
 		fmt.Println("Error running program:", err)
 		os.Exit(1)
 	}
+	if err := safeModel.Err(); err != nil {
+		exitAfterPanic(err)
+	}
+}
+
+// exitAfterPanic tells the user where to find the details of a recovered
+// panic and exits
+func exitAfterPanic(err error) {
+	fmt.Fprintf(os.Stderr, "wydo hit an unexpected error and had to close: %v\n", err)
+	fmt.Fprintf(os.Stderr, "The full details were written to %s\n", logs.Path())
+	os.Exit(2)
 }
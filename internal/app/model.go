@@ -1,11 +1,15 @@
 package app
 
 import (
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/wyattlefevre/wydocli/internal/components"
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
 	"github.com/wyattlefevre/wydocli/internal/service"
 	"github.com/wyattlefevre/wydocli/logs"
@@ -19,6 +23,31 @@ type AppModel struct {
 	projects       map[string]data.Project
 	loading        bool
 	service        service.TaskService
+
+	// Debounced writes: updates are applied in memory immediately and
+	// persisted together once no new update has arrived for writeDebounce
+	pendingUpdates []data.Task
+	pendingDeletes []string
+	writeSeq       int
+
+	// watcher reloads tasks when the files change on disk (watch_files)
+	watcher *fileWatcher
+
+	// serviceMu serializes service calls. Commands run in their own
+	// goroutines, so a reload could otherwise interleave with a write.
+	serviceMu sync.Mutex
+	// written holds the task files' stamps after wydo's last write, so the
+	// watcher events caused by that write don't trigger a reload. Guarded by
+	// serviceMu.
+	written []fileStamp
+}
+
+// writeDebounce is how long to wait for further updates before writing
+const writeDebounce = 150 * time.Millisecond
+
+// flushWritesMsg is sent when the debounce timer for writeSeq expires
+type flushWritesMsg struct {
+	seq int
 }
 
 type ViewType int
@@ -36,6 +65,9 @@ type ParseTaskMismatchMsg struct {
 type DataLoadedMsg struct {
 	Tasks    []data.Task
 	Projects map[string]data.Project
+	// Warning is shown in the info bar, for problems found reading the
+	// files (rather than after wydo's own writes)
+	Warning string
 }
 
 // NewAppModel creates a new AppModel without a service (legacy, loads data internally)
@@ -65,7 +97,8 @@ func NewAppModelWithService(svc service.TaskService) *AppModel {
 }
 
 func (a *AppModel) Init() tea.Cmd {
-	return func() tea.Msg {
+	initCmd := a.taskManager.Init()
+	loadCmd := func() tea.Msg {
 		a.loading = true
 
 		var tasks []data.Task
@@ -74,6 +107,8 @@ func (a *AppModel) Init() tea.Cmd {
 
 		if a.service != nil {
 			// Use service if available
+			a.serviceMu.Lock()
+			defer a.serviceMu.Unlock()
 			tasks, err = a.service.List()
 			if err != nil {
 				logs.Logger.Fatalf("ERROR: %s", err.Error())
@@ -91,7 +126,53 @@ func (a *AppModel) Init() tea.Cmd {
 				return err
 			}
 		}
-		return DataLoadedMsg{tasks, projects}
+		return DataLoadedMsg{Tasks: tasks, Projects: projects, Warning: data.StrayDoneWarning(tasks)}
+	}
+	return tea.Batch(initCmd, loadCmd, a.startWatching())
+}
+
+// startWatching begins watching the task files when watch_files is enabled
+func (a *AppModel) startWatching() tea.Cmd {
+	if a.service == nil || !config.Get().GetWatchFiles() {
+		return nil
+	}
+	w, err := newFileWatcher(data.GetTodoFilePath(), data.GetDoneFilePath())
+	if err != nil {
+		logs.Logger.Printf("Could not watch task files: %v", err)
+		return nil
+	}
+	a.watcher = w
+	return waitForFileChange(w.changes)
+}
+
+// reloadFromDisk re-reads the task files after an external change. The
+// updates and deletes still waiting to be written (pending and deleted,
+// copied by the caller since the command runs off the update loop) are
+// re-applied on top so they aren't lost, and the task manager keeps any open
+// editor's unsaved edits (see WithTasks).
+func (a *AppModel) reloadFromDisk(pending []data.Task, deleted []string) tea.Cmd {
+	return func() tea.Msg {
+		a.serviceMu.Lock()
+		defer a.serviceMu.Unlock()
+		if a.written != nil && slices.Equal(a.written, taskFileStamps()) {
+			// Only wydo's own write changed the files
+			return nil
+		}
+		if err := a.service.Reload(); err != nil {
+			return tea.Printf("Error reloading tasks: %v", err)
+		}
+		tasks, err := a.service.List()
+		if err != nil {
+			return tea.Printf("Error loading tasks: %v", err)
+		}
+		tasks = slices.Clone(tasks)
+		for _, t := range pending {
+			tasks = data.UpdateTask(tasks, t)
+		}
+		for _, id := range deleted {
+			tasks = data.DeleteTask(tasks, id)
+		}
+		return DataLoadedMsg{Tasks: tasks, Projects: a.service.GetProjects(), Warning: data.StrayDoneWarning(tasks)}
 	}
 }
 
@@ -103,11 +184,34 @@ func (a *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.projects = msg.Projects
 		a.loading = false
 
+		var cmd tea.Cmd
 		if tm, ok := a.taskManager.(*components.TaskManagerModel); ok {
 			a.taskManager = tm.WithTasks(a.tasks)
+			cmd = tm.RefreshDonePage()
+			if msg.Warning != "" {
+				tm.ShowWarning(msg.Warning)
+			}
+		}
+		if pm, ok := a.projectManager.(*components.ProjectManagerModel); ok {
+			a.projectManager = pm.WithProjects(a.projects)
 		}
 
-		return a, nil
+		return a, cmd
+
+	case tea.WindowSizeMsg:
+		// Both views lay out against the terminal size, not just the visible one
+		var tmCmd, pmCmd tea.Cmd
+		a.taskManager, tmCmd = a.taskManager.Update(msg)
+		a.projectManager, pmCmd = a.projectManager.Update(msg)
+		return a, tea.Batch(tmCmd, pmCmd)
+
+	case FilesChangedMsg:
+		logs.Logger.Println("Task files changed on disk, reloading")
+		var next tea.Cmd
+		if a.watcher != nil {
+			next = waitForFileChange(a.watcher.changes)
+		}
+		return a, tea.Batch(a.reloadFromDisk(slices.Clone(a.pendingUpdates), slices.Clone(a.pendingDeletes)), next)
 
 	case ParseTaskMismatchMsg:
 		logs.Logger.Println("Parse Mismatch detected, must resolve")
@@ -129,7 +233,7 @@ func (a *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Global keys only when not in modal state
 		switch msg.String() {
 		case "ctrl+c", "q":
-			return a, tea.Quit
+			return a.quit()
 		case "P":
 			a.currentView = ViewProjectManager
 			return a, nil
@@ -154,44 +258,72 @@ func (a *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, nil
 		}
 
-	case components.TaskUpdateMsg:
-		// Update the task using service or data layer
-		a.loading = true
+	case components.QuitRequestMsg:
+		return a.quit()
 
+	case components.TaskUpdateMsg:
 		if a.service != nil {
-			return a, func() tea.Msg {
-				err := a.service.Update(msg.Task)
-				if err != nil {
-					return tea.Printf("Error updating task: %v", err)
-				}
-				tasks, err := a.service.List()
-				if err != nil {
-					return tea.Printf("Error loading tasks: %v", err)
-				}
-				return DataLoadedMsg{tasks, a.service.GetProjects()}
-			}
+			return a, a.queueUpdate(msg.Task)
 		}
 
 		// Legacy path without service
 		a.tasks = data.UpdateTask(a.tasks, msg.Task)
+		return a, a.writeLegacy()
+
+	case components.TasksUpdateMsg:
+		if a.service != nil {
+			// Queued together, so the debounce writes them all at once
+			return a, a.queueChanges(msg.Tasks, msg.Deleted)
+		}
+
+		// Legacy path without service
+		for _, t := range msg.Tasks {
+			a.tasks = data.UpdateTask(a.tasks, t)
+		}
+		for _, id := range msg.Deleted {
+			a.tasks = data.DeleteTask(a.tasks, id)
+		}
+		return a, a.writeLegacy()
+
+	case flushWritesMsg:
+		if msg.seq != a.writeSeq || !a.hasPendingWrites() {
+			// A newer update restarted the timer, or nothing left to write
+			return a, nil
+		}
+		a.loading = true
+		pending, deleted := a.pendingUpdates, a.pendingDeletes
+		a.pendingUpdates, a.pendingDeletes = nil, nil
 		return a, func() tea.Msg {
-			err := data.WriteData(a.tasks)
+			a.serviceMu.Lock()
+			defer a.serviceMu.Unlock()
+			err := a.service.UpdateAndDelete(pending, deleted)
+			a.recordWrite()
 			if err != nil {
-				return tea.Printf("Error writing tasks: %v", err)
+				return tea.Printf("Error updating tasks: %v", err)
 			}
-			tasks, projects, err := data.LoadData(false)
+			tasks, err := a.service.List()
 			if err != nil {
 				return tea.Printf("Error loading tasks: %v", err)
 			}
-			return DataLoadedMsg{tasks, projects}
+			return DataLoadedMsg{Tasks: tasks, Projects: a.service.GetProjects()}
 		}
 
 	case components.ArchiveRequestMsg:
+		a.flushPendingUpdates()
 		a.loading = true
 		count := msg.Count
+		project := msg.Project
 		return a, func() tea.Msg {
 			if a.service != nil {
-				err := a.service.Archive()
+				a.serviceMu.Lock()
+				defer a.serviceMu.Unlock()
+				var err error
+				if project != "" {
+					err = a.service.CleanProject(project, false)
+				} else {
+					err = a.service.Archive()
+				}
+				a.recordWrite()
 				if err != nil {
 					return tea.Printf("Error archiving: %v", err)
 				}
@@ -204,7 +336,7 @@ func (a *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Legacy path without service
-			err := data.ArchiveDone(a.tasks)
+			err := data.ArchiveDoneInProject(a.tasks, project)
 			if err != nil {
 				return tea.Printf("Error archiving: %v", err)
 			}
@@ -217,7 +349,66 @@ func (a *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return components.ArchiveCompleteMsg{Count: count}
 		}
 
-	case components.ArchiveCompleteMsg:
+	case components.PurgeRequestMsg:
+		a.flushPendingUpdates()
+		a.loading = true
+		count := msg.Count
+		return a, func() tea.Msg {
+			if a.service != nil {
+				a.serviceMu.Lock()
+				defer a.serviceMu.Unlock()
+				err := a.service.PurgeDone()
+				a.recordWrite()
+				if err != nil {
+					return tea.Printf("Error purging: %v", err)
+				}
+				tasks, err := a.service.List()
+				if err != nil {
+					return tea.Printf("Error loading: %v", err)
+				}
+				a.tasks = tasks
+				return components.PurgeCompleteMsg{Count: count}
+			}
+
+			// Legacy path without service
+			tasks := data.PurgeDone(a.tasks, config.Get().GetPurgeDoneFile())
+			if err := data.WriteData(tasks); err != nil {
+				return tea.Printf("Error purging: %v", err)
+			}
+			tasks, projects, err := data.LoadData(false)
+			if err != nil {
+				return tea.Printf("Error loading: %v", err)
+			}
+			a.tasks = tasks
+			a.projects = projects
+			return components.PurgeCompleteMsg{Count: count}
+		}
+
+	case components.DonePageRequestMsg:
+		offset, limit := msg.Offset, msg.Limit
+		return a, func() tea.Msg {
+			var tasks []data.Task
+			var err error
+			if a.service != nil {
+				a.serviceMu.Lock()
+				tasks, err = a.service.ListDonePaged(offset, limit)
+				a.serviceMu.Unlock()
+			} else {
+				tasks, err = data.LoadDonePage(offset, limit)
+			}
+			if err != nil {
+				return tea.Printf("Error loading done.txt: %v", err)
+			}
+			return components.DonePageLoadedMsg{Offset: offset, Tasks: tasks}
+		}
+
+	case components.DonePageLoadedMsg:
+		// The page belongs to the task manager whichever view is showing
+		var cmd tea.Cmd
+		a.taskManager, cmd = a.taskManager.Update(msg)
+		return a, cmd
+
+	case components.ArchiveCompleteMsg, components.PurgeCompleteMsg:
 		a.loading = false
 		if tm, ok := a.taskManager.(*components.TaskManagerModel); ok {
 			a.taskManager = tm.WithTasks(a.tasks)
@@ -239,6 +430,135 @@ func (a *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, cmd
 }
 
+// writeLegacy writes a.tasks and reloads them when running without a service
+func (a *AppModel) writeLegacy() tea.Cmd {
+	a.loading = true
+	return func() tea.Msg {
+		err := data.WriteData(a.tasks)
+		if err != nil {
+			return tea.Printf("Error writing tasks: %v", err)
+		}
+		tasks, projects, err := data.LoadData(false)
+		if err != nil {
+			return tea.Printf("Error loading tasks: %v", err)
+		}
+		return DataLoadedMsg{Tasks: tasks, Projects: projects}
+	}
+}
+
+// queueUpdate applies a task update in memory and (re)starts the debounce
+// timer so a burst of updates results in a single write.
+func (a *AppModel) queueUpdate(task data.Task) tea.Cmd {
+	return a.queueChanges([]data.Task{task}, nil)
+}
+
+// queueChanges is queueUpdate for several tasks, also deleting the tasks
+// with the deleted IDs. A pending update to a deleted task is dropped.
+func (a *AppModel) queueChanges(tasks []data.Task, deleted []string) tea.Cmd {
+	a.tasks = slices.Clone(a.tasks)
+	for _, task := range tasks {
+		a.pendingUpdates = data.UpdateTask(a.pendingUpdates, task)
+		a.tasks = data.UpdateTask(a.tasks, task)
+	}
+	for _, id := range deleted {
+		a.pendingUpdates = data.DeleteTask(a.pendingUpdates, id)
+		a.pendingDeletes = append(a.pendingDeletes, id)
+		a.tasks = data.DeleteTask(a.tasks, id)
+	}
+	if tm, ok := a.taskManager.(*components.TaskManagerModel); ok {
+		a.taskManager = tm.WithTasks(a.tasks)
+	}
+
+	a.writeSeq++
+	seq := a.writeSeq
+	return tea.Tick(writeDebounce, func(time.Time) tea.Msg {
+		return flushWritesMsg{seq: seq}
+	})
+}
+
+// flushPendingUpdates synchronously writes any debounced updates.
+// Called on quit so no edits are lost mid-debounce, and before archiving or
+// purging so those see (and don't clobber) the latest edits.
+func (a *AppModel) flushPendingUpdates() {
+	if a.service == nil || !a.hasPendingWrites() {
+		return
+	}
+	a.serviceMu.Lock()
+	defer a.serviceMu.Unlock()
+	err := a.service.UpdateAndDelete(a.pendingUpdates, a.pendingDeletes)
+	a.recordWrite()
+	if err != nil {
+		logs.Logger.Printf("Error flushing pending updates: %v", err)
+		return
+	}
+	a.pendingUpdates, a.pendingDeletes = nil, nil
+}
+
+// hasPendingWrites reports whether any updates or deletes await the
+// debounced write
+func (a *AppModel) hasPendingWrites() bool {
+	return len(a.pendingUpdates) > 0 || len(a.pendingDeletes) > 0
+}
+
+// quit flushes pending writes, archives if configured, saves the session
+// and exits
+func (a *AppModel) quit() (tea.Model, tea.Cmd) {
+	a.flushPendingUpdates()
+	a.archiveOnQuit()
+	a.saveSession()
+	if a.watcher != nil {
+		a.watcher.Close()
+	}
+	return a, tea.Quit
+}
+
+// archiveOnQuit moves completed tasks in todo.txt to done.txt when
+// archive_on_quit is enabled. Call it after flushPendingUpdates so tasks
+// completed just before quitting are included.
+func (a *AppModel) archiveOnQuit() {
+	if a.service == nil || !config.Get().GetArchiveOnQuit() {
+		return
+	}
+	a.serviceMu.Lock()
+	defer a.serviceMu.Unlock()
+	tasks, err := a.service.List()
+	if err != nil {
+		logs.Logger.Printf("Error listing tasks to archive: %v", err)
+		return
+	}
+	todoPath := data.GetTodoFilePath()
+	if !slices.ContainsFunc(tasks, func(t data.Task) bool { return t.Done && t.File == todoPath }) {
+		return
+	}
+	if err := a.service.Archive(); err != nil {
+		logs.Logger.Printf("Error archiving on quit: %v", err)
+	}
+}
+
+// recordWrite remembers the task files' stamps after a write of wydo's own.
+// Call it with serviceMu held.
+func (a *AppModel) recordWrite() {
+	a.written = taskFileStamps()
+}
+
+// taskFileStamps stamps todo.txt and done.txt
+func taskFileStamps() []fileStamp {
+	return stampFiles(data.GetTodoFilePath(), data.GetDoneFilePath())
+}
+
+// saveSession remembers the task manager's filter/sort/group for the next
+// launch when restore_session is enabled
+func (a *AppModel) saveSession() {
+	if !config.Get().GetRestoreSession() {
+		return
+	}
+	if tm, ok := a.taskManager.(*components.TaskManagerModel); ok {
+		if err := tm.SaveSession(config.GetSessionPath()); err != nil {
+			logs.Logger.Printf("Error saving session: %v", err)
+		}
+	}
+}
+
 func (a *AppModel) View() string {
 	topBarStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("2")).
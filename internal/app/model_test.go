@@ -0,0 +1,322 @@
+package app
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/internal/components"
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// fakeService is a TaskService that keeps tasks in memory and counts writes
+type fakeService struct {
+	tasks    []data.Task
+	writes   int
+	reloads  int
+	batches  [][]data.Task
+	deleted  []string
+	archives int
+
+	// busy and overlapped detect calls made while another is running
+	busy       atomic.Bool
+	overlapped atomic.Bool
+}
+
+// enter marks a call as running, noting any overlap with another call.
+// The returned func ends the call.
+func (f *fakeService) enter() func() {
+	if !f.busy.CompareAndSwap(false, true) {
+		f.overlapped.Store(true)
+		return func() {}
+	}
+	time.Sleep(time.Millisecond)
+	return func() { f.busy.Store(false) }
+}
+
+func (f *fakeService) List() ([]data.Task, error) {
+	defer f.enter()()
+	return f.tasks, nil
+}
+
+func (f *fakeService) ListByProject(string) ([]data.Task, error) { return nil, nil }
+func (f *fakeService) ListByContext(string) ([]data.Task, error) { return nil, nil }
+func (f *fakeService) ListPending() ([]data.Task, error)         { return nil, nil }
+func (f *fakeService) ListDone() ([]data.Task, error)            { return nil, nil }
+func (f *fakeService) Get(string) (*data.Task, error)            { return nil, nil }
+func (f *fakeService) Add(string) (*data.Task, error)            { return nil, nil }
+func (f *fakeService) Insert(string, int) (*data.Task, error)    { return nil, nil }
+func (f *fakeService) Capture(string) error                      { return nil }
+func (f *fakeService) Update(task data.Task) error               { return f.UpdateMany([]data.Task{task}) }
+func (f *fakeService) Complete(string) error                     { return nil }
+func (f *fakeService) Uncomplete(string) error                   { return nil }
+func (f *fakeService) Delete(string) error                       { return nil }
+func (f *fakeService) PurgeDone() error                          { return nil }
+func (f *fakeService) CleanProject(string, bool) error           { return nil }
+func (f *fakeService) RenameTagKey(string, string) error         { return nil }
+func (f *fakeService) GetProjects() map[string]data.Project      { return nil }
+func (f *fakeService) ProjectsSorted() []data.Project            { return nil }
+
+func (f *fakeService) Reload() error {
+	defer f.enter()()
+	f.reloads++
+	return nil
+}
+
+func (f *fakeService) ListDueBetween(time.Time, time.Time) ([]data.Task, error) {
+	return nil, nil
+}
+
+func (f *fakeService) ListDonePaged(offset, limit int) ([]data.Task, error) {
+	var done []data.Task
+	for _, t := range f.tasks {
+		if t.File == data.GetDoneFilePath() {
+			done = append(done, t)
+		}
+	}
+	if offset >= len(done) {
+		return nil, nil
+	}
+	return done[offset:min(offset+limit, len(done))], nil
+}
+
+func (f *fakeService) Stats() (service.ServiceStats, error) {
+	return service.ComputeStats(f.tasks, data.Today()), nil
+}
+
+func (f *fakeService) Split(string, string, bool) ([]data.Task, error) {
+	return nil, nil
+}
+
+func (f *fakeService) UpdateMany(tasks []data.Task) error {
+	return f.UpdateAndDelete(tasks, nil)
+}
+
+func (f *fakeService) UpdateAndDelete(tasks []data.Task, deleted []string) error {
+	defer f.enter()()
+	f.writes++
+	f.batches = append(f.batches, tasks)
+	f.deleted = append(f.deleted, deleted...)
+	for _, t := range tasks {
+		f.tasks = data.UpdateTask(f.tasks, t)
+	}
+	for _, id := range deleted {
+		f.tasks = data.DeleteTask(f.tasks, id)
+	}
+	return nil
+}
+
+// Archive moves done tasks in todo.txt to done.txt, like the real service
+func (f *fakeService) Archive() error {
+	f.archives++
+	for i := range f.tasks {
+		if f.tasks[i].Done && f.tasks[i].File == data.GetTodoFilePath() {
+			f.tasks[i].File = data.GetDoneFilePath()
+		}
+	}
+	return nil
+}
+
+func newTestApp(t *testing.T) (*AppModel, *fakeService) {
+	t.Helper()
+	svc := &fakeService{
+		tasks: []data.Task{
+			{ID: "t1", Name: "one", Tags: map[string]string{}, File: data.GetTodoFilePath()},
+			{ID: "t2", Name: "two", Tags: map[string]string{}, File: data.GetTodoFilePath()},
+			{ID: "t3", Name: "three", Tags: map[string]string{}, File: data.GetTodoFilePath()},
+		},
+	}
+	a := NewAppModelWithService(svc)
+	a.taskManager.Init()
+	a.Update(DataLoadedMsg{Tasks: svc.tasks, Projects: nil})
+	return a, svc
+}
+
+func TestAppModel_DebouncesRapidUpdates(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	for _, id := range []string{"t1", "t2", "t3"} {
+		_, cmd := a.Update(components.TaskUpdateMsg{Task: data.Task{ID: id, Name: id + " done", Done: true, File: data.GetTodoFilePath()}})
+		if cmd == nil {
+			t.Fatal("expected a debounce timer command")
+		}
+	}
+	if svc.writes != 0 {
+		t.Fatalf("expected no writes during debounce, got %d", svc.writes)
+	}
+
+	// Timers from the earlier updates are superseded and must not write
+	a.Update(flushWritesMsg{seq: 1})
+	a.Update(flushWritesMsg{seq: 2})
+	if svc.writes != 0 {
+		t.Fatalf("expected stale timers not to write, got %d writes", svc.writes)
+	}
+
+	_, cmd := a.Update(flushWritesMsg{seq: a.writeSeq})
+	if cmd == nil {
+		t.Fatal("expected flush command for the latest timer")
+	}
+	cmd()
+
+	if svc.writes != 1 {
+		t.Errorf("expected exactly 1 write, got %d", svc.writes)
+	}
+	if len(svc.batches[0]) != 3 {
+		t.Errorf("expected 3 tasks in the batch, got %d", len(svc.batches[0]))
+	}
+}
+
+func TestAppModel_QuitFlushesPendingUpdates(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one", Done: true, File: data.GetTodoFilePath()}})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+
+	if svc.writes != 1 {
+		t.Fatalf("expected pending update to be flushed on quit, got %d writes", svc.writes)
+	}
+	if len(a.pendingUpdates) != 0 {
+		t.Errorf("expected no pending updates after quit, got %d", len(a.pendingUpdates))
+	}
+}
+
+func TestAppModel_ArchiveFlushesPendingUpdates(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	// Completed moments ago, still waiting on the debounce
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one", Done: true, File: data.GetTodoFilePath()}})
+	_, cmd := a.Update(components.ArchiveRequestMsg{Count: 1})
+	if svc.writes != 1 || len(a.pendingUpdates) != 0 {
+		t.Fatalf("expected pending update flushed before archiving, got %d writes", svc.writes)
+	}
+	cmd()
+	if got := svc.tasks[0].File; got != data.GetDoneFilePath() {
+		t.Errorf("expected the just-completed task archived, got %s", got)
+	}
+
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t2", Name: "two edited", File: data.GetTodoFilePath()}})
+	a.Update(components.PurgeRequestMsg{Count: 1})
+	if svc.writes != 2 || len(a.pendingUpdates) != 0 {
+		t.Errorf("expected pending update flushed before purging, got %d writes", svc.writes)
+	}
+}
+
+func TestAppModel_LoadsDonePages(t *testing.T) {
+	a, svc := newTestApp(t)
+	svc.tasks[0].Done = true
+	svc.tasks[0].File = data.GetDoneFilePath()
+
+	_, cmd := a.Update(components.DonePageRequestMsg{Offset: 0, Limit: 10})
+	page, ok := cmd().(components.DonePageLoadedMsg)
+	if !ok {
+		t.Fatalf("expected DonePageLoadedMsg, got %T", cmd())
+	}
+	if len(page.Tasks) != 1 || page.Tasks[0].ID != "t1" {
+		t.Errorf("expected the one done.txt task, got %v", page.Tasks)
+	}
+}
+
+func TestAppModel_QuitArchivesWhenEnabled(t *testing.T) {
+	cfg := config.Get()
+	defer func(v bool) { cfg.ArchiveOnQuit = v }(cfg.ArchiveOnQuit)
+
+	cfg.ArchiveOnQuit = false
+	a, svc := newTestApp(t)
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one", Done: true, File: data.GetTodoFilePath()}})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if svc.archives != 0 {
+		t.Fatalf("expected no archive with archive_on_quit off, got %d", svc.archives)
+	}
+
+	cfg.ArchiveOnQuit = true
+	a, svc = newTestApp(t)
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if svc.archives != 0 {
+		t.Fatalf("expected no archive with nothing done, got %d", svc.archives)
+	}
+
+	a, svc = newTestApp(t)
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one", Done: true, File: data.GetTodoFilePath()}})
+	a.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if svc.archives != 1 {
+		t.Fatalf("expected one archive on quit, got %d", svc.archives)
+	}
+	if got := svc.tasks[0].File; got != data.GetDoneFilePath() {
+		t.Errorf("expected the task completed before quitting in done.txt, got %s", got)
+	}
+	if got := svc.tasks[1].File; got != data.GetTodoFilePath() {
+		t.Errorf("expected pending task to stay in todo.txt, got %s", got)
+	}
+}
+
+func TestAppModel_QuitRequestFlushesAndQuits(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one (edited)", File: data.GetTodoFilePath()}})
+	_, cmd := a.Update(components.QuitRequestMsg{})
+	if cmd == nil {
+		t.Fatal("expected a quit command")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Errorf("expected tea.QuitMsg, got %T", cmd())
+	}
+	if svc.writes != 1 {
+		t.Errorf("expected the editor's save flushed before quitting, got %d writes", svc.writes)
+	}
+}
+
+func TestAppModel_TasksUpdateWritesOnce(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	a.Update(components.TasksUpdateMsg{Tasks: []data.Task{
+		{ID: "t1", Name: "one", Projects: []string{"work"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+		{ID: "t2", Name: "two", Projects: []string{"work"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+	}})
+	_, cmd := a.Update(flushWritesMsg{seq: a.writeSeq})
+	if cmd == nil {
+		t.Fatal("expected flush command")
+	}
+	cmd()
+
+	if svc.writes != 1 || len(svc.batches[0]) != 2 {
+		t.Fatalf("expected one write of 2 tasks, got %d writes", svc.writes)
+	}
+	for _, task := range svc.tasks[:2] {
+		if !task.HasProject("work") {
+			t.Errorf("expected %s to gain +work", task.ID)
+		}
+	}
+}
+
+func TestAppModel_TasksUpdateDeletesInTheSameWrite(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	// An edit to a task that's then deleted isn't written back
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one edited", Tags: map[string]string{}, File: data.GetTodoFilePath()}})
+	a.Update(components.TasksUpdateMsg{
+		Tasks:   []data.Task{{ID: "t4", Name: "four", Tags: map[string]string{}, File: data.GetTodoFilePath()}},
+		Deleted: []string{"t1"},
+	})
+	if len(a.tasks) != 3 || a.tasks[0].ID != "t2" {
+		t.Fatalf("expected t1 gone from the list right away, got %+v", a.tasks)
+	}
+
+	_, cmd := a.Update(flushWritesMsg{seq: a.writeSeq})
+	if cmd == nil {
+		t.Fatal("expected flush command")
+	}
+	cmd()
+
+	if svc.writes != 1 || len(svc.batches[0]) != 1 || svc.batches[0][0].ID != "t4" {
+		t.Fatalf("expected one write adding t4, got %d writes, batches %+v", svc.writes, svc.batches)
+	}
+	if len(svc.deleted) != 1 || svc.deleted[0] != "t1" {
+		t.Errorf("deleted = %v, want [t1]", svc.deleted)
+	}
+	if a.hasPendingWrites() {
+		t.Error("expected nothing pending after the flush")
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/logs"
+)
+
+// PanicError describes a recovered panic
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// LogPanic writes a recovered panic and the current stack to the log.
+// Call it from the deferred function that recovered, so the stack still
+// includes the panicking frames.
+func LogPanic(v any) *PanicError {
+	err := &PanicError{Value: v, Stack: debug.Stack()}
+	logs.Logger.Printf("panic: %v\n%s", v, err.Stack)
+	return err
+}
+
+// RecoverModel wraps a model so that a panic in Init, Update or View is
+// logged and ends the program normally, letting bubbletea restore the
+// terminal, instead of taking the process down mid-render
+type RecoverModel struct {
+	model   tea.Model
+	err     *PanicError
+	onPanic func()
+}
+
+// NewRecoverModel wraps model with panic recovery
+func NewRecoverModel(model tea.Model) *RecoverModel {
+	return &RecoverModel{model: model}
+}
+
+// OnPanic sets a function to call when View panics. View can't return a
+// command, so this is how the program is told to quit (e.g. Program.Quit).
+func (r *RecoverModel) OnPanic(f func()) {
+	r.onPanic = f
+}
+
+// Err returns the recovered panic, or nil if there was none
+func (r *RecoverModel) Err() error {
+	if r.err == nil {
+		return nil
+	}
+	return r.err
+}
+
+func (r *RecoverModel) Init() (cmd tea.Cmd) {
+	defer func() {
+		if v := recover(); v != nil {
+			r.err = LogPanic(v)
+			cmd = tea.Quit
+		}
+	}()
+	return r.model.Init()
+}
+
+func (r *RecoverModel) Update(msg tea.Msg) (model tea.Model, cmd tea.Cmd) {
+	if r.err != nil {
+		return r, tea.Quit
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			r.err = LogPanic(v)
+			model, cmd = r, tea.Quit
+		}
+	}()
+	r.model, cmd = r.model.Update(msg)
+	return r, cmd
+}
+
+func (r *RecoverModel) View() (view string) {
+	if r.err != nil {
+		return ""
+	}
+	defer func() {
+		if v := recover(); v != nil {
+			r.err = LogPanic(v)
+			view = ""
+			if r.onPanic != nil {
+				go r.onPanic()
+			}
+		}
+	}()
+	return r.model.View()
+}
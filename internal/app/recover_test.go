@@ -0,0 +1,80 @@
+package app
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/logs"
+)
+
+// panicModel panics in Update or View once the trigger message arrives
+type panicModel struct {
+	inView    bool
+	triggered bool
+}
+
+type triggerPanicMsg struct{}
+
+func (m *panicModel) Init() tea.Cmd {
+	return func() tea.Msg { return triggerPanicMsg{} }
+}
+
+func (m *panicModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if _, ok := msg.(triggerPanicMsg); ok {
+		if !m.inView {
+			panic("boom in update")
+		}
+		m.triggered = true
+	}
+	return m, nil
+}
+
+func (m *panicModel) View() string {
+	if m.triggered {
+		panic("boom in view")
+	}
+	return "ok"
+}
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	orig := logs.Logger
+	logs.Logger = log.New(&buf, "", 0)
+	t.Cleanup(func() { logs.Logger = orig })
+	return &buf
+}
+
+func TestRecoverModel_CatchesPanics(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		inView bool
+		want   string
+	}{
+		{"update", false, "boom in update"},
+		{"view", true, "boom in view"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := captureLog(t)
+
+			safe := NewRecoverModel(&panicModel{inView: tc.inView})
+			p := tea.NewProgram(safe, tea.WithInput(nil), tea.WithOutput(io.Discard))
+			safe.OnPanic(p.Quit)
+			if _, err := p.Run(); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			if safe.Err() == nil || !strings.Contains(safe.Err().Error(), tc.want) {
+				t.Fatalf("Err() = %v, want it to mention %q", safe.Err(), tc.want)
+			}
+			logged := buf.String()
+			if !strings.Contains(logged, "panic: "+tc.want) || !strings.Contains(logged, "goroutine") {
+				t.Errorf("expected the panic and its stack in the log, got %q", logged)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
+	"github.com/wyattlefevre/wydocli/logs"
+)
+
+// watchDebounce is how long the task files must be quiet after a change
+// before a reload is triggered, so an editor's save (often several events)
+// causes a single reload
+const watchDebounce = 200 * time.Millisecond
+
+// FilesChangedMsg is sent when the watched task files changed on disk
+type FilesChangedMsg struct{}
+
+// fileWatcher reports debounced changes to a set of files
+type fileWatcher struct {
+	watcher *fsnotify.Watcher
+	changes chan struct{}
+}
+
+// newFileWatcher watches the given files. Their directories are watched
+// rather than the files themselves so that editors which save by replacing
+// the file are still noticed.
+func newFileWatcher(paths ...string) (*fileWatcher, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	dirs := make(map[string]bool)
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		names[p] = true
+		dir := filepath.Dir(p)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := w.Add(dir); err != nil {
+			w.Close()
+			return nil, err
+		}
+	}
+
+	fw := &fileWatcher{watcher: w, changes: make(chan struct{})}
+	events := make(chan struct{})
+	go func() {
+		defer close(events)
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if names[filepath.Clean(ev.Name)] && !ev.Has(fsnotify.Chmod) {
+					events <- struct{}{}
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				logs.Logger.Printf("File watcher error: %v", err)
+			}
+		}
+	}()
+	go debounceEvents(events, fw.changes, watchDebounce)
+	return fw, nil
+}
+
+// Close stops watching
+func (fw *fileWatcher) Close() error {
+	return fw.watcher.Close()
+}
+
+// debounceEvents sends one value on out for each burst of values on in that
+// is followed by a quiet period of d. out is closed once in is closed.
+func debounceEvents(in <-chan struct{}, out chan<- struct{}, d time.Duration) {
+	defer close(out)
+	var timer <-chan time.Time
+	for {
+		select {
+		case _, ok := <-in:
+			if !ok {
+				return
+			}
+			timer = time.After(d)
+		case <-timer:
+			timer = nil
+			out <- struct{}{}
+		}
+	}
+}
+
+// fileStamp identifies a version of a file by its size and modification
+// time. A missing file has the zero stamp.
+type fileStamp struct {
+	size    int64
+	modTime int64
+}
+
+// stampFiles returns the current stamp of each path
+func stampFiles(paths ...string) []fileStamp {
+	stamps := make([]fileStamp, len(paths))
+	for i, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			stamps[i] = fileStamp{size: info.Size(), modTime: info.ModTime().UnixNano()}
+		}
+	}
+	return stamps
+}
+
+// waitForFileChange blocks until the next debounced change. The app
+// re-issues it after every FilesChangedMsg to keep listening.
+func waitForFileChange(changes <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-changes; !ok {
+			return nil
+		}
+		return FilesChangedMsg{}
+	}
+}
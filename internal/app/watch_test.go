@@ -0,0 +1,190 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/internal/components"
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+// loadedFrom runs cmd and returns the DataLoadedMsg it (or its batch) produces
+func loadedFrom(t *testing.T, cmd tea.Cmd) DataLoadedMsg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected a command")
+	}
+	switch msg := cmd().(type) {
+	case DataLoadedMsg:
+		return msg
+	case tea.BatchMsg:
+		for _, c := range msg {
+			if c == nil {
+				continue
+			}
+			if loaded, ok := c().(DataLoadedMsg); ok {
+				return loaded
+			}
+		}
+	}
+	t.Fatal("expected a DataLoadedMsg")
+	return DataLoadedMsg{}
+}
+
+func TestAppModel_FilesChangedTriggersReload(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	// Simulate an external edit
+	svc.tasks = append(svc.tasks, data.Task{ID: "t4", Name: "four", Tags: map[string]string{}, File: data.GetTodoFilePath()})
+
+	_, cmd := a.Update(FilesChangedMsg{})
+	loaded := loadedFrom(t, cmd)
+	if svc.reloads != 1 {
+		t.Errorf("expected service to reload once, got %d", svc.reloads)
+	}
+	if len(loaded.Tasks) != 4 {
+		t.Errorf("expected 4 tasks after reload, got %d", len(loaded.Tasks))
+	}
+}
+
+func TestAppModel_FilesChangedWarnsAboutCompletedTasksInTodo(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	// Someone checks a task off in todo.txt by hand
+	svc.tasks[0].Done = true
+	_, cmd := a.Update(FilesChangedMsg{})
+	loaded := loadedFrom(t, cmd)
+	if !strings.HasPrefix(loaded.Warning, "1 completed task(s) in todo.txt") {
+		t.Fatalf("Warning = %q, want the completed-in-todo warning", loaded.Warning)
+	}
+	a.Update(loaded)
+	if view := a.View(); !strings.Contains(view, "Warning: 1 completed task(s)") {
+		t.Errorf("expected the warning in the info bar, got:\n%s", view)
+	}
+}
+
+func TestAppModel_FilesChangedKeepsPendingUpdates(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one edited", Tags: map[string]string{}, File: data.GetTodoFilePath()}})
+
+	_, cmd := a.Update(FilesChangedMsg{})
+	// The update loop moves on before the reload runs; it must use its copy
+	a.pendingUpdates = nil
+	loaded := loadedFrom(t, cmd)
+	for _, task := range loaded.Tasks {
+		if task.ID == "t1" && task.Name != "one edited" {
+			t.Errorf("expected unsaved edit to survive the reload, got %q", task.Name)
+		}
+	}
+	if svc.writes != 0 {
+		t.Errorf("expected reload not to write, got %d writes", svc.writes)
+	}
+}
+
+func TestDebounceEvents(t *testing.T) {
+	in := make(chan struct{})
+	out := make(chan struct{})
+	go debounceEvents(in, out, 20*time.Millisecond)
+
+	for i := 0; i < 5; i++ {
+		in <- struct{}{}
+	}
+
+	select {
+	case <-out:
+	case <-time.After(time.Second):
+		t.Fatal("expected a change after the burst")
+	}
+	select {
+	case <-out:
+		t.Fatal("expected a single change for the burst")
+	case <-time.After(60 * time.Millisecond):
+	}
+
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("expected out to close once in is closed")
+	}
+}
+
+func TestFileWatcher_ReportsExternalChange(t *testing.T) {
+	dir := t.TempDir()
+	todo := filepath.Join(dir, "todo.txt")
+	if err := os.WriteFile(todo, []byte("Pay rent\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := newFileWatcher(todo, filepath.Join(dir, "done.txt"))
+	if err != nil {
+		t.Fatalf("newFileWatcher: %v", err)
+	}
+	defer w.Close()
+
+	// Changes to other files in the directory are ignored
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(todo, []byte("Pay rent\nCall mom\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- waitForFileChange(w.changes)() }()
+	select {
+	case msg := <-msgs:
+		if _, ok := msg.(FilesChangedMsg); !ok {
+			t.Errorf("expected FilesChangedMsg, got %T", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a change notification")
+	}
+}
+
+func TestAppModel_FilesChangedIgnoresOwnWrite(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one edited", Tags: map[string]string{}, File: data.GetTodoFilePath()}})
+	_, cmd := a.Update(flushWritesMsg{seq: a.writeSeq})
+	cmd()
+
+	// The watcher reports the flush wydo just made; nothing else changed
+	_, cmd = a.Update(FilesChangedMsg{})
+	if msg := cmd(); msg != nil {
+		t.Errorf("expected no reload for wydo's own write, got %T", msg)
+	}
+	if svc.reloads != 0 {
+		t.Errorf("expected no reloads, got %d", svc.reloads)
+	}
+}
+
+func TestAppModel_FlushAndFileChangeDoNotOverlap(t *testing.T) {
+	a, svc := newTestApp(t)
+
+	a.Update(components.TaskUpdateMsg{Task: data.Task{ID: "t1", Name: "one edited", Tags: map[string]string{}, File: data.GetTodoFilePath()}})
+	_, flush := a.Update(flushWritesMsg{seq: a.writeSeq})
+	_, reload := a.Update(FilesChangedMsg{})
+
+	// Bubble Tea runs commands concurrently; the service calls must not
+	var wg sync.WaitGroup
+	for _, cmd := range []tea.Cmd{flush, reload} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd()
+		}()
+	}
+	wg.Wait()
+
+	if svc.overlapped.Load() {
+		t.Error("expected service calls from the flush and the reload not to overlap")
+	}
+	if svc.writes != 1 {
+		t.Errorf("expected 1 write, got %d", svc.writes)
+	}
+}
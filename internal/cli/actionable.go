@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/template"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runActionable(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("actionable", flag.ContinueOnError)
+	project := fs.String("p", "", "Filter by project")
+	context := fs.String("c", "", "Filter by context")
+	format := fs.String("format", "", "Output template (Go text/template) or preset: short, oneline")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	var tmpl *template.Template
+	if *format != "" {
+		var err error
+		tmpl, err = parseListFormat(*format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid format: %v\n", err)
+			return 1
+		}
+	}
+
+	tasks, err := svc.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+	tasks = filterActionable(tasks, config.Get().GetActionable(), data.Today())
+	if *project != "" {
+		tasks = filterByProject(tasks, *project)
+	}
+	if *context != "" {
+		tasks = filterByContext(tasks, *context)
+	}
+
+	if tmpl != nil {
+		if err := writeFormattedTasks(os.Stdout, tasks, tmpl); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(tasks) == 0 {
+		infof("Nothing actionable.\n")
+		return 0
+	}
+	for _, t := range tasks {
+		printTask(t)
+	}
+	infof("\n%d task(s)\n", len(tasks))
+	return 0
+}
+
+// filterActionable keeps the tasks that meet every actionable rule
+func filterActionable(tasks []data.Task, rules []string, today string) []data.Task {
+	var filtered []data.Task
+	for _, t := range tasks {
+		if t.IsActionable(rules, today) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
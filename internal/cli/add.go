@@ -9,6 +9,10 @@ import (
 )
 
 func runAdd(args []string, svc service.TaskService) int {
+	// "--" only protects flag-like words in the text from the global flags
+	if len(args) > 0 && args[0] == "--" {
+		args = args[1:]
+	}
 	if len(args) == 0 {
 		fmt.Fprintln(os.Stderr, "Error: task description required")
 		fmt.Fprintln(os.Stderr, "Usage: wydo add \"Task description +project @context\"")
@@ -21,10 +25,11 @@ func runAdd(args []string, svc service.TaskService) int {
 	task, err := svc.Add(rawLine)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error adding task: %v\n", err)
-		return 1
+		return exitCode(err)
 	}
 
-	fmt.Printf("Added: %s\n", task.String())
-	fmt.Printf("ID: %s\n", task.ID)
+	infof("Added: %s\n", task.String())
+	infof("ID: %s\n", task.ID)
+	verbosef("File: %s\n", task.File)
 	return 0
 }
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// defaultAgendaDays is how far past --from the agenda extends without --to
+const defaultAgendaDays = 7
+
+func runAgenda(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("agenda", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "First day of the range (default today)")
+	toFlag := fs.String("to", "", fmt.Sprintf("Last day of the range, inclusive (default %d days after --from)", defaultAgendaDays))
+	week := fs.Bool("week", false, "Show the week containing --from, starting on week_start")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *week && *toFlag != "" {
+		fmt.Fprintln(os.Stderr, "Error: --week and --to cannot be used together")
+		return 1
+	}
+
+	from := data.Now()
+	if *fromFlag != "" {
+		t, _, err := data.ParseFlexibleDate(*fromFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --from: %v\n", err)
+			return 1
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, defaultAgendaDays)
+	if *week {
+		from, to = data.WeekBounds(from)
+	} else if *toFlag != "" {
+		t, _, err := data.ParseFlexibleDate(*toFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --to: %v\n", err)
+			return 1
+		}
+		to = t
+	}
+	if to.Before(from) {
+		fmt.Fprintln(os.Stderr, "Error: --to is before --from")
+		return 1
+	}
+
+	tasks, err := svc.ListDueBetween(from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	if len(tasks) == 0 {
+		fmt.Printf("Nothing due from %s to %s.\n", from.Format(data.DateFormat), to.Format(data.DateFormat))
+		return 0
+	}
+
+	// One heading per day, tasks are already sorted by due date
+	day := ""
+	for _, t := range tasks {
+		if due := t.ValidDueDate(); due != day {
+			if day != "" {
+				fmt.Println()
+			}
+			day = due
+			fmt.Println(day)
+		}
+		printTask(t)
+	}
+
+	fmt.Printf("\n%d task(s)\n", len(tasks))
+	return 0
+}
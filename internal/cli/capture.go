@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// runCapture appends a line verbatim for later triage. Unlike add, the line
+// isn't parsed or rewritten into canonical todo.txt form.
+func runCapture(args []string, svc service.TaskService) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: task text required")
+		fmt.Fprintln(os.Stderr, "Usage: wydo capture \"Text to triage later\"")
+		return 1
+	}
+
+	if err := svc.Capture(strings.Join(args, " ")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error capturing task: %v\n", err)
+		return 1
+	}
+	fmt.Println("Captured")
+	return 0
+}
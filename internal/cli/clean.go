@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runClean(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("clean", flag.ContinueOnError)
+	project := fs.String("project", "", "Project whose completed tasks to clean up")
+	fs.StringVar(project, "p", "", "Project whose completed tasks to clean up (shorthand)")
+	purge := fs.Bool("purge", false, "Delete the completed tasks instead of archiving them")
+	yes := fs.Bool("yes", false, "Don't ask for confirmation when purging")
+	fs.BoolVar(yes, "y", false, "Don't ask for confirmation when purging (shorthand)")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	if *project == "" {
+		fmt.Fprintln(os.Stderr, "Error: --project is required")
+		fmt.Fprintln(os.Stderr, "Usage: wydo clean --project <name> [--purge]")
+		return 1
+	}
+
+	tasks, err := svc.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	if !*purge {
+		todoPath := data.GetTodoFilePath()
+		count := 0
+		for _, t := range tasks {
+			if t.Done && t.File == todoPath && t.HasProject(*project) {
+				count++
+			}
+		}
+		if count == 0 {
+			fmt.Printf("No completed tasks to archive in +%s.\n", *project)
+			return 0
+		}
+		if err := svc.CleanProject(*project, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Error archiving tasks: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Archived %d completed task(s) in +%s\n", count, *project)
+		return 0
+	}
+
+	includeDoneFile := config.Get().GetPurgeDoneFile()
+	count := len(tasks) - len(data.PurgeDoneInProject(tasks, *project, includeDoneFile))
+	if count == 0 {
+		fmt.Printf("No completed tasks to purge in +%s.\n", *project)
+		return 0
+	}
+
+	if !*yes {
+		prompt := fmt.Sprintf("Permanently delete %d completed task(s) in +%s?", count, *project)
+		if !confirm(os.Stdin, prompt) {
+			fmt.Println("Aborted.")
+			return 1
+		}
+	}
+
+	if err := svc.CleanProject(*project, true); err != nil {
+		fmt.Fprintf(os.Stderr, "Error purging tasks: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Purged %d completed task(s) in +%s\n", count, *project)
+	return 0
+}
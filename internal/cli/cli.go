@@ -1,32 +1,80 @@
 package cli
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
 	"github.com/wyattlefevre/wydocli/internal/service"
 )
 
 // Run executes the CLI with the given arguments.
 // Returns an exit code (0 for success, non-zero for errors).
 func Run(args []string, svc service.TaskService) int {
+	args = parseGlobalFlags(args)
 	if len(args) == 0 {
 		printUsage()
 		return 1
 	}
 
-	command := args[0]
-	cmdArgs := args[1:]
-
+	command, cmdArgs, err := resolveAlias(args[0], args[1:], config.Get().GetAliases())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
 	switch command {
 	case "add", "a":
 		return runAdd(cmdArgs, svc)
+	case "capture", "cap":
+		return runCapture(cmdArgs, svc)
 	case "list", "ls", "l":
 		return runList(cmdArgs, svc)
+	case "actionable":
+		return runActionable(cmdArgs, svc)
 	case "done", "do", "d":
 		return runDone(cmdArgs, svc)
 	case "delete", "rm", "del":
 		return runDelete(cmdArgs, svc)
+	case "dup", "duplicate":
+		return runDup(cmdArgs, svc)
+	case "split":
+		return runSplit(cmdArgs, svc)
+	case "defer":
+		return runDefer(cmdArgs, svc)
+	case "normalize", "fmt":
+		return runNormalize(cmdArgs, svc)
+	case "report":
+		return runReport(cmdArgs, svc)
+	case "agenda":
+		return runAgenda(cmdArgs, svc)
+	case "log":
+		return runLog(cmdArgs, svc)
+	case "projects":
+		return runProjects(cmdArgs, svc)
+	case "prompt":
+		return runPrompt(cmdArgs, svc)
+	case "export":
+		return runExport(cmdArgs, svc)
+	case "serve":
+		return runServe(cmdArgs, svc)
+	case "purge":
+		return runPurge(cmdArgs, svc)
+	case "dedupe-projects":
+		return runDedupe(cmdArgs, svc, false)
+	case "dedupe-contexts":
+		return runDedupe(cmdArgs, svc, true)
+	case "rename-tag":
+		return runRenameTag(cmdArgs, svc)
+	case "clean":
+		return runClean(cmdArgs, svc)
+	case "restore":
+		return runRestore(cmdArgs, svc)
+	case "keys":
+		return runKeys(cmdArgs)
 	case "help", "-h", "--help":
 		printUsage()
 		return 0
@@ -37,29 +85,201 @@ func Run(args []string, svc service.TaskService) int {
 	}
 }
 
+// warnStrayDone warns about completed tasks left in todo.txt. Only list
+// warns, so the warning isn't repeated after every command.
+func warnStrayDone(svc service.TaskService) {
+	tasks, err := svc.List()
+	if err != nil {
+		return
+	}
+	if warning := data.StrayDoneWarning(tasks); warning != "" {
+		warnf("%s\n", warning)
+	}
+}
+
+// Exit codes for failures scripts may want to tell apart. Anything else
+// exits with 1.
+const (
+	exitNotFound    = 2
+	exitAmbiguousID = 3
+	exitWriteFailed = 4
+)
+
+// exitCode returns the exit code for an error from the service
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		return exitNotFound
+	case errors.Is(err, service.ErrAmbiguousID):
+		return exitAmbiguousID
+	case errors.Is(err, service.ErrWriteFailed):
+		return exitWriteFailed
+	}
+	return 1
+}
+
+// resolveAlias follows configured aliases from command until it reaches a
+// name that isn't one, prepending any arguments each alias adds. An alias
+// that leads back to itself is an error.
+func resolveAlias(command string, args []string, aliases map[string]string) (string, []string, error) {
+	seen := map[string]bool{}
+	for {
+		target, ok := aliases[command]
+		if !ok {
+			return command, args, nil
+		}
+		if seen[command] {
+			return "", nil, fmt.Errorf("alias %q loops back on itself", command)
+		}
+		seen[command] = true
+
+		fields := strings.Fields(target)
+		if len(fields) == 0 {
+			return "", nil, fmt.Errorf("alias %q is empty", command)
+		}
+		command = fields[0]
+		args = append(fields[1:], args...)
+	}
+}
+
+// parseInterspersed parses flags that may appear before or after positional
+// arguments (e.g. "wydo done <id> --archive") and returns the positionals
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
 func printUsage() {
 	fmt.Println(`wydo - A command-line task manager using todo.txt format
 
-Usage: wydo [command] [arguments]
+Usage: wydo [-q|-v] [command] [arguments]
+
+Global flags (before or after the command):
+  -q, --quiet    Only print errors and requested output (add, done, delete, list)
+  -v, --verbose  Also print details such as the files touched
 
 Commands:
   add, a      Add a new task
               wydo add "Task description +project @context"
 
+  capture     Append text to todo.txt as-is, without parsing or normalizing it (alias: cap)
+              wydo capture "call dentist re: crown  +health"
+
   list, ls, l List tasks
               wydo list              # List all pending tasks
-              wydo list --all        # List all tasks including done
+              wydo list --all        # List all tasks including done, future and blocked
+              wydo list --include-future  # Include tasks with a future t: date
+              wydo list --include-blocked # Include blocked tasks (blocked: tag or @waiting)
+              wydo list --blocked    # List only blocked tasks
               wydo list -p project   # Filter by project
               wydo list -c context   # Filter by context
               wydo list --done       # List only completed tasks
+              wydo list --has-due    # List only tasks with a due date (--no-due: without)
+              wydo list --format short                 # Preset: short, oneline
+              wydo list --format '{{.ID}} {{.Name}}'   # Custom Go template
+              wydo list -p work --ids | xargs -n1 wydo done   # Full IDs only, one per line
+
+  actionable  List tasks you can act on now, as defined by the actionable config
+              wydo actionable          # Default: pending, not future, not blocked
+              wydo actionable -p work --format short
 
   done, do, d Mark a task as complete
               wydo done <task-id>
+              wydo done <task-id> --archive   # Also archive it if it's already done in todo.txt
 
   delete, rm  Delete a task
               wydo delete <task-id>
 
+  purge       Delete completed tasks without archiving them
+              wydo purge               # Asks for confirmation
+              wydo purge --yes         # Skip the confirmation
+
+  clean       Archive or purge one project's completed tasks
+              wydo clean --project work           # Move them to done.txt
+              wydo clean --project work --purge   # Delete them (asks for confirmation)
+
+  restore     Restore todo.txt and done.txt from a backup (see "backups" in the config)
+              wydo restore             # List backups, most recent first
+              wydo restore --index 2   # Restore the second most recent (asks for confirmation)
+
+  dup         Duplicate a task as a new pending task
+              wydo dup <task-id>
+              wydo dup --suffix <task-id>   # Append "(copy)" to the name
+
+  split       Split a task's name into several tasks with the same metadata
+              wydo split <task-id>              # Split on ";"
+              wydo split <task-id> ","          # Split on a custom delimiter
+              wydo split --complete <task-id>   # Mark the original done instead of deleting it
+
+  defer       Push a task's due date back (sets it from today if it has none)
+              wydo defer <task-id>       # One day
+              wydo defer <task-id> 7     # A week
+
+  normalize   Rewrite tasks in canonical todo.txt form (alias: fmt)
+              wydo normalize           # Show what would change
+              wydo normalize --write   # Apply the changes
+
+  dedupe-projects  Merge projects that differ only by case or a typo (+errand, +errands)
+              wydo dedupe-projects             # Asks before merging each group
+              wydo dedupe-projects --dry-run   # Only show the groups
+              wydo dedupe-projects --distance 0   # Case differences only
+              wydo dedupe-contexts             # The same for @contexts
+
+  rename-tag  Rename a tag key on every task, pending and done
+              wydo rename-tag est estimate   # est:2h becomes estimate:2h
+
+  report      Total estimated (est:) and spent (spent:) time per project
+              wydo report              # All tasks
+              wydo report --pending    # Only pending tasks
+
+  projects    List projects with the first line of their note as a description
+              wydo projects            # Names and descriptions
+              wydo projects --count    # Also pending and done task counts
+
+  prompt      Print overdue and due-today counts for a shell prompt, or nothing
+              wydo prompt              # e.g. "⚑2 ⌛3": 2 overdue, 3 due today
+              wydo prompt --format '{{.Overdue}}/{{.Today}}'
+
+  agenda      List pending tasks by due date within a range (inclusive)
+              wydo agenda                                  # Today and the next 7 days
+              wydo agenda --from 2024-03-01 --to 2024-03-31
+              wydo agenda --week                           # This week (see week_start)
+
+  log         List completed tasks by completion date, most recent first
+              wydo log                                     # The last 7 days
+              wydo log --days 30
+              wydo log --since 2024-03-01 --until 2024-03-31
+
+  keys        Print the TUI's keybindings for each mode (with config applied)
+              wydo keys
+
+  export      Export pending tasks with due dates for calendar apps
+              wydo export --format ics > tasks.ics
+              wydo export -p work      # Accepts the same filters as list
+
+  serve       Serve tasks over HTTP as JSON for integrations
+              wydo serve                   # Listens on 127.0.0.1:8080
+              wydo serve --addr :9000      # All interfaces, read-only unless --allow-remote-writes
+              GET /tasks?status=&project=&context=   POST /tasks {"line": "..."}
+              POST /tasks/{id}/complete              DELETE /tasks/{id}
+
   help        Show this help message
 
+Extra command names can be set under "aliases" in the config file,
+e.g. {"aliases": {"addm": "add", "lsa": "list --all"}}.
+
+Exit codes: 0 success, 2 no task with that ID, 3 ambiguous ID prefix,
+4 todo.txt or done.txt couldn't be written, 1 any other error.
+
 Running wydo without arguments launches the interactive TUI.`)
 }
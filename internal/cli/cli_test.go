@@ -1,11 +1,15 @@
 package cli
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
 	"github.com/wyattlefevre/wydocli/internal/service"
 )
 
@@ -125,8 +129,8 @@ func TestRunDone_InvalidID(t *testing.T) {
 	svc := setupTestService(t, "basic")
 
 	exitCode := runDone([]string{"nonexistent"}, svc)
-	if exitCode != 1 {
-		t.Errorf("Expected exit code 1 for invalid ID, got %d", exitCode)
+	if exitCode != exitNotFound {
+		t.Errorf("Expected exit code %d for invalid ID, got %d", exitNotFound, exitCode)
 	}
 }
 
@@ -143,8 +147,8 @@ func TestRunDelete_InvalidID(t *testing.T) {
 	svc := setupTestService(t, "basic")
 
 	exitCode := runDelete([]string{"nonexistent"}, svc)
-	if exitCode != 1 {
-		t.Errorf("Expected exit code 1 for invalid ID, got %d", exitCode)
+	if exitCode != exitNotFound {
+		t.Errorf("Expected exit code %d for invalid ID, got %d", exitNotFound, exitCode)
 	}
 }
 
@@ -242,3 +246,1095 @@ func TestAddDoneDeleteWorkflow(t *testing.T) {
 		t.Errorf("Expected 0 tasks after delete, got %d", len(allTasks))
 	}
 }
+
+func TestWriteFormattedTasks(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("(A) Write report +work @office due:2025-01-10", "abcdef123456", "todo.txt"),
+		data.ParseTask("Buy milk", "0123456789", "todo.txt"),
+	}
+
+	tests := []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:     "custom template",
+			format:   "{{.ShortID}}|{{.Priority}}|{{.Name}}|{{.Projects}}|{{.Due}}",
+			expected: "abcdef1|A|Write report|[work]|2025-01-10\n0123456||Buy milk|[]|\n",
+		},
+		{
+			name:     "short preset",
+			format:   "short",
+			expected: "abcdef1 Write report\n0123456 Buy milk\n",
+		},
+		{
+			name:     "oneline preset",
+			format:   "oneline",
+			expected: "abcdef1 (A) Write report +work @office due:2025-01-10\n0123456 Buy milk\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := parseListFormat(tc.format)
+			if err != nil {
+				t.Fatalf("parseListFormat(%q) error: %v", tc.format, err)
+			}
+			var buf bytes.Buffer
+			if err := writeFormattedTasks(&buf, tasks, tmpl); err != nil {
+				t.Fatalf("writeFormattedTasks error: %v", err)
+			}
+			if buf.String() != tc.expected {
+				t.Errorf("output = %q, want %q", buf.String(), tc.expected)
+			}
+		})
+	}
+}
+
+func TestRunList_InvalidFormat(t *testing.T) {
+	svc := setupTestService(t, "basic")
+
+	exitCode := runList([]string{"--format", "{{.Name"}, svc)
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1 for invalid format, got %d", exitCode)
+	}
+}
+
+func TestRunDup(t *testing.T) {
+	svc := setupTempService(t, "", "")
+
+	if exitCode := runAdd([]string{"(B) Weekly review +admin"}, svc); exitCode != 0 {
+		t.Fatalf("Failed to add task, exit code: %d", exitCode)
+	}
+	tasks, _ := svc.List()
+	original := tasks[0]
+	if err := svc.Complete(original.ID); err != nil {
+		t.Fatalf("Failed to complete task: %v", err)
+	}
+	done, _ := svc.ListDone()
+
+	if exitCode := runDup([]string{"--suffix", done[0].ID}, svc); exitCode != 0 {
+		t.Fatalf("Failed to duplicate task, exit code: %d", exitCode)
+	}
+
+	pending, _ := svc.ListPending()
+	if len(pending) != 1 {
+		t.Fatalf("Expected 1 pending duplicate, got %d", len(pending))
+	}
+	dup := pending[0]
+	if dup.ID == done[0].ID {
+		t.Error("Expected duplicate to have a distinct ID")
+	}
+	if dup.Name != "Weekly review (copy)" {
+		t.Errorf("Name = %q, want %q", dup.Name, "Weekly review (copy)")
+	}
+	if !dup.HasProject("admin") {
+		t.Error("Expected duplicate to keep project +admin")
+	}
+	if dup.Done {
+		t.Error("Expected duplicate to be pending")
+	}
+}
+
+func TestRunDup_RequiresID(t *testing.T) {
+	svc := setupTestService(t, "basic")
+
+	if exitCode := runDup([]string{}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for missing ID, got %d", exitCode)
+	}
+}
+
+func TestRunNormalize(t *testing.T) {
+	original := "Buy  milk due:2024-01-01 @store +home\n(A)   Call mom\n"
+	svc := setupTempService(t, original, "")
+	todoPath := data.GetTodoFilePath()
+
+	// Without --write the file is left untouched
+	if exitCode := runNormalize([]string{}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	content, _ := os.ReadFile(todoPath)
+	if string(content) != original {
+		t.Errorf("todo.txt modified without --write: %q", content)
+	}
+
+	if exitCode := runNormalize([]string{"--write"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	content, _ = os.ReadFile(todoPath)
+	expected := "Buy milk +home @store due:2024-01-01\n(A) Call mom\n"
+	if string(content) != expected {
+		t.Errorf("todo.txt = %q, want %q", content, expected)
+	}
+}
+
+func TestWriteNormalizeDiff(t *testing.T) {
+	lines := []data.NormalizedLine{
+		{LineNum: 1, Original: "Buy  milk", Normalized: "Buy milk"},
+		{LineNum: 2, Original: "", Normalized: ""},
+		{LineNum: 3, Original: "Call mom", Normalized: "Call mom"},
+	}
+
+	var buf bytes.Buffer
+	n := writeNormalizeDiff(&buf, "todo.txt", lines)
+	if n != 2 {
+		t.Errorf("changed = %d, want 2", n)
+	}
+	expected := "--- todo.txt\n-1: Buy  milk\n+1: Buy milk\n-2: \n"
+	if buf.String() != expected {
+		t.Errorf("diff = %q, want %q", buf.String(), expected)
+	}
+}
+
+// captureStdout returns everything fn writes to os.Stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stdout, fn)
+}
+
+// captureStderr returns everything fn writes to os.Stderr
+func captureStderr(t *testing.T, fn func()) string {
+	t.Helper()
+	return captureFile(t, &os.Stderr, fn)
+}
+
+// captureFile returns everything fn writes to *f
+func captureFile(t *testing.T, f **os.File, fn func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	orig := *f
+	*f = w
+	defer func() { *f = orig }()
+
+	fn()
+
+	w.Close()
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	return buf.String()
+}
+
+func TestRunList_FutureThreshold(t *testing.T) {
+	svc := setupTempService(t, "Start now\nStart later t:2999-01-01\n", "")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, "Start now\n"},
+		{[]string{"--include-future"}, "Start now\nStart later\n"},
+		{[]string{"--all"}, "Start now\nStart later\n"},
+	}
+
+	for _, tc := range tests {
+		var exitCode int
+		out := captureStdout(t, func() {
+			exitCode = runList(append(tc.args, "--format", "{{.Name}}"), svc)
+		})
+		if exitCode != 0 {
+			t.Errorf("%v: expected exit code 0, got %d", tc.args, exitCode)
+		}
+		if out != tc.expected {
+			t.Errorf("%v: output = %q, want %q", tc.args, out, tc.expected)
+		}
+	}
+}
+
+func TestRunDone_Archive(t *testing.T) {
+	svc := setupTempService(t, "", "")
+
+	for _, line := range []string{"Keep me", "Ship it +work", "Just complete"} {
+		if exitCode := runAdd([]string{line}, svc); exitCode != 0 {
+			t.Fatalf("Failed to add task, exit code: %d", exitCode)
+		}
+	}
+	// IDs are derived from line positions, so look them up after each write
+	idOf := func(name string) string {
+		tasks, _ := svc.List()
+		for _, task := range tasks {
+			if task.Name == name {
+				return task.ID
+			}
+		}
+		t.Fatalf("task %q not found", name)
+		return ""
+	}
+
+	// Flags may follow the task ID
+	if exitCode := runDone([]string{idOf("Ship it"), "--archive"}, svc); exitCode != 0 {
+		t.Fatalf("Failed to complete and archive task, exit code: %d", exitCode)
+	}
+	if exitCode := runDone([]string{idOf("Just complete")}, svc); exitCode != 0 {
+		t.Fatalf("Failed to complete task, exit code: %d", exitCode)
+	}
+
+	todo, _ := os.ReadFile(data.GetTodoFilePath())
+	done, _ := os.ReadFile(data.GetDoneFilePath())
+	if bytes.Contains(todo, []byte("Ship it")) {
+		t.Errorf("archived task still in todo.txt:\n%s", todo)
+	}
+	if !bytes.HasPrefix(done, []byte("x ")) || !bytes.Contains(done, []byte("Ship it +work")) {
+		t.Errorf("expected completed task in done.txt, got:\n%s", done)
+	}
+	if !bytes.Contains(todo, []byte("Keep me")) {
+		t.Errorf("expected pending task to stay in todo.txt, got:\n%s", todo)
+	}
+}
+
+func TestBuildTimeReport(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("Design +work est:2h spent:90m", "1", ""),
+		data.ParseTask("Build +work +client est:5h spent:1h", "2", ""),
+		data.ParseTask("Errands est:30m", "3", ""),
+		data.ParseTask("Untracked +work", "4", ""),
+	}
+
+	rows, total := buildTimeReport(tasks)
+	expected := []timeReportRow{
+		{Project: "client", Estimate: 300, Spent: 60, Tasks: 1},
+		{Project: "work", Estimate: 420, Spent: 150, Tasks: 2},
+		{Project: "", Estimate: 30, Spent: 0, Tasks: 1},
+	}
+	if len(rows) != len(expected) {
+		t.Fatalf("rows = %+v, want %+v", rows, expected)
+	}
+	for i := range expected {
+		if rows[i] != expected[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], expected[i])
+		}
+	}
+	// Tasks in several projects count once toward the total
+	if total.Estimate != 450 || total.Spent != 150 || total.Tasks != 3 {
+		t.Errorf("total = %+v, want est 450 spent 150 tasks 3", total)
+	}
+
+	var buf bytes.Buffer
+	writeTimeReport(&buf, rows, total)
+	want := "PROJECT  SPENT  EST    TASKS\n" +
+		"+client  1h     5h     1\n" +
+		"+work    2h30m  7h     2\n" +
+		"(none)   0m     30m    1\n" +
+		"Total    2h30m  7h30m  3\n"
+	if buf.String() != want {
+		t.Errorf("report =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestRunPurge(t *testing.T) {
+	todo := "Pending one\nx 2024-01-02 Finished\nPending two\nx Also finished\n"
+	done := "x 2023-12-01 Archived long ago\n"
+	svc := setupTempService(t, todo, done)
+
+	if exitCode := runPurge([]string{"--yes"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+
+	gotTodo, _ := os.ReadFile(data.GetTodoFilePath())
+	if string(gotTodo) != "Pending one\nPending two\n" {
+		t.Errorf("todo.txt = %q, want only pending tasks", gotTodo)
+	}
+	gotDone, _ := os.ReadFile(data.GetDoneFilePath())
+	if string(gotDone) != done {
+		t.Errorf("done.txt = %q, want it untouched", gotDone)
+	}
+
+	pending, _ := svc.ListPending()
+	if len(pending) != 2 {
+		t.Errorf("Expected 2 pending tasks, got %d", len(pending))
+	}
+
+	// Purge including done.txt
+	config.Get().PurgeDoneFile = true
+	if exitCode := runPurge([]string{"-y"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	gotDone, _ = os.ReadFile(data.GetDoneFilePath())
+	if len(gotDone) != 0 {
+		t.Errorf("done.txt = %q, want it empty", gotDone)
+	}
+	all, _ := svc.List()
+	if len(all) != 2 {
+		t.Errorf("Expected 2 remaining tasks, got %d", len(all))
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"y\n", true},
+		{"YES\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, tc := range tests {
+		if got := confirm(strings.NewReader(tc.input), "Continue?"); got != tc.expected {
+			t.Errorf("confirm(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestRunClean(t *testing.T) {
+	todo := "Pending +work\nx 2024-01-02 Shipped +work\nx 2024-01-03 Groceries +home\nx 2024-01-04 Retro +team +work\n"
+	done := "x 2023-12-01 Old launch +work\nx 2023-12-02 Old chores +home\n"
+	svc := setupTempService(t, todo, done)
+
+	if exitCode := runClean([]string{"--project", "work"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	gotTodo, _ := os.ReadFile(data.GetTodoFilePath())
+	if string(gotTodo) != "Pending +work\nx 2024-01-03 Groceries +home\n" {
+		t.Errorf("todo.txt = %q, want only +work's done tasks archived", gotTodo)
+	}
+	gotDone, _ := os.ReadFile(data.GetDoneFilePath())
+	for _, want := range []string{"Shipped +work", "Retro +team +work", "Old chores +home"} {
+		if !strings.Contains(string(gotDone), want) {
+			t.Errorf("done.txt = %q, want it to contain %q", gotDone, want)
+		}
+	}
+
+	// Purge, including done.txt, still only touches +work
+	config.Get().PurgeDoneFile = true
+	if exitCode := runClean([]string{"-p", "work", "--purge", "-y"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	gotTodo, _ = os.ReadFile(data.GetTodoFilePath())
+	if string(gotTodo) != "Pending +work\nx 2024-01-03 Groceries +home\n" {
+		t.Errorf("todo.txt = %q, want the +home done task kept", gotTodo)
+	}
+	gotDone, _ = os.ReadFile(data.GetDoneFilePath())
+	if string(gotDone) != "x 2023-12-02 Old chores +home\n" {
+		t.Errorf("done.txt = %q, want only the +home task left", gotDone)
+	}
+
+	if exitCode := runClean(nil, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 without --project, got %d", exitCode)
+	}
+}
+
+func TestRunCapture_KeepsLineVerbatim(t *testing.T) {
+	svc := setupTempService(t, "", "")
+	todoPath := data.GetTodoFilePath()
+
+	// Double space and a project before the text: add rewrites this line,
+	// capture must keep it exactly
+	line := "+inbox call dentist  re: crown"
+	if exitCode := runCapture([]string{line}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	got, _ := os.ReadFile(todoPath)
+	if string(got) != line+"\n" {
+		t.Fatalf("todo.txt = %q, want the line verbatim", got)
+	}
+
+	if exitCode := runAdd([]string{line}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	got, _ = os.ReadFile(todoPath)
+	added := strings.Split(strings.TrimSpace(string(got)), "\n")[1]
+	if added == line {
+		t.Errorf("expected add to normalize %q", line)
+	}
+
+	if exitCode := runCapture(nil, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 without text, got %d", exitCode)
+	}
+}
+
+func TestRunCapture_AddsCreatedDate(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	svc := setupTempService(t, "", "")
+	config.Get().AddCreatedDate = true
+
+	for _, line := range []string{"(B) triage  this", "2024-01-01 already dated"} {
+		if exitCode := runCapture([]string{line}, svc); exitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d", exitCode)
+		}
+	}
+	got, _ := os.ReadFile(data.GetTodoFilePath())
+	want := "(B) 2024-03-15 triage  this\n2024-01-01 already dated\n"
+	if string(got) != want {
+		t.Errorf("todo.txt = %q, want %q", got, want)
+	}
+}
+
+func TestRunDedupeProjects(t *testing.T) {
+	todo := "Buy milk +Errand\nPost office +errand\nBank +errand\nPlant seeds +garden\nReview +Work +work\n"
+	svc := setupTempService(t, todo, "")
+	todoPath := data.GetTodoFilePath()
+
+	if exitCode := runDedupe([]string{"--dry-run", "--distance", "0"}, svc, false); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	got, _ := os.ReadFile(todoPath)
+	if string(got) != todo {
+		t.Fatalf("dry run changed todo.txt: %q", got)
+	}
+
+	if exitCode := runDedupe([]string{"--yes", "--distance", "0"}, svc, false); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	got, _ = os.ReadFile(todoPath)
+	want := "Buy milk +errand\nPost office +errand\nBank +errand\nPlant seeds +garden\nReview +Work\n"
+	if string(got) != want {
+		t.Errorf("todo.txt = %q, want %q", got, want)
+	}
+}
+
+func TestRunRenameTag(t *testing.T) {
+	svc := setupTempService(t, "Write report est:2h\nReview PR est:30m +work\nBuy milk\n", "")
+	todoPath := data.GetTodoFilePath()
+
+	if exitCode := runRenameTag([]string{"est", "estimate"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	got, _ := os.ReadFile(todoPath)
+	want := "Write report estimate:2h\nReview PR +work estimate:30m\nBuy milk\n"
+	if string(got) != want {
+		t.Errorf("todo.txt = %q, want %q", got, want)
+	}
+
+	if exitCode := runRenameTag([]string{"estimate"}, svc); exitCode != 1 {
+		t.Errorf("missing argument: expected exit code 1, got %d", exitCode)
+	}
+}
+
+func TestRun_QuietAndVerbose(t *testing.T) {
+	svc := setupTempService(t, "", "")
+	todoPath := data.GetTodoFilePath()
+
+	out := captureStdout(t, func() {
+		if exitCode := Run([]string{"-q", "add", "Buy milk"}, svc); exitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d", exitCode)
+		}
+	})
+	if out != "" {
+		t.Errorf("quiet add printed %q, want nothing", out)
+	}
+	tasks, _ := svc.ListPending()
+	if len(tasks) != 1 {
+		t.Fatalf("expected the task to be added, got %d tasks", len(tasks))
+	}
+
+	out = captureStdout(t, func() {
+		Run([]string{"add", "Call mom", "--verbose"}, svc)
+	})
+	if !strings.Contains(out, "Added: Call mom") || !strings.Contains(out, todoPath) {
+		t.Errorf("verbose add = %q, want the task and %s", out, todoPath)
+	}
+
+	// Without the flags, output is unchanged and the flags don't stick
+	out = captureStdout(t, func() {
+		Run([]string{"add", "Pay rent"}, svc)
+	})
+	if !strings.Contains(out, "Added: Pay rent") || strings.Contains(out, todoPath) {
+		t.Errorf("default add = %q", out)
+	}
+
+	// "--" keeps a flag-like word in the task text
+	Run([]string{"-q", "add", "--", "Pick", "-v", "option"}, svc)
+	tasks, _ = svc.ListPending()
+	if tasks[len(tasks)-1].Name != "Pick -v option" {
+		t.Errorf("last task = %q", tasks[len(tasks)-1].Name)
+	}
+}
+
+// setupTempService returns a service over a fresh todo dir seeded with todo
+// and, unless it's empty, done
+func setupTempService(t *testing.T, todo, done string) service.TaskService {
+	t.Helper()
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "todo.txt"), []byte(todo), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+	if done != "" {
+		if err := os.WriteFile(filepath.Join(tmpDir, "done.txt"), []byte(done), 0644); err != nil {
+			t.Fatalf("Failed to write done.txt: %v", err)
+		}
+	}
+	config.Reset()
+	config.SetCLIFlags(config.CLIFlags{TodoDir: tmpDir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	svc, err := service.NewTaskService()
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	return svc
+}
+
+func TestRun_WarnsAboutCompletedTasksInTodo(t *testing.T) {
+	svc := setupTempService(t, "x 2024-03-01 Filed taxes\nPay rent\n", "")
+
+	stderr := captureStderr(t, func() {
+		captureStdout(t, func() { Run([]string{"list"}, svc) })
+	})
+	if !strings.Contains(stderr, "Warning: 1 completed task(s) in todo.txt") {
+		t.Errorf("stderr = %q, want the completed-in-todo warning", stderr)
+	}
+
+	stderr = captureStderr(t, func() {
+		captureStdout(t, func() { Run([]string{"--quiet", "list"}, svc) })
+	})
+	if stderr != "" {
+		t.Errorf("stderr = %q with --quiet, want nothing", stderr)
+	}
+
+	// Other commands leave the warning to list
+	stderr = captureStderr(t, func() {
+		captureStdout(t, func() { Run([]string{"add", "Call mom"}, svc) })
+	})
+	if stderr != "" {
+		t.Errorf("stderr = %q after add, want nothing", stderr)
+	}
+}
+
+func TestRunSplit(t *testing.T) {
+	svc := setupTempService(t, "Call mom\n(A) pack; book hotel; rent car +trip @home\n", "")
+	before, _ := svc.ListPending()
+	var id string
+	for _, task := range before {
+		if task.HasProject("trip") {
+			id = task.ID
+		}
+	}
+
+	if exitCode := runSplit([]string{id, ";"}, svc); exitCode != 0 {
+		t.Fatalf("Failed to split task, exit code: %d", exitCode)
+	}
+
+	pending, _ := svc.ListPending()
+	var names []string
+	for _, task := range pending {
+		if !task.HasProject("trip") {
+			continue
+		}
+		names = append(names, task.Name)
+		if task.Priority != data.PriorityA || !task.HasContext("home") {
+			t.Errorf("split task did not inherit metadata: %q", task.String())
+		}
+	}
+	want := []string{"pack", "book hotel", "rent car"}
+	if strings.Join(names, "|") != strings.Join(want, "|") {
+		t.Errorf("split names = %v, want %v", names, want)
+	}
+	if len(pending) != len(before)+2 {
+		t.Errorf("expected original to be replaced: %d pending before, %d after", len(before), len(pending))
+	}
+}
+
+func TestRunSplit_Complete(t *testing.T) {
+	svc := setupTempService(t, "wash; dry; fold +laundry\n", "")
+	tasks, _ := svc.ListPending()
+
+	if exitCode := runSplit([]string{"--complete", tasks[0].ID}, svc); exitCode != 0 {
+		t.Fatalf("Failed to split task, exit code: %d", exitCode)
+	}
+
+	all, _ := svc.List()
+	var done, pending int
+	for _, task := range all {
+		if task.Done {
+			done++
+			if task.Name != "wash; dry; fold" {
+				t.Errorf("completed original Name = %q", task.Name)
+			}
+		} else {
+			pending++
+		}
+	}
+	if done != 1 || pending != 3 {
+		t.Errorf("expected 1 completed original and 3 new tasks, got %d done, %d pending", done, pending)
+	}
+}
+
+func TestRunSplit_NoDelimiter(t *testing.T) {
+	svc := setupTempService(t, "Call mom\n", "")
+	tasks, _ := svc.ListPending()
+
+	if exitCode := runSplit([]string{tasks[0].ID}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 when the name has no delimiter, got %d", exitCode)
+	}
+}
+
+func TestRunAgenda(t *testing.T) {
+	svc := setupTempService(t, "Pay rent due:2024-03-01\nCall mom\nFile taxes due:2024-04-15\nBook flights due:2024-03-05\n", "")
+
+	var exitCode int
+	out := captureStdout(t, func() {
+		exitCode = runAgenda([]string{"--from", "2024-03-01", "--to", "2024-03-05"}, svc)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(out, "Pay rent") || !strings.Contains(out, "Book flights") {
+		t.Errorf("expected tasks due on both boundaries, got:\n%s", out)
+	}
+	if strings.Contains(out, "File taxes") || strings.Contains(out, "Call mom") {
+		t.Errorf("expected tasks outside the range or without a due date to be excluded, got:\n%s", out)
+	}
+	if strings.Index(out, "Pay rent") > strings.Index(out, "Book flights") {
+		t.Errorf("expected tasks sorted by due date, got:\n%s", out)
+	}
+}
+
+func TestRunAgenda_Week(t *testing.T) {
+	svc := setupTempService(t, "Water plants due:2024-03-03\nPay rent due:2024-03-04\nCall mom due:2024-03-10\n", "")
+	defer func(orig string) { config.Get().WeekStart = orig }(config.Get().WeekStart)
+
+	// 2024-03-06 is a Wednesday, 03-03 and 03-10 are Sundays
+	for _, tc := range []struct {
+		weekStart string
+		want      []string
+		notWant   string
+	}{
+		{"monday", []string{"Pay rent", "Call mom"}, "Water plants"},
+		{"sunday", []string{"Water plants", "Pay rent"}, "Call mom"},
+	} {
+		config.Get().WeekStart = tc.weekStart
+		out := captureStdout(t, func() {
+			if exitCode := runAgenda([]string{"--week", "--from", "2024-03-06"}, svc); exitCode != 0 {
+				t.Fatalf("Expected exit code 0, got %d", exitCode)
+			}
+		})
+		for _, want := range tc.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("week_start %s: expected %q, got:\n%s", tc.weekStart, want, out)
+			}
+		}
+		if strings.Contains(out, tc.notWant) {
+			t.Errorf("week_start %s: expected no %q, got:\n%s", tc.weekStart, tc.notWant, out)
+		}
+	}
+}
+
+func TestRunAgenda_InvalidRange(t *testing.T) {
+	svc := setupTempService(t, "", "")
+
+	if exitCode := runAgenda([]string{"--from", "2024-03-05", "--to", "2024-03-01"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for --to before --from, got %d", exitCode)
+	}
+	if exitCode := runAgenda([]string{"--from", "someday"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for an invalid date, got %d", exitCode)
+	}
+	if exitCode := runAgenda([]string{"--week", "--to", "2024-03-01"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for --week with --to, got %d", exitCode)
+	}
+}
+
+func TestRunExport_ICS(t *testing.T) {
+	svc := setupTestService(t, "complex")
+
+	var exitCode int
+	out := captureStdout(t, func() {
+		exitCode = runExport([]string{"--format", "ics"}, svc)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+
+	if !strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("expected a VCALENDAR with CRLF line endings, got:\n%s", out)
+	}
+	if n := strings.Count(out, "BEGIN:VTODO"); n != 2 {
+		t.Errorf("expected 2 VTODO entries for the due-dated tasks, got %d:\n%s", n, out)
+	}
+	for _, want := range []string{
+		"SUMMARY:Critical security patch\r\n",
+		"DUE;VALUE=DATE:20240125\r\n",
+		"CATEGORIES:backend,urgent\r\n",
+		"SUMMARY:Deploy to production\r\n",
+		"DUE;VALUE=DATE:20260123\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected ICS to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Write unit tests") {
+		t.Errorf("expected tasks without a due date to be skipped, got:\n%s", out)
+	}
+}
+
+func TestRunExport_Filters(t *testing.T) {
+	svc := setupTestService(t, "complex")
+
+	out := captureStdout(t, func() {
+		runExport([]string{"-p", "devops"}, svc)
+	})
+	if n := strings.Count(out, "BEGIN:VTODO"); n != 1 || !strings.Contains(out, "Deploy to production") {
+		t.Errorf("expected only the +devops task, got:\n%s", out)
+	}
+
+	if exitCode := runExport([]string{"--format", "csv"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for an unsupported format, got %d", exitCode)
+	}
+}
+
+func TestRunDefer(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	svc := setupTempService(t, "Pay rent due:2024-03-20\nCall mom\n", "")
+	dueOf := func(name string) string {
+		tasks, _ := svc.List()
+		for _, task := range tasks {
+			if task.Name == name {
+				return task.GetDueDate()
+			}
+		}
+		return ""
+	}
+	idOf := func(name string) string {
+		tasks, _ := svc.List()
+		for _, task := range tasks {
+			if task.Name == name {
+				return task.ID
+			}
+		}
+		return ""
+	}
+
+	if exitCode := runDefer([]string{idOf("Pay rent")}, svc); exitCode != 0 {
+		t.Fatalf("Failed to defer task, exit code: %d", exitCode)
+	}
+	if got := dueOf("Pay rent"); got != "2024-03-21" {
+		t.Errorf("Pay rent due = %q, want %q", got, "2024-03-21")
+	}
+
+	if exitCode := runDefer([]string{idOf("Call mom"), "3"}, svc); exitCode != 0 {
+		t.Fatalf("Failed to defer task, exit code: %d", exitCode)
+	}
+	if got := dueOf("Call mom"); got != "2024-03-18" {
+		t.Errorf("Call mom due = %q, want today+3 %q", got, "2024-03-18")
+	}
+
+	if exitCode := runDefer([]string{idOf("Call mom"), "soon"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for invalid days, got %d", exitCode)
+	}
+}
+
+func TestRunList_IDs(t *testing.T) {
+	svc := setupTempService(t, "Write report +work\nMow lawn +home\nx 2024-03-01 Filed taxes +work\nPlan sprint +work @office\n", "")
+	pending, _ := svc.ListPending()
+	var want []string
+	for _, task := range pending {
+		if task.HasProject("work") {
+			want = append(want, task.ID)
+		}
+	}
+
+	var exitCode int
+	output := captureStdout(t, func() {
+		exitCode = runList([]string{"-p", "work", "--ids"}, svc)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	if output != strings.Join(want, "\n")+"\n" {
+		t.Errorf("output = %q, want the IDs %v one per line", output, want)
+	}
+
+	if code := runList([]string{"--ids", "--format", "short"}, svc); code != 1 {
+		t.Errorf("Expected exit code 1 for --ids with --format, got %d", code)
+	}
+}
+
+func TestRunProjects_NoteDescription(t *testing.T) {
+	svc := setupTempService(t, "Write report +work\nx 2024-03-01 Filed taxes +work\nMow lawn +home\n", "")
+	projDir := config.Get().GetProjDir()
+	if err := os.MkdirAll(projDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	note := "\n# Quarterly client deliverables\n\nMore notes here.\n"
+	if err := os.WriteFile(filepath.Join(projDir, "work.md"), []byte(note), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	var exitCode int
+	output := captureStdout(t, func() {
+		exitCode = runProjects([]string{"--count"}, svc)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected two projects, got %q", output)
+	}
+	if !strings.HasPrefix(lines[0], "+home") || strings.Contains(lines[0], "Quarterly") {
+		t.Errorf("first line = %q, want +home without a description", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "+work") || !strings.Contains(lines[1], "1 pending, 1 done") ||
+		!strings.HasSuffix(lines[1], "Quarterly client deliverables") {
+		t.Errorf("second line = %q, want +work with counts and its note's first line", lines[1])
+	}
+}
+
+func TestRunList_Blocked(t *testing.T) {
+	svc := setupTempService(t, "Write report\nSign contract blocked:legal\nHear back @waiting\n", "")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, "Write report\n"},
+		{[]string{"--include-blocked"}, "Write report\nSign contract\nHear back\n"},
+		{[]string{"--blocked"}, "Sign contract\nHear back\n"},
+		{[]string{"--all"}, "Write report\nSign contract\nHear back\n"},
+	}
+
+	for _, tc := range tests {
+		var exitCode int
+		out := captureStdout(t, func() {
+			exitCode = runList(append(tc.args, "--format", "{{.Name}}"), svc)
+		})
+		if exitCode != 0 {
+			t.Errorf("%v: expected exit code 0, got %d", tc.args, exitCode)
+		}
+		if out != tc.expected {
+			t.Errorf("%v: output = %q, want %q", tc.args, out, tc.expected)
+		}
+	}
+}
+
+func TestRunList_DuePresence(t *testing.T) {
+	svc := setupTestService(t, "complex")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"--has-due"}, "Critical security patch\nDeploy to production\n"},
+		{[]string{"--no-due", "-p", "devops"}, "Set up monitoring alerts\n"},
+	}
+
+	for _, tc := range tests {
+		var exitCode int
+		out := captureStdout(t, func() {
+			exitCode = runList(append(tc.args, "--format", "{{.Name}}"), svc)
+		})
+		if exitCode != 0 {
+			t.Errorf("%v: expected exit code 0, got %d", tc.args, exitCode)
+		}
+		if out != tc.expected {
+			t.Errorf("%v: output = %q, want %q", tc.args, out, tc.expected)
+		}
+	}
+
+	if code := runList([]string{"--has-due", "--no-due"}, svc); code != 1 {
+		t.Errorf("--has-due with --no-due: expected exit code 1, got %d", code)
+	}
+}
+
+func TestRun_Aliases(t *testing.T) {
+	svc := setupTempService(t, "Write report +work\nMow lawn +home\n", "")
+	defer func(orig map[string]string) { config.Get().Aliases = orig }(config.Get().Aliases)
+	config.Get().Aliases = map[string]string{
+		"lw":    "ls -p work",
+		"ls":    "list",
+		"loop":  "again",
+		"again": "loop",
+	}
+
+	var exitCode int
+	output := captureStdout(t, func() {
+		exitCode = Run([]string{"lw", "--format", "{{.Name}}"}, svc)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	if output != "Write report\n" {
+		t.Errorf("output = %q, want only the +work task", output)
+	}
+
+	if exitCode := Run([]string{"loop"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for a cyclic alias, got %d", exitCode)
+	}
+	if _, _, err := resolveAlias("loop", nil, config.Get().Aliases); err == nil || !strings.Contains(err.Error(), "loop") {
+		t.Errorf("expected an error naming the looping alias, got %v", err)
+	}
+}
+
+func TestRunPrompt(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	svc := setupTempService(t, "Pay rent due:2024-03-01\nFile taxes due:2024-03-14\n"+
+		"Call mom due:2024-03-15\nBuy milk due:2024-03-15\nWater plants due:2024-03-15\n"+
+		"Plan trip due:2024-04-01\nx 2024-03-10 Old bill due:2024-03-02\nNo date\n", "")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{}, "⚑2 ⌛3\n"},
+		{[]string{"--format", "{{.Overdue}}!{{.Today}}"}, "2!3\n"},
+	}
+	for _, tc := range tests {
+		var exitCode int
+		out := captureStdout(t, func() {
+			exitCode = runPrompt(tc.args, svc)
+		})
+		if exitCode != 0 {
+			t.Errorf("%v: expected exit code 0, got %d", tc.args, exitCode)
+		}
+		if out != tc.expected {
+			t.Errorf("%v: output = %q, want %q", tc.args, out, tc.expected)
+		}
+	}
+
+	// Nothing urgent: no output at all
+	var b strings.Builder
+	if err := writePrompt(&b, promptCounts{}, nil); err != nil || b.String() != "" {
+		t.Errorf("writePrompt with no urgent tasks = %q, %v; want empty", b.String(), err)
+	}
+	b.Reset()
+	writePrompt(&b, promptCounts{Today: 1}, nil)
+	if b.String() != "⌛1\n" {
+		t.Errorf("writePrompt = %q, want only the due-today count", b.String())
+	}
+}
+
+func TestRunLog_Window(t *testing.T) {
+	svc := setupTempService(t, "x 2024-03-01 Before\nx 2024-03-02 First day\nx 2024-03-05 Last day\nx 2024-03-06 After\nx Undated\nStill pending\n", "")
+
+	var exitCode int
+	out := captureStdout(t, func() {
+		exitCode = runLog([]string{"--since", "2024-03-02", "--until", "2024-03-05", "--days", "1"}, svc)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	if !strings.Contains(out, "First day") || !strings.Contains(out, "Last day") {
+		t.Errorf("expected tasks completed on both boundaries, got:\n%s", out)
+	}
+	for _, excluded := range []string{"Before", "After", "Undated", "Still pending"} {
+		if strings.Contains(out, excluded) {
+			t.Errorf("expected %q to be excluded, got:\n%s", excluded, out)
+		}
+	}
+	if strings.Index(out, "Last day") > strings.Index(out, "First day") {
+		t.Errorf("expected the most recent completion first, got:\n%s", out)
+	}
+}
+
+func TestRunLog_InvalidRange(t *testing.T) {
+	svc := setupTempService(t, "", "")
+
+	if exitCode := runLog([]string{"--since", "2024-03-05", "--until", "2024-03-01"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for --until before --since, got %d", exitCode)
+	}
+	if exitCode := runLog([]string{"--since", "someday"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for an invalid date, got %d", exitCode)
+	}
+}
+
+func TestRunActionable(t *testing.T) {
+	svc := setupTempService(t, "Write report @desk\nCall bank\nx Done already @desk\nLater t:2999-01-01 @desk\nSign contract blocked:legal @desk\n", "")
+
+	run := func() string {
+		t.Helper()
+		var exitCode int
+		out := captureStdout(t, func() {
+			exitCode = runActionable([]string{"--format", "{{.Name}}"}, svc)
+		})
+		if exitCode != 0 {
+			t.Fatalf("Expected exit code 0, got %d", exitCode)
+		}
+		return out
+	}
+
+	if out, want := run(), "Write report\nCall bank\n"; out != want {
+		t.Errorf("default rules: output = %q, want %q", out, want)
+	}
+
+	config.Get().Actionable = []string{"pending", "has_context"}
+	if out, want := run(), "Write report\nLater\nSign contract\n"; out != want {
+		t.Errorf("custom rules: output = %q, want %q", out, want)
+	}
+}
+
+func TestRunKeys(t *testing.T) {
+	setupTempService(t, "", "")
+
+	var exitCode int
+	out := captureStdout(t, func() {
+		exitCode = Run([]string{"keys"}, nil)
+	})
+	if exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	for _, want := range []string{"Normal\n", "Filter (f)\n", "space/x", "toggle done", "  ?  ", "show this help"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	config.Get().DisableXToggle = true
+	out = captureStdout(t, func() {
+		exitCode = Run([]string{"keys"}, nil)
+	})
+	if strings.Contains(out, "space/x") || !strings.Contains(out, "  space  ") {
+		t.Errorf("expected disable_x_toggle to drop x from the toggle binding, got:\n%s", out)
+	}
+}
+
+func TestRunRestore(t *testing.T) {
+	svc := setupTempService(t, "Buy milk\nCall mom\n", "")
+	config.Get().Backups = 5
+
+	// Two rewrites, each backing up the files first. Completing moves a
+	// task to done.txt and renumbers the rest, so look the next one up.
+	for range 2 {
+		pending, _ := svc.ListPending()
+		if err := svc.Complete(pending[0].ID); err != nil {
+			t.Fatalf("Complete: %v", err)
+		}
+	}
+
+	backups, err := data.ListBackups()
+	if err != nil || len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %d (%v)", len(backups), err)
+	}
+	want, err := os.ReadFile(filepath.Join(backups[1].Dir, "todo.txt"))
+	if err != nil {
+		t.Fatalf("reading backup: %v", err)
+	}
+	if string(want) != "Buy milk\nCall mom\n" {
+		t.Fatalf("oldest backup = %q, want the original todo.txt", want)
+	}
+
+	// Listing, and an index out of range
+	if exitCode := runRestore([]string{}, svc); exitCode != 0 {
+		t.Errorf("Expected exit code 0 listing backups, got %d", exitCode)
+	}
+	if exitCode := runRestore([]string{"--index", "3", "--yes"}, svc); exitCode != 1 {
+		t.Errorf("Expected exit code 1 for a missing backup, got %d", exitCode)
+	}
+
+	if exitCode := runRestore([]string{"--index", "2", "--yes"}, svc); exitCode != 0 {
+		t.Fatalf("Expected exit code 0, got %d", exitCode)
+	}
+	got, _ := os.ReadFile(data.GetTodoFilePath())
+	if string(got) != string(want) {
+		t.Errorf("todo.txt = %q, want the backup's %q", got, want)
+	}
+	pending, _ := svc.ListPending()
+	if len(pending) != 2 {
+		t.Errorf("expected the service to see 2 pending tasks after restoring, got %d", len(pending))
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// runDedupe finds projects (or contexts) that look like typos of each other
+// and merges each group into its most-used name
+func runDedupe(args []string, svc service.TaskService, contexts bool) int {
+	name, sigil := "dedupe-projects", "+"
+	if contexts {
+		name, sigil = "dedupe-contexts", "@"
+	}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Show the groups and merges without changing anything")
+	yes := fs.Bool("yes", false, "Merge every group without asking")
+	fs.BoolVar(yes, "y", false, "Merge every group without asking (shorthand)")
+	distance := fs.Int("distance", 1, "Maximum edit distance between similar names (0: case only)")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	tasks, err := svc.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	names := func(t data.Task) []string {
+		if contexts {
+			return t.Contexts
+		}
+		return t.Projects
+	}
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		for _, n := range names(t) {
+			counts[n]++
+		}
+	}
+	var all []string
+	for n := range counts {
+		all = append(all, n)
+	}
+
+	groups := data.SimilarNames(all, *distance)
+	if len(groups) == 0 {
+		fmt.Println("No similar names found")
+		return 0
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	renames := make(map[string]string) // name -> the name it merges into
+	merged := 0
+	for _, group := range groups {
+		// Merge into the most-used name; ties go to the first alphabetically
+		sort.SliceStable(group, func(i, j int) bool { return counts[group[i]] > counts[group[j]] })
+		target := group[0]
+
+		fmt.Println("Similar:")
+		for _, n := range group {
+			fmt.Printf("  %s%s (%d task(s))\n", sigil, n, counts[n])
+		}
+		if *dryRun {
+			fmt.Printf("  would merge into %s%s\n", sigil, target)
+			continue
+		}
+		if !*yes && !confirm(in, fmt.Sprintf("Merge into %s%s?", sigil, target)) {
+			continue
+		}
+
+		for _, from := range group[1:] {
+			renames[from] = target
+		}
+		merged++
+	}
+
+	if *dryRun {
+		fmt.Printf("%d group(s) would be merged; run without --dry-run to merge\n", len(groups))
+		return 0
+	}
+	var changed []data.Task
+	for _, t := range tasks {
+		// Rename on a copy so the service's tasks only change through UpdateMany
+		t.Projects, t.Contexts = slices.Clone(t.Projects), slices.Clone(t.Contexts)
+		renamed := false
+		for _, from := range slices.Clone(names(t)) {
+			to, ok := renames[from]
+			if !ok {
+				continue
+			}
+			if contexts {
+				renamed = t.RenameContext(from, to) || renamed
+			} else {
+				renamed = t.RenameProject(from, to) || renamed
+			}
+		}
+		if renamed {
+			changed = append(changed, t)
+		}
+	}
+	if len(changed) > 0 {
+		if err := svc.UpdateMany(changed); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating tasks: %v\n", err)
+			return 1
+		}
+	}
+	fmt.Printf("Merged %d group(s), updating %d task(s)\n", merged, len(changed))
+	return 0
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runDefer(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("defer", flag.ContinueOnError)
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Error: task ID required")
+		fmt.Fprintln(os.Stderr, "Usage: wydo defer <task-id> [days]")
+		return 1
+	}
+
+	days := 1
+	if fs.NArg() > 1 {
+		n, err := strconv.Atoi(fs.Arg(1))
+		if err != nil || n < 1 {
+			fmt.Fprintf(os.Stderr, "Error: days must be a positive number, got %q\n", fs.Arg(1))
+			return 1
+		}
+		days = n
+	}
+
+	// Try to find the task first (supports partial ID matching)
+	task, err := findTaskByPartialID(svc, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+
+	task.Defer(days, data.Now())
+	if err := svc.Update(*task); err != nil {
+		fmt.Fprintf(os.Stderr, "Error deferring task: %v\n", err)
+		return exitCode(err)
+	}
+
+	fmt.Printf("Deferred to %s: %s\n", task.GetDueDate(), task.Name)
+	return 0
+}
@@ -20,15 +20,16 @@ func runDelete(args []string, svc service.TaskService) int {
 	task, err := findTaskByPartialID(svc, taskID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return 1
+		return exitCode(err)
 	}
 
 	err = svc.Delete(task.ID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error deleting task: %v\n", err)
-		return 1
+		return exitCode(err)
 	}
 
-	fmt.Printf("Deleted: %s\n", task.Name)
+	infof("Deleted: %s\n", task.Name)
+	verbosef("File: %s\n", task.File)
 	return 0
 }
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
@@ -9,33 +10,56 @@ import (
 )
 
 func runDone(args []string, svc service.TaskService) int {
-	if len(args) == 0 {
+	fs := flag.NewFlagSet("done", flag.ContinueOnError)
+	archive := fs.Bool("archive", false, "Move the completed task to done.txt")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(positional) == 0 {
 		fmt.Fprintln(os.Stderr, "Error: task ID required")
-		fmt.Fprintln(os.Stderr, "Usage: wydo done <task-id>")
+		fmt.Fprintln(os.Stderr, "Usage: wydo done <task-id> [--archive]")
 		return 1
 	}
 
-	taskID := args[0]
+	taskID := positional[0]
 
 	// Try to find the task first (supports partial ID matching)
 	task, err := findTaskByPartialID(svc, taskID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		return 1
+		return exitCode(err)
+	}
+
+	if *archive {
+		if task.Done && task.File == data.GetDoneFilePath() {
+			infof("Task already archived: %s\n", task.Name)
+			return 0
+		}
+		if err := svc.Complete(task.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Error completing task: %v\n", err)
+			return exitCode(err)
+		}
+		infof("Completed and archived: %s\n", task.Name)
+		verbosef("File: %s -> %s\n", task.File, data.GetDoneFilePath())
+		return 0
 	}
 
 	if task.Done {
-		fmt.Printf("Task already completed: %s\n", task.Name)
+		infof("Task already completed: %s\n", task.Name)
 		return 0
 	}
 
 	err = svc.Complete(task.ID)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error completing task: %v\n", err)
-		return 1
+		return exitCode(err)
 	}
 
-	fmt.Printf("Completed: %s\n", task.Name)
+	infof("Completed: %s\n", task.Name)
+	verbosef("File: %s -> %s\n", task.File, data.GetDoneFilePath())
 	return 0
 }
 
@@ -45,20 +69,5 @@ func findTaskByPartialID(svc service.TaskService, partialID string) (*data.Task,
 	if err != nil {
 		return nil, err
 	}
-
-	var matches []data.Task
-	for _, t := range tasks {
-		if t.ID == partialID || (len(partialID) >= 4 && len(t.ID) >= len(partialID) && t.ID[:len(partialID)] == partialID) {
-			matches = append(matches, t)
-		}
-	}
-
-	if len(matches) == 0 {
-		return nil, fmt.Errorf("no task found with ID: %s", partialID)
-	}
-	if len(matches) > 1 {
-		return nil, fmt.Errorf("multiple tasks match ID '%s', please be more specific", partialID)
-	}
-
-	return &matches[0], nil
+	return data.FindByPartialID(tasks, partialID)
 }
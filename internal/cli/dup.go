@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runDup(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("dup", flag.ContinueOnError)
+	suffix := fs.Bool("suffix", false, "Append \"(copy)\" to the duplicated task name")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	if fs.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Error: task ID required")
+		fmt.Fprintln(os.Stderr, "Usage: wydo dup [--suffix] <task-id>")
+		return 1
+	}
+
+	// Try to find the task first (supports partial ID matching)
+	task, err := findTaskByPartialID(svc, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+
+	dup := task.Duplicate(data.Today())
+	if *suffix {
+		dup.Name += " (copy)"
+	}
+
+	added, err := svc.Add(dup.String())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error duplicating task: %v\n", err)
+		return exitCode(err)
+	}
+
+	fmt.Printf("Duplicated: %s\n", added.String())
+	fmt.Printf("ID: %s\n", added.ID)
+	return 0
+}
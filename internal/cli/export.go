@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runExport(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "ics", "Export format: ics")
+	project := fs.String("p", "", "Filter by project")
+	context := fs.String("c", "", "Filter by context")
+	includeFuture := fs.Bool("include-future", false, "Include tasks whose threshold date (t:) is in the future")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	if *format != "ics" {
+		fmt.Fprintf(os.Stderr, "Error: unsupported export format %q (supported: ics)\n", *format)
+		return 1
+	}
+
+	tasks, err := svc.ListPending()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	// Same filters as list
+	if !*includeFuture {
+		tasks = filterOutFuture(tasks, data.Today())
+	}
+	if *project != "" {
+		tasks = filterByProject(tasks, *project)
+	}
+	if *context != "" {
+		tasks = filterByContext(tasks, *context)
+	}
+
+	writeICS(os.Stdout, tasks, data.Now())
+	return 0
+}
+
+// writeICS writes an iCalendar with one VTODO per task that has a valid due
+// date. Tasks without one are skipped. stamp is used as each DTSTAMP.
+func writeICS(w io.Writer, tasks []data.Task, stamp time.Time) {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//wydocli//wydo//EN",
+	}
+	for _, t := range tasks {
+		due := t.ValidDueDate()
+		if due == "" {
+			continue
+		}
+		lines = append(lines,
+			"BEGIN:VTODO",
+			"UID:"+t.ID+"@wydo",
+			"DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"),
+			"SUMMARY:"+escapeICSText(t.Name),
+			"DUE;VALUE=DATE:"+strings.ReplaceAll(due, "-", ""),
+		)
+		var categories []string
+		for _, p := range t.Projects {
+			categories = append(categories, escapeICSText(p))
+		}
+		for _, c := range t.Contexts {
+			categories = append(categories, escapeICSText(c))
+		}
+		if len(categories) > 0 {
+			lines = append(lines, "CATEGORIES:"+strings.Join(categories, ","))
+		}
+		if t.Priority != data.PriorityNone {
+			lines = append(lines, fmt.Sprintf("PRIORITY:%d", icsPriority(t.Priority)))
+		}
+		lines = append(lines, "END:VTODO")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	// iCalendar requires CRLF line endings
+	for _, l := range lines {
+		fmt.Fprint(w, l+"\r\n")
+	}
+}
+
+// escapeICSText escapes the characters RFC 5545 reserves in TEXT values
+func escapeICSText(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// icsPriority maps todo.txt priorities onto the iCalendar 1 (highest) to 9 scale
+func icsPriority(p data.Priority) int {
+	n := int(p-data.PriorityA) + 1
+	if n > 9 {
+		n = 9
+	}
+	return n
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/wyattlefevre/wydocli/internal/components"
+)
+
+func runKeys(args []string) int {
+	fs := flag.NewFlagSet("keys", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	writeKeys(os.Stdout, components.KeyBindingGroups())
+	return 0
+}
+
+// writeKeys prints each mode's bindings as an aligned two-column table
+func writeKeys(w io.Writer, groups []components.BindingGroup) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s\n", g.Mode)
+		for _, kb := range g.Bindings {
+			fmt.Fprintf(tw, "  %s\t%s\n", kb.Key, kb.Desc)
+		}
+	}
+	tw.Flush()
+}
@@ -3,7 +3,10 @@ package cli
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
+	"text/template"
 
 	"github.com/wyattlefevre/wydocli/internal/data"
 	"github.com/wyattlefevre/wydocli/internal/service"
@@ -14,11 +17,38 @@ func runList(args []string, svc service.TaskService) int {
 	project := fs.String("p", "", "Filter by project")
 	context := fs.String("c", "", "Filter by context")
 	showDone := fs.Bool("done", false, "Show only completed tasks")
-	showAll := fs.Bool("all", false, "Show all tasks including completed")
+	showAll := fs.Bool("all", false, "Show all tasks including completed, future and blocked")
+	includeFuture := fs.Bool("include-future", false, "Show tasks whose threshold date (t:) is in the future")
+	includeBlocked := fs.Bool("include-blocked", false, "Show blocked tasks (blocked: tag or @waiting)")
+	onlyBlocked := fs.Bool("blocked", false, "Show only blocked tasks (blocked: tag or @waiting)")
+	hasDue := fs.Bool("has-due", false, "Show only tasks with a due date")
+	noDue := fs.Bool("no-due", false, "Show only tasks without a due date")
+	format := fs.String("format", "", "Output template (Go text/template) or preset: short, oneline")
+	idsOnly := fs.Bool("ids", false, "Print only the full task IDs, one per line (for xargs)")
 
 	if err := fs.Parse(args); err != nil {
 		return 1
 	}
+	if *hasDue && *noDue {
+		fmt.Fprintln(os.Stderr, "Error: --has-due and --no-due cannot be used together")
+		return 1
+	}
+	if *idsOnly && *format != "" {
+		fmt.Fprintln(os.Stderr, "Error: --ids and --format cannot be used together")
+		return 1
+	}
+
+	var tmpl *template.Template
+	if *format != "" {
+		var err error
+		tmpl, err = parseListFormat(*format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid format: %v\n", err)
+			return 1
+		}
+	}
+
+	defer warnStrayDone(svc)
 
 	var tasks []data.Task
 	var err error
@@ -38,24 +68,53 @@ func runList(args []string, svc service.TaskService) int {
 	}
 
 	// Apply filters
+	if !*showAll && !*includeFuture {
+		tasks = filterOutFuture(tasks, data.Today())
+	}
+	if *onlyBlocked {
+		tasks = filterByBlocked(tasks, true)
+	} else if !*showAll && !*includeBlocked {
+		tasks = filterByBlocked(tasks, false)
+	}
 	if *project != "" {
 		tasks = filterByProject(tasks, *project)
 	}
 	if *context != "" {
 		tasks = filterByContext(tasks, *context)
 	}
+	if *hasDue || *noDue {
+		tasks = filterByDue(tasks, *hasDue)
+	}
+
+	// IDs only: full IDs so they stay unambiguous when piped to other commands
+	if *idsOnly {
+		for _, t := range tasks {
+			fmt.Println(t.ID)
+		}
+		return 0
+	}
+
+	// Custom format: print one rendered line per task, nothing else
+	if tmpl != nil {
+		if err := writeFormattedTasks(os.Stdout, tasks, tmpl); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		return 0
+	}
 
 	// Print tasks
 	if len(tasks) == 0 {
-		fmt.Println("No tasks found.")
+		infof("No tasks found.\n")
 		return 0
 	}
 
 	for _, t := range tasks {
 		printTask(t)
+		verbosef("        file: %s\n", t.File)
 	}
 
-	fmt.Printf("\n%d task(s)\n", len(tasks))
+	infof("\n%d task(s)\n", len(tasks))
 	return 0
 }
 
@@ -69,6 +128,39 @@ func filterByProject(tasks []data.Task, project string) []data.Task {
 	return filtered
 }
 
+// filterOutFuture drops tasks whose threshold date is after today
+func filterOutFuture(tasks []data.Task, today string) []data.Task {
+	var filtered []data.Task
+	for _, t := range tasks {
+		if !t.IsFuture(today) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// filterByBlocked keeps the tasks whose blocked state matches blocked
+func filterByBlocked(tasks []data.Task, blocked bool) []data.Task {
+	var filtered []data.Task
+	for _, t := range tasks {
+		if t.IsBlocked() == blocked {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// filterByDue keeps the tasks that have a due date, or those that don't
+func filterByDue(tasks []data.Task, hasDue bool) []data.Task {
+	var filtered []data.Task
+	for _, t := range tasks {
+		if (t.GetDueDate() != "") == hasDue {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
 func filterByContext(tasks []data.Task, context string) []data.Task {
 	var filtered []data.Task
 	for _, t := range tasks {
@@ -109,3 +201,55 @@ func printTask(t data.Task) {
 		fmt.Println()
 	}
 }
+
+// listFormatPresets are named templates accepted by --format
+var listFormatPresets = map[string]string{
+	"short":   "{{.ShortID}} {{.Name}}",
+	"oneline": "{{.ShortID}} {{.Line}}",
+}
+
+// templateTask is the value each --format template is executed against.
+// It embeds the task and adds display-friendly fields.
+type templateTask struct {
+	data.Task
+	Priority string // "A".."F" or empty
+	ShortID  string // first 7 characters of the ID
+	Due      string // due date tag value
+	Line     string // the task in todo.txt format
+}
+
+func newTemplateTask(t data.Task) templateTask {
+	tt := templateTask{
+		Task:    t,
+		ShortID: t.ID,
+		Due:     t.GetDueDate(),
+		Line:    t.String(),
+	}
+	if t.Priority != data.PriorityNone {
+		tt.Priority = string(t.Priority)
+	}
+	if len(t.ID) > 7 {
+		tt.ShortID = t.ID[:7]
+	}
+	return tt
+}
+
+// parseListFormat resolves a preset name or parses a custom template
+func parseListFormat(format string) (*template.Template, error) {
+	if preset, ok := listFormatPresets[format]; ok {
+		format = preset
+	}
+	return template.New("list").Parse(format)
+}
+
+// writeFormattedTasks renders each task with the template, one per line
+func writeFormattedTasks(w io.Writer, tasks []data.Task, tmpl *template.Template) error {
+	for _, t := range tasks {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, newTemplateTask(t)); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// defaultLogDays is how many days back the log goes without --days
+const defaultLogDays = 7
+
+func runLog(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	days := fs.Int("days", defaultLogDays, "Show tasks completed in the last N days, today included")
+	sinceFlag := fs.String("since", "", "First completion day to show, inclusive (overrides --days)")
+	untilFlag := fs.String("until", "", "Last completion day to show, inclusive (default today)")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	if *days < 1 {
+		fmt.Fprintln(os.Stderr, "Error: --days must be at least 1")
+		return 1
+	}
+
+	until := data.Now()
+	if *untilFlag != "" {
+		t, _, err := data.ParseFlexibleDate(*untilFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --until: %v\n", err)
+			return 1
+		}
+		until = t
+	}
+	since := until.AddDate(0, 0, 1-*days)
+	if *sinceFlag != "" {
+		t, _, err := data.ParseFlexibleDate(*sinceFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --since: %v\n", err)
+			return 1
+		}
+		since = t
+	}
+	if until.Before(since) {
+		fmt.Fprintln(os.Stderr, "Error: --until is before --since")
+		return 1
+	}
+
+	tasks, err := svc.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+	from, to := since.Format(data.DateFormat), until.Format(data.DateFormat)
+	tasks = completedBetween(tasks, from, to)
+
+	if len(tasks) == 0 {
+		fmt.Printf("Nothing completed from %s to %s.\n", from, to)
+		return 0
+	}
+
+	// One heading per day, most recent first
+	day := ""
+	for _, t := range tasks {
+		if t.CompletionDate != day {
+			if day != "" {
+				fmt.Println()
+			}
+			day = t.CompletionDate
+			fmt.Println(day)
+		}
+		printTask(t)
+	}
+
+	fmt.Printf("\n%d task(s)\n", len(tasks))
+	return 0
+}
+
+// completedBetween returns the done tasks completed from from to to
+// (yyyy-MM-dd, inclusive), most recently completed first
+func completedBetween(tasks []data.Task, from, to string) []data.Task {
+	var completed []data.Task
+	for _, t := range tasks {
+		if t.Done && t.CompletionDate != "" && t.CompletionDate >= from && t.CompletionDate <= to {
+			completed = append(completed, t)
+		}
+	}
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].CompletionDate > completed[j].CompletionDate
+	})
+	return completed
+}
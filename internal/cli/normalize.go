@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runNormalize(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("normalize", flag.ContinueOnError)
+	write := fs.Bool("write", false, "Write the normalized tasks back to disk")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	changed := 0
+	for _, path := range []string{data.GetTodoFilePath(), data.GetDoneFilePath()} {
+		lines, err := data.NormalizeFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+			return 1
+		}
+
+		n := writeNormalizeDiff(os.Stdout, path, lines)
+		if n == 0 {
+			continue
+		}
+		changed += n
+
+		if *write {
+			if err := data.WriteNormalized(path, lines); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+		}
+	}
+
+	switch {
+	case changed == 0:
+		fmt.Println("All tasks are already normalized")
+	case *write:
+		if err := svc.Reload(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reloading tasks: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Normalized %d line(s)\n", changed)
+	default:
+		fmt.Printf("%d line(s) would change; run with --write to apply\n", changed)
+	}
+	return 0
+}
+
+// writeNormalizeDiff prints a line-oriented diff of the changed lines in a
+// file and returns how many lines changed.
+func writeNormalizeDiff(w io.Writer, path string, lines []data.NormalizedLine) int {
+	changed := 0
+	for _, l := range lines {
+		if !l.Changed() {
+			continue
+		}
+		if changed == 0 {
+			fmt.Fprintf(w, "--- %s\n", path)
+		}
+		changed++
+		fmt.Fprintf(w, "-%d: %s\n", l.LineNum, l.Original)
+		if l.Normalized != "" {
+			fmt.Fprintf(w, "+%d: %s\n", l.LineNum, l.Normalized)
+		}
+	}
+	return changed
+}
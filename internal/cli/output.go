@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+)
+
+// Output verbosity, set by the global -q/--quiet and -v/--verbose flags
+var (
+	quietOutput   bool
+	verboseOutput bool
+)
+
+// parseGlobalFlags sets the output verbosity from the global flags and
+// returns args without them. Global flags may come before or after the
+// command; anything after "--" is left alone.
+func parseGlobalFlags(args []string) []string {
+	quietOutput, verboseOutput = false, false
+	var rest []string
+	for i, arg := range args {
+		switch arg {
+		case "-q", "--quiet":
+			quietOutput = true
+		case "-v", "--verbose":
+			verboseOutput = true
+		case "--":
+			return append(rest, args[i:]...)
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest
+}
+
+// infof prints progress messages that --quiet suppresses
+func infof(format string, a ...any) {
+	if !quietOutput {
+		fmt.Printf(format, a...)
+	}
+}
+
+// warnf prints a warning to stderr that --quiet suppresses
+func warnf(format string, a ...any) {
+	if !quietOutput {
+		fmt.Fprintf(os.Stderr, "Warning: "+format, a...)
+	}
+}
+
+// verbosef prints extra detail shown only with --verbose
+func verbosef(format string, a ...any) {
+	if verboseOutput && !quietOutput {
+		fmt.Printf(format, a...)
+	}
+}
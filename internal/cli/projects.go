@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runProjects(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("projects", flag.ContinueOnError)
+	count := fs.Bool("count", false, "Show each project's pending and done task counts")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	projects := svc.ProjectsSorted()
+	if len(projects) == 0 {
+		fmt.Println("No projects found.")
+		return 0
+	}
+
+	var tasks []data.Task
+	if *count {
+		var err error
+		tasks, err = svc.List()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+			return 1
+		}
+	}
+	writeProjects(os.Stdout, projects, tasks, *count)
+	return 0
+}
+
+// writeProjects prints one project per line, with its task counts when
+// count is set and the description from its note
+func writeProjects(w io.Writer, projects []data.Project, tasks []data.Task, count bool) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, project := range projects {
+		line := "+" + project.Name
+		if count {
+			todo, done := data.TaskCount(tasks, project.Name)
+			line += fmt.Sprintf("\t%d pending, %d done", todo, done)
+		}
+		if project.Description != "" {
+			line += "\t" + project.Description
+		}
+		fmt.Fprintln(tw, line)
+	}
+	tw.Flush()
+}
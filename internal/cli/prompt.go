@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// promptCounts is what the prompt --format template is executed against
+type promptCounts struct {
+	Overdue int // pending tasks due before today
+	Today   int // pending tasks due today
+}
+
+func runPrompt(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("prompt", flag.ContinueOnError)
+	format := fs.String("format", "", "Output template (Go text/template) with .Overdue and .Today")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	var tmpl *template.Template
+	if *format != "" {
+		var err error
+		tmpl, err = template.New("prompt").Parse(*format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid format: %v\n", err)
+			return 1
+		}
+	}
+
+	tasks, err := svc.ListPending()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	if err := writePrompt(os.Stdout, countUrgent(tasks, data.Today()), tmpl); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// countUrgent counts the tasks overdue and due on today (yyyy-MM-dd)
+func countUrgent(tasks []data.Task, today string) promptCounts {
+	var counts promptCounts
+	for _, t := range tasks {
+		due := t.ValidDueDate()
+		if t.Done || due == "" {
+			continue
+		}
+		if due < today {
+			counts.Overdue++
+		} else if due == today {
+			counts.Today++
+		}
+	}
+	return counts
+}
+
+// writePrompt prints the counts for a shell prompt: "⚑2 ⌛3" (leaving out
+// zero counts) or the rendered template, and nothing when nothing is urgent
+func writePrompt(w io.Writer, counts promptCounts, tmpl *template.Template) error {
+	if counts.Overdue == 0 && counts.Today == 0 {
+		return nil
+	}
+	if tmpl != nil {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, counts); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String(), "\n"))
+		return nil
+	}
+
+	var parts []string
+	if counts.Overdue > 0 {
+		parts = append(parts, fmt.Sprintf("⚑%d", counts.Overdue))
+	}
+	if counts.Today > 0 {
+		parts = append(parts, fmt.Sprintf("⌛%d", counts.Today))
+	}
+	fmt.Fprintln(w, strings.Join(parts, " "))
+	return nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runPurge(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	fs.BoolVar(yes, "y", false, "Don't ask for confirmation (shorthand)")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	tasks, err := svc.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	includeDoneFile := config.Get().GetPurgeDoneFile()
+	count := len(tasks) - len(data.PurgeDone(tasks, includeDoneFile))
+	if count == 0 {
+		fmt.Println("No completed tasks to purge.")
+		return 0
+	}
+
+	if !*yes {
+		where := "todo.txt"
+		if includeDoneFile {
+			where = "todo.txt and done.txt"
+		}
+		prompt := fmt.Sprintf("Permanently delete %d completed task(s) from %s?", count, where)
+		if !confirm(os.Stdin, prompt) {
+			fmt.Println("Aborted.")
+			return 1
+		}
+	}
+
+	if err := svc.PurgeDone(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error purging tasks: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Purged %d completed task(s)\n", count)
+	return 0
+}
+
+// confirm asks a yes/no question on stdout and reads the answer from r.
+// Anything other than y/yes counts as no.
+func confirm(r io.Reader, prompt string) bool {
+	fmt.Printf("%s [y/N] ", prompt)
+	answer, _ := bufio.NewReader(r).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// runRenameTag renames a tag key on every task, e.g. est: to estimate:
+func runRenameTag(args []string, svc service.TaskService) int {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: wydo rename-tag <old-key> <new-key>")
+		return 1
+	}
+	oldKey, newKey := args[0], args[1]
+	if oldKey == newKey {
+		fmt.Fprintln(os.Stderr, "Error: the old and new keys are the same")
+		return 1
+	}
+
+	tasks, err := svc.List()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+	count := 0
+	for _, t := range tasks {
+		if _, ok := t.Tags[oldKey]; ok {
+			count++
+		}
+	}
+	if count == 0 {
+		fmt.Printf("No tasks have a %s: tag\n", oldKey)
+		return 0
+	}
+
+	if err := svc.RenameTagKey(oldKey, newKey); err != nil {
+		fmt.Fprintf(os.Stderr, "Error renaming tag: %v\n", err)
+		return exitCode(err)
+	}
+	fmt.Printf("Renamed %s: to %s: on %d task(s)\n", oldKey, newKey, count)
+	return 0
+}
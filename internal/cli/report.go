@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// timeReportRow sums the est: and spent: tags of one project's tasks
+type timeReportRow struct {
+	Project  string // empty for tasks without a project
+	Estimate int    // minutes
+	Spent    int    // minutes
+	Tasks    int    // tasks with an est: or spent: tag
+}
+
+func runReport(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	pendingOnly := fs.Bool("pending", false, "Only include pending tasks")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	var tasks []data.Task
+	var err error
+	if *pendingOnly {
+		tasks, err = svc.ListPending()
+	} else {
+		tasks, err = svc.List()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tasks: %v\n", err)
+		return 1
+	}
+
+	stats, err := svc.Stats()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing stats: %v\n", err)
+		return 1
+	}
+	writeStatsSummary(os.Stdout, stats)
+	fmt.Println()
+
+	rows, total := buildTimeReport(tasks)
+	if len(rows) == 0 {
+		fmt.Println("No tasks with est: or spent: tags.")
+		return 0
+	}
+	writeTimeReport(os.Stdout, rows, total)
+	return 0
+}
+
+// buildTimeReport totals estimates and time spent per project, sorted by
+// project name with project-less tasks last. A task in several projects
+// counts toward each of them, but only once toward the overall total.
+func buildTimeReport(tasks []data.Task) ([]timeReportRow, timeReportRow) {
+	byProject := make(map[string]*timeReportRow)
+	var total timeReportRow
+	for _, t := range tasks {
+		est, hasEst := t.GetEstimate()
+		spent, hasSpent := t.GetSpent()
+		if !hasEst && !hasSpent {
+			continue
+		}
+		total.Estimate += est
+		total.Spent += spent
+		total.Tasks++
+
+		projects := t.Projects
+		if len(projects) == 0 {
+			projects = []string{""}
+		}
+		for _, p := range projects {
+			row, ok := byProject[p]
+			if !ok {
+				row = &timeReportRow{Project: p}
+				byProject[p] = row
+			}
+			row.Estimate += est
+			row.Spent += spent
+			row.Tasks++
+		}
+	}
+
+	rows := make([]timeReportRow, 0, len(byProject))
+	for _, row := range byProject {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if (rows[i].Project == "") != (rows[j].Project == "") {
+			return rows[j].Project == ""
+		}
+		return rows[i].Project < rows[j].Project
+	})
+	return rows, total
+}
+
+// writeStatsSummary prints the overall task counts on one line
+func writeStatsSummary(w io.Writer, stats service.ServiceStats) {
+	fmt.Fprintf(w, "%d task(s): %d pending (%d overdue), %d done\n", stats.Total, stats.Pending, stats.Overdue, stats.Done)
+}
+
+// writeTimeReport prints the report as an aligned table with a total row
+func writeTimeReport(w io.Writer, rows []timeReportRow, total timeReportRow) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tSPENT\tEST\tTASKS")
+
+	for _, row := range rows {
+		name := "(none)"
+		if row.Project != "" {
+			name = "+" + row.Project
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", name, data.FormatMinutes(row.Spent), data.FormatMinutes(row.Estimate), row.Tasks)
+	}
+	fmt.Fprintf(tw, "Total\t%s\t%s\t%d\n", data.FormatMinutes(total.Spent), data.FormatMinutes(total.Estimate), total.Tasks)
+	tw.Flush()
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// backupTimeFormat shows when each backup was taken
+const backupTimeFormat = "2006-01-02 15:04:05"
+
+func runRestore(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	index := fs.Int("index", 0, "Restore the Nth most recent backup (1 is the latest)")
+	yes := fs.Bool("yes", false, "Don't ask for confirmation")
+	fs.BoolVar(yes, "y", false, "Don't ask for confirmation (shorthand)")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	backups, err := data.ListBackups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing backups: %v\n", err)
+		return 1
+	}
+	if len(backups) == 0 {
+		if config.Get().GetBackups() == 0 {
+			fmt.Println("No backups. Set \"backups\" in the config file to keep some.")
+		} else {
+			fmt.Println("No backups yet.")
+		}
+		return 0
+	}
+
+	if *index == 0 {
+		for i, b := range backups {
+			fmt.Printf("%3d  %s\n", i+1, b.Time.Format(backupTimeFormat))
+		}
+		fmt.Println("\nRestore one with: wydo restore --index N")
+		return 0
+	}
+	if *index < 1 || *index > len(backups) {
+		fmt.Fprintf(os.Stderr, "Error: --index must be from 1 to %d\n", len(backups))
+		return 1
+	}
+
+	backup := backups[*index-1]
+	if !*yes {
+		prompt := fmt.Sprintf("Replace todo.txt and done.txt with the backup from %s?", backup.Time.Format(backupTimeFormat))
+		if !confirm(os.Stdin, prompt) {
+			fmt.Println("Aborted.")
+			return 1
+		}
+	}
+
+	if err := data.RestoreBackup(backup); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := svc.Reload(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reloading tasks: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("Restored the backup from %s\n", backup.Time.Format(backupTimeFormat))
+	return 0
+}
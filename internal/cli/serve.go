@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/server"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func runServe(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", "127.0.0.1:8080", "Address to listen on")
+	allowRemoteWrites := fs.Bool("allow-remote-writes", false, "Allow add/complete/delete when not bound to localhost")
+
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	allowWrites := server.IsLoopback(*addr) || *allowRemoteWrites
+	if !allowWrites {
+		fmt.Fprintf(os.Stderr, "Warning: %s is reachable from other hosts, serving read-only (use --allow-remote-writes to change)\n", *addr)
+	}
+
+	fmt.Printf("Serving tasks on http://%s\n", *addr)
+	if err := http.ListenAndServe(*addr, server.New(svc, *addr, allowWrites).Handler()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+// defaultSplitDelimiter separates subtasks when no delimiter is given
+const defaultSplitDelimiter = ";"
+
+func runSplit(args []string, svc service.TaskService) int {
+	fs := flag.NewFlagSet("split", flag.ContinueOnError)
+	complete := fs.Bool("complete", false, "Mark the original task done instead of deleting it")
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return 1
+	}
+
+	if len(positional) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: task ID required")
+		fmt.Fprintln(os.Stderr, "Usage: wydo split [--complete] <task-id> [delimiter]")
+		return 1
+	}
+
+	sep := defaultSplitDelimiter
+	if len(positional) > 1 {
+		sep = positional[1]
+	}
+
+	// Try to find the task first (supports partial ID matching)
+	task, err := findTaskByPartialID(svc, positional[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+
+	parts, err := svc.Split(task.ID, sep, *complete)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error splitting task: %v\n", err)
+		return exitCode(err)
+	}
+
+	fmt.Printf("Split into %d task(s):\n", len(parts))
+	for _, p := range parts {
+		fmt.Printf("  %s\n", p.String())
+	}
+	return 0
+}
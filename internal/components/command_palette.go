@@ -0,0 +1,127 @@
+package components
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteCommand is one entry in the command palette
+type paletteCommand struct {
+	Label string
+	Run   func(m *TaskManagerModel) (tea.Model, tea.Cmd)
+}
+
+// paletteSorts and paletteGroups name the fields offered in the palette
+var (
+	paletteSorts = []struct {
+		name  string
+		field SortField
+	}{
+		{"due", SortByDueDate}, {"priority", SortByPriority}, {"project", SortByProject},
+		{"context", SortByContext}, {"name", SortByName},
+	}
+	paletteGroups = []struct {
+		name  string
+		field GroupField
+	}{
+		{"due", GroupByDueDate}, {"priority", GroupByPriority}, {"project", GroupByProject},
+		{"context", GroupByContext}, {"file", GroupByFile},
+	}
+)
+
+// paletteCommands lists everything the palette can run: filters for each
+// known project and context, sorting, grouping and the task list actions
+func (m *TaskManagerModel) paletteCommands() []paletteCommand {
+	var cmds []paletteCommand
+	for _, p := range m.allProjects {
+		cmds = append(cmds, paletteCommand{"Filter by project: " + p, func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.filterState.ProjectFilter = []string{p}
+			m.refreshDisplayTasks()
+			return m, nil
+		}})
+	}
+	for _, c := range m.allContexts {
+		cmds = append(cmds, paletteCommand{"Filter by context: " + c, func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.filterState.ContextFilter = []string{c}
+			m.refreshDisplayTasks()
+			return m, nil
+		}})
+	}
+	cmds = append(cmds,
+		paletteCommand{"Filter: has due date", func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.filterState.DateFilter = &DateFilter{Mode: DatePresent}
+			m.refreshDisplayTasks()
+			return m, nil
+		}},
+		paletteCommand{"Filter: no due date", func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.filterState.DateFilter = &DateFilter{Mode: DateMissing}
+			m.refreshDisplayTasks()
+			return m, nil
+		}},
+	)
+	for _, s := range paletteSorts {
+		cmds = append(cmds, paletteCommand{"Sort by " + s.name, func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.sortState.Field = s.field
+			m.sortState.Ascending = true
+			m.refreshDisplayTasks()
+			return m, nil
+		}})
+	}
+	for _, g := range paletteGroups {
+		cmds = append(cmds, paletteCommand{"Group by " + g.name, func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.groupState.Field = g.field
+			m.groupState.Ascending = true
+			m.refreshDisplayTasks()
+			return m, nil
+		}})
+	}
+	cmds = append(cmds,
+		paletteCommand{"Clear search", func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.clearSearch()
+			return m, nil
+		}},
+		paletteCommand{"Clear filters, sort and grouping", func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.clearAllFilters()
+			return m, nil
+		}},
+		paletteCommand{"Show/hide future tasks", func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.toggleFuture()
+			return m, nil
+		}},
+		paletteCommand{"New task", (*TaskManagerModel).startNewTask},
+		paletteCommand{"Archive done", (*TaskManagerModel).handleStartArchive},
+		paletteCommand{"Purge done", (*TaskManagerModel).handleStartPurge},
+		paletteCommand{"Show help", func(m *TaskManagerModel) (tea.Model, tea.Cmd) {
+			m.showHelp = true
+			return m, nil
+		}},
+	)
+	return cmds
+}
+
+// startCommandPalette opens a picker of every palette command
+func (m *TaskManagerModel) startCommandPalette() (tea.Model, tea.Cmd) {
+	m.palette = m.paletteCommands()
+	labels := make([]string, len(m.palette))
+	for i, c := range m.palette {
+		labels[i] = c.Label
+	}
+	m.fuzzyPicker = m.newPicker(labels, "Command Palette", false, false)
+	m.pickerContext = "palette"
+	m.inputContext.TransitionTo(ModeFuzzyPicker)
+	return m, nil
+}
+
+// runPaletteCommand runs the command the palette picked, if any
+func (m *TaskManagerModel) runPaletteCommand(selected []string) (tea.Model, tea.Cmd) {
+	palette := m.palette
+	m.palette = nil
+	if len(selected) == 0 {
+		return m, nil
+	}
+	for _, c := range palette {
+		if c.Label == selected[0] {
+			return c.Run(m)
+		}
+	}
+	return m, nil
+}
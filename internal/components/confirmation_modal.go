@@ -20,12 +20,15 @@ type ConfirmationModal struct {
 	Message string // Primary question (e.g., "Archive 5 completed tasks?")
 	Details string // Additional context (optional)
 	Width   int    // Modal width
+	// AllowDiscard adds a [d] Discard answer, making it save/discard/cancel
+	AllowDiscard bool
 }
 
 // ConfirmationResultMsg is sent when the user confirms or cancels
 type ConfirmationResultMsg struct {
 	Confirmed bool
 	Cancelled bool
+	Discarded bool // [d] was chosen (only with AllowDiscard)
 }
 
 // NewConfirmationModal creates a new confirmation modal
@@ -47,6 +50,12 @@ func (m *ConfirmationModal) Update(msg tea.KeyMsg) tea.Cmd {
 				Cancelled: false,
 			}
 		}
+	case "d":
+		if m.AllowDiscard {
+			return func() tea.Msg {
+				return ConfirmationResultMsg{Discarded: true}
+			}
+		}
 	case "n", "esc":
 		return func() tea.Msg {
 			return ConfirmationResultMsg{
@@ -72,8 +81,14 @@ func (m *ConfirmationModal) View() string {
 
 	// Prompt
 	content += "\n"
-	content += confirmYesStyle.Render("[y]") + " Yes  "
-	content += confirmNoStyle.Render("[n/esc]") + " No"
+	if m.AllowDiscard {
+		content += confirmYesStyle.Render("[y]") + " Save  "
+		content += confirmNoStyle.Render("[d]") + " Discard  "
+		content += "[n/esc] Cancel"
+	} else {
+		content += confirmYesStyle.Render("[y]") + " Yes  "
+		content += confirmNoStyle.Render("[n/esc]") + " No"
+	}
 
 	return confirmModalBoxStyle.Width(m.Width).Render(content)
 }
@@ -0,0 +1,49 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+// renderFileStatus renders the overlay listing the resolved todo, done and
+// project paths, with how many of tasks came from each file. Files other
+// than todo.txt and done.txt that tasks were loaded from are listed too.
+func renderFileStatus(tasks []data.Task, width int) string {
+	counts := make(map[string]int)
+	for _, t := range tasks {
+		counts[t.File]++
+	}
+
+	todo, done := data.GetTodoFilePath(), data.GetDoneFilePath()
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Files"))
+	b.WriteString("\n\n")
+	b.WriteString(helpKeyStyle.Render("todo") + fileCountLine(todo, counts[todo]) + "\n")
+	b.WriteString(helpKeyStyle.Render("done") + fileCountLine(done, counts[done]) + "\n")
+	b.WriteString(helpKeyStyle.Render("projects") + config.Get().GetProjDir() + "\n")
+
+	var others []string
+	for file := range counts {
+		if file != todo && file != done {
+			others = append(others, file)
+		}
+	}
+	sort.Strings(others)
+	for _, file := range others {
+		b.WriteString(helpKeyStyle.Render("other") + fileCountLine(file, counts[file]) + "\n")
+	}
+
+	b.WriteString("\n" + renderHintFooter([]string{"press any key to close"}, width, 0, 1))
+	return b.String()
+}
+
+func fileCountLine(path string, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%s  (1 task)", path)
+	}
+	return fmt.Sprintf("%s  (%d tasks)", path, count)
+}
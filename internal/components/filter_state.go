@@ -4,6 +4,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
 )
 
@@ -16,6 +17,15 @@ const (
 	StatusDone
 )
 
+// BlockedFilter represents filtering by blocked state (data.Task.IsBlocked)
+type BlockedFilter int
+
+const (
+	BlockedAny BlockedFilter = iota
+	BlockedOnly
+	BlockedHidden
+)
+
 // DateFilterMode represents how to compare dates
 type DateFilterMode int
 
@@ -25,6 +35,7 @@ const (
 	DateOn
 	DateAfter
 	DateMissing
+	DatePresent
 )
 
 // DateFilter holds date filtering configuration
@@ -37,11 +48,14 @@ type DateFilter struct {
 type FilterState struct {
 	SearchQuery    string
 	StatusFilter   StatusFilter
+	BlockedFilter  BlockedFilter
+	Actionable     bool // only tasks meeting the actionable config's rules
 	DateFilter     *DateFilter
 	ProjectFilter  []string
 	ContextFilter  []string
 	PriorityFilter []data.Priority
 	FileFilter     []string
+	TagFilter      []string // "key:value" entries
 }
 
 // NewFilterState creates a new empty filter state
@@ -55,22 +69,28 @@ func NewFilterState() FilterState {
 func (f *FilterState) IsEmpty() bool {
 	return f.SearchQuery == "" &&
 		f.StatusFilter == StatusAll &&
+		f.BlockedFilter == BlockedAny &&
+		!f.Actionable &&
 		f.DateFilter == nil &&
 		len(f.ProjectFilter) == 0 &&
 		len(f.ContextFilter) == 0 &&
 		len(f.PriorityFilter) == 0 &&
-		len(f.FileFilter) == 0
+		len(f.FileFilter) == 0 &&
+		len(f.TagFilter) == 0
 }
 
 // Reset clears all filters
 func (f *FilterState) Reset() {
 	f.SearchQuery = ""
 	f.StatusFilter = StatusAll
+	f.BlockedFilter = BlockedAny
+	f.Actionable = false
 	f.DateFilter = nil
 	f.ProjectFilter = nil
 	f.ContextFilter = nil
 	f.PriorityFilter = nil
 	f.FileFilter = nil
+	f.TagFilter = nil
 }
 
 // CycleStatusFilter cycles through status filter options
@@ -85,6 +105,29 @@ func (f *FilterState) CycleStatusFilter() {
 	}
 }
 
+// CycleBlockedFilter cycles through showing all tasks, only blocked ones,
+// and only unblocked ones
+func (f *FilterState) CycleBlockedFilter() {
+	switch f.BlockedFilter {
+	case BlockedAny:
+		f.BlockedFilter = BlockedOnly
+	case BlockedOnly:
+		f.BlockedFilter = BlockedHidden
+	case BlockedHidden:
+		f.BlockedFilter = BlockedAny
+	}
+}
+
+// TogglePendingDone flips the status filter between pending and done,
+// starting from pending when no status filter is set
+func (f *FilterState) TogglePendingDone() {
+	if f.StatusFilter == StatusPending {
+		f.StatusFilter = StatusDone
+	} else {
+		f.StatusFilter = StatusPending
+	}
+}
+
 // ApplyFilters applies all active filters to a task list
 func ApplyFilters(tasks []data.Task, state FilterState) []data.Task {
 	if state.IsEmpty() {
@@ -120,6 +163,23 @@ func matchesFilters(task data.Task, state FilterState) bool {
 		}
 	}
 
+	// Blocked filter
+	switch state.BlockedFilter {
+	case BlockedOnly:
+		if !task.IsBlocked() {
+			return false
+		}
+	case BlockedHidden:
+		if task.IsBlocked() {
+			return false
+		}
+	}
+
+	// Actionable filter
+	if state.Actionable && !task.IsActionable(config.Get().GetActionable(), data.Today()) {
+		return false
+	}
+
 	// Date filter
 	if state.DateFilter != nil {
 		if !matchesDateFilter(task, state.DateFilter) {
@@ -155,6 +215,13 @@ func matchesFilters(task data.Task, state FilterState) bool {
 		}
 	}
 
+	// Tag filter (task must have at least one matching key:value tag)
+	if len(state.TagFilter) > 0 {
+		if !matchesAnyTag(task, state.TagFilter) {
+			return false
+		}
+	}
+
 	return true
 }
 
@@ -179,15 +246,18 @@ func fuzzyMatch(s, pattern string) bool {
 func matchesDateFilter(task data.Task, filter *DateFilter) bool {
 	dueDate := task.GetDueDate()
 
-	if filter.Mode == DateMissing {
+	switch filter.Mode {
+	case DateMissing:
 		return dueDate == ""
+	case DatePresent:
+		return dueDate != ""
 	}
 
 	if dueDate == "" {
 		return false
 	}
 
-	taskDate, err := time.Parse("2006-01-02", dueDate)
+	taskDate, _, err := data.ParseFlexibleDate(dueDate)
 	if err != nil {
 		return false
 	}
@@ -242,6 +312,19 @@ func matchesFile(task data.Task, files []string) bool {
 	return false
 }
 
+func matchesAnyTag(task data.Task, tags []string) bool {
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			continue
+		}
+		if v, exists := task.Tags[key]; exists && v == value {
+			return true
+		}
+	}
+	return false
+}
+
 // StatusFilterString returns a display string for the status filter
 func (f *FilterState) StatusFilterString() string {
 	switch f.StatusFilter {
@@ -262,6 +345,17 @@ func (f *FilterState) Summary() string {
 		parts = append(parts, "status="+f.StatusFilterString())
 	}
 
+	switch f.BlockedFilter {
+	case BlockedOnly:
+		parts = append(parts, "blocked")
+	case BlockedHidden:
+		parts = append(parts, "unblocked")
+	}
+
+	if f.Actionable {
+		parts = append(parts, "actionable")
+	}
+
 	if len(f.ProjectFilter) > 0 {
 		parts = append(parts, "project="+strings.Join(f.ProjectFilter, ","))
 	}
@@ -289,11 +383,13 @@ func (f *FilterState) Summary() string {
 			mode = "after"
 		case DateMissing:
 			mode = "missing"
+		case DatePresent:
+			mode = "present"
 		}
-		if f.DateFilter.Mode == DateMissing {
+		if f.DateFilter.Mode == DateMissing || f.DateFilter.Mode == DatePresent {
 			parts = append(parts, "due:"+mode)
 		} else {
-			parts = append(parts, "due:"+mode+" "+f.DateFilter.Date.Format("2006-01-02"))
+			parts = append(parts, "due:"+mode+" "+data.FormatDisplayDate(f.DateFilter.Date.Format(data.DateFormat)))
 		}
 	}
 
@@ -301,5 +397,9 @@ func (f *FilterState) Summary() string {
 		parts = append(parts, "file="+strings.Join(f.FileFilter, ","))
 	}
 
+	if len(f.TagFilter) > 0 {
+		parts = append(parts, "tag="+strings.Join(f.TagFilter, ","))
+	}
+
 	return strings.Join(parts, " | ")
 }
@@ -0,0 +1,88 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// hintSeparator separates "key:action" hints on a footer line
+const hintSeparator = "  "
+
+// maxHintLines caps how many lines the info bar's hints may wrap onto
+const maxHintLines = 3
+
+// splitHints splits a hint string such as "n:new  f:filter" into its hints
+func splitHints(hints string) []string {
+	var out []string
+	for _, h := range strings.Split(hints, hintSeparator) {
+		if h = strings.TrimSpace(h); h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// wrapHints lays hints out on lines at most width columns wide, never
+// splitting a hint across lines. The first line has indent columns fewer to
+// fill and later lines are padded by indent so the hints line up. When the
+// hints need more than maxLines lines, the last line is cut short with "…".
+// A width of 0 or less disables wrapping.
+func wrapHints(hints []string, width, indent, maxLines int) []string {
+	if len(hints) == 0 {
+		return nil
+	}
+	if width <= 0 {
+		return []string{strings.Join(hints, hintSeparator)}
+	}
+	avail := max(width-indent, 1)
+
+	var lines []string
+	var cur string
+	for _, h := range hints {
+		h = truncateHint(h, avail)
+		switch {
+		case cur == "":
+			cur = h
+		case lipgloss.Width(cur+hintSeparator+h) <= avail:
+			cur += hintSeparator + h
+		default:
+			lines = append(lines, cur)
+			cur = h
+		}
+	}
+	lines = append(lines, cur)
+
+	if maxLines > 0 && len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncateHint(lines[maxLines-1]+hintSeparator+"…", avail)
+	}
+
+	pad := strings.Repeat(" ", indent)
+	for i := 1; i < len(lines); i++ {
+		lines[i] = pad + lines[i]
+	}
+	return lines
+}
+
+// truncateHint shortens s to at most width columns, ending it with "…"
+// when anything was cut
+func truncateHint(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// renderHintFooter renders hints wrapped to width in the hint style
+func renderHintFooter(hints []string, width, indent, maxLines int) string {
+	lines := wrapHints(hints, width, indent, maxLines)
+	for i, l := range lines {
+		lines[i] = hintStyle.Render(l)
+	}
+	return strings.Join(lines, "\n")
+}
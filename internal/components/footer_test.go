@@ -0,0 +1,45 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestWrapHints(t *testing.T) {
+	hints := splitHints("n:new  f:filter  s:sort  g:group  /:search")
+
+	if got := wrapHints(hints, 0, 0, 0); len(got) != 1 {
+		t.Errorf("width 0: got %d lines, want 1", len(got))
+	}
+
+	got := wrapHints(hints, 18, 2, 0)
+	want := []string{"n:new  f:filter", "  s:sort  g:group", "  /:search"}
+	if !slicesEqual(got, want) {
+		t.Errorf("wrapHints = %q, want %q", got, want)
+	}
+
+	got = wrapHints(hints, 18, 2, 2)
+	if len(got) != 2 || !strings.HasSuffix(got[1], "…") {
+		t.Errorf("maxLines 2: got %q, want 2 lines ending in …", got)
+	}
+}
+
+func TestInfoBar_HintsWrapOnNarrowWidth(t *testing.T) {
+	bar := NewInfoBar()
+	bar.Width = 40
+	ctx := NewInputModeContext()
+	bar.InputContext = &ctx
+
+	out := bar.renderModeLine()
+	lines := strings.Split(out, "\n")
+	if len(lines) < 2 || len(lines) > maxHintLines {
+		t.Fatalf("expected hints to wrap onto 2..%d lines, got %d:\n%s", maxHintLines, len(lines), out)
+	}
+	for _, l := range lines {
+		if w := lipgloss.Width(l); w > bar.Width {
+			t.Errorf("line %q is %d columns wide, want at most %d", l, w, bar.Width)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/config"
 )
 
 var (
@@ -17,6 +18,15 @@ var (
 	pickerBoxStyle      = lipgloss.NewStyle().BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("4")).Padding(0, 1)
 )
 
+// Picker sizing when fitted to the terminal
+const (
+	pickerMinWidth = 30
+	pickerMaxWidth = 80
+	// pickerChromeLines is everything but the items: border, title, input,
+	// the "create" line, blank lines and help
+	pickerChromeLines = 8
+)
+
 // FuzzyPickerModel is a fuzzy-searchable list picker
 type FuzzyPickerModel struct {
 	Items       []string
@@ -24,11 +34,13 @@ type FuzzyPickerModel struct {
 	Query       string
 	Cursor      int
 	Selected    map[string]bool
+	created     map[string]bool // entries in Selected added via "Create new"
 	MultiSelect bool
 	AllowCreate bool
 	Title       string
 	Width       int
 	MaxVisible  int
+	offset      int // index of the first visible item
 	textInput   textinput.Model
 	filterMode  bool // true when actively typing filter
 }
@@ -52,6 +64,7 @@ func NewFuzzyPicker(items []string, title string, multiSelect bool, allowCreate
 		Items:       items,
 		Filtered:    items,
 		Selected:    make(map[string]bool),
+		created:     make(map[string]bool),
 		MultiSelect: multiSelect,
 		AllowCreate: allowCreate,
 		Title:       title,
@@ -62,6 +75,24 @@ func NewFuzzyPicker(items []string, title string, multiSelect bool, allowCreate
 	}
 }
 
+// SetSize fits the picker to a screen area of the given size, unless
+// picker_width or picker_max_visible are configured. A zero dimension (not
+// known yet) keeps the current value.
+func (m *FuzzyPickerModel) SetSize(width, height int) {
+	cfg := config.Get()
+	if w := cfg.GetPickerWidth(); w > 0 {
+		m.Width = w
+	} else if width > 0 {
+		m.Width = min(max(width-2, pickerMinWidth), pickerMaxWidth)
+	}
+	if n := cfg.GetPickerMaxVisible(); n > 0 {
+		m.MaxVisible = n
+	} else if height > 0 {
+		m.MaxVisible = max(height-pickerChromeLines, 1)
+	}
+	m.textInput.Width = max(m.Width-10, 10)
+}
+
 // Init implements tea.Model
 func (m *FuzzyPickerModel) Init() tea.Cmd {
 	// Start in navigation mode, no blink needed
@@ -71,6 +102,9 @@ func (m *FuzzyPickerModel) Init() tea.Cmd {
 // Update implements tea.Model
 func (m *FuzzyPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+		return m, nil
 	case tea.KeyMsg:
 		// Handle filter mode
 		if m.filterMode {
@@ -170,12 +204,8 @@ func (m *FuzzyPickerModel) View() string {
 	content += m.textInput.View() + "\n\n"
 
 	// Items
-	startIdx := 0
-	if m.Cursor >= m.MaxVisible {
-		startIdx = m.Cursor - m.MaxVisible + 1
-	}
-
-	for i := startIdx; i < len(m.Filtered) && i < startIdx+m.MaxVisible; i++ {
+	startIdx, endIdx := m.visibleRange()
+	for i := startIdx; i < endIdx; i++ {
 		item := m.Filtered[i]
 		line := m.renderItem(item, i == m.Cursor, m.Selected[item])
 		content += line + "\n"
@@ -227,6 +257,22 @@ func (m *FuzzyPickerModel) View() string {
 	return pickerBoxStyle.Width(m.Width).Render(content)
 }
 
+// visibleRange returns the window [start, end) of Filtered to render. The
+// window only scrolls when the cursor would leave it, and is kept full when
+// the list or MaxVisible shrinks.
+func (m *FuzzyPickerModel) visibleRange() (int, int) {
+	visible := max(m.MaxVisible, 1)
+	cursor := min(m.Cursor, len(m.Filtered)-1) // the "create" line is outside the window
+	if cursor < m.offset {
+		m.offset = cursor
+	}
+	if cursor >= m.offset+visible {
+		m.offset = cursor - visible + 1
+	}
+	m.offset = max(min(m.offset, len(m.Filtered)-visible), 0)
+	return m.offset, min(m.offset+visible, len(m.Filtered))
+}
+
 func (m *FuzzyPickerModel) renderItem(item string, cursor bool, checked bool) string {
 	prefix := "  "
 	if cursor {
@@ -254,6 +300,7 @@ func (m *FuzzyPickerModel) renderItem(item string, cursor bool, checked bool) st
 }
 
 func (m *FuzzyPickerModel) filterItems() {
+	m.dropStaleCreated()
 	if m.Query == "" {
 		m.Filtered = m.Items
 		return
@@ -269,6 +316,18 @@ func (m *FuzzyPickerModel) filterItems() {
 	m.Filtered = filtered
 }
 
+// dropStaleCreated unselects items created from an earlier query. A created
+// item only exists as the query, so once the query changes it has to be
+// created again to be returned.
+func (m *FuzzyPickerModel) dropStaleCreated() {
+	for item := range m.created {
+		if item != m.Query {
+			delete(m.Selected, item)
+			delete(m.created, item)
+		}
+	}
+}
+
 func (m *FuzzyPickerModel) itemExists(name string) bool {
 	lower := strings.ToLower(name)
 	for _, item := range m.Items {
@@ -285,7 +344,13 @@ func (m *FuzzyPickerModel) toggleCurrent() {
 		m.Selected[item] = !m.Selected[item]
 	} else if m.AllowCreate && m.Query != "" && !m.itemExists(m.Query) {
 		// Toggle the "Create new" option
-		m.Selected[m.Query] = !m.Selected[m.Query]
+		if m.Selected[m.Query] {
+			delete(m.Selected, m.Query)
+			delete(m.created, m.Query)
+		} else {
+			m.Selected[m.Query] = true
+			m.created[m.Query] = true
+		}
 	}
 }
 
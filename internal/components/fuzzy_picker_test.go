@@ -1,9 +1,11 @@
 package components
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/internal/config"
 )
 
 func TestFuzzyPicker_StartsInNavigationMode(t *testing.T) {
@@ -261,3 +263,68 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestFuzzyPicker_MaxVisibleScrolls(t *testing.T) {
+	items := []string{"a0", "a1", "a2", "a3", "a4", "a5", "a6", "a7"}
+	picker := NewFuzzyPicker(items, "Test", false, false)
+	picker.MaxVisible = 3
+
+	visible := func() []string {
+		var shown []string
+		view := picker.View()
+		for _, item := range items {
+			if strings.Contains(view, item) {
+				shown = append(shown, item)
+			}
+		}
+		return shown
+	}
+
+	if got := visible(); strings.Join(got, ",") != "a0,a1,a2" {
+		t.Errorf("initially visible = %v, want [a0 a1 a2]", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		picker.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	}
+	if got := visible(); strings.Join(got, ",") != "a3,a4,a5" {
+		t.Errorf("visible with cursor on a5 = %v, want [a3 a4 a5]", got)
+	}
+
+	// Moving back up inside the window doesn't scroll past the cursor
+	for i := 0; i < 4; i++ {
+		picker.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}})
+	}
+	if got := visible(); strings.Join(got, ",") != "a1,a2,a3" {
+		t.Errorf("visible with cursor on a1 = %v, want [a1 a2 a3]", got)
+	}
+}
+
+func TestFuzzyPicker_SetSize(t *testing.T) {
+	defer func(w, n int) {
+		config.Get().PickerWidth, config.Get().PickerMaxVisible = w, n
+	}(config.Get().PickerWidth, config.Get().PickerMaxVisible)
+	config.Get().PickerWidth, config.Get().PickerMaxVisible = 0, 0
+
+	picker := NewFuzzyPicker([]string{"alpha"}, "Test", false, false)
+	picker.SetSize(0, 0)
+	if picker.Width != 50 || picker.MaxVisible != 10 {
+		t.Errorf("unknown size: got %dx%d, want the 50x10 defaults", picker.Width, picker.MaxVisible)
+	}
+
+	picker.Update(tea.WindowSizeMsg{Width: 40, Height: 12})
+	if picker.Width != 38 || picker.MaxVisible != 4 {
+		t.Errorf("small terminal: got width %d, %d visible, want 38 and 4", picker.Width, picker.MaxVisible)
+	}
+
+	picker.SetSize(200, 3)
+	if picker.Width != pickerMaxWidth || picker.MaxVisible != 1 {
+		t.Errorf("wide, short terminal: got width %d, %d visible, want %d and 1", picker.Width, picker.MaxVisible, pickerMaxWidth)
+	}
+
+	config.Get().PickerWidth, config.Get().PickerMaxVisible = 60, 5
+	picker.SetSize(200, 50)
+	if picker.Width != 60 || picker.MaxVisible != 5 {
+		t.Errorf("configured: got width %d, %d visible, want 60 and 5", picker.Width, picker.MaxVisible)
+	}
+}
@@ -0,0 +1,149 @@
+package components
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/config"
+)
+
+var (
+	helpTitleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4"))
+	helpKeyStyle     = lipgloss.NewStyle().Bold(true).Width(10)
+	firstRunTipStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+)
+
+// firstRunTipText is shown once, on the first launch of the TUI
+const firstRunTipText = "Welcome to wydo! Press ? at any time to see all keybindings."
+
+// KeyBinding is a key and what it does
+type KeyBinding struct {
+	Key  string
+	Desc string
+}
+
+// BindingGroup is the keybindings of one input mode
+type BindingGroup struct {
+	Mode     string
+	Bindings []KeyBinding
+}
+
+var normalModeBindings = []KeyBinding{
+	{"j/k", "move down/up"},
+	{"NG", "jump to row N"},
+	{"enter", "edit task"},
+	{"space/x", "toggle done"},
+	{"n", "new task"},
+	{"y", "duplicate task"},
+	{"|", "split task"},
+	{"z", "defer due date a day (Nz: N days)"},
+	{">/<", "promote/demote priority"},
+	{"v", "mark task for bulk actions"},
+	{"b", "set projects/contexts or shift due dates on marked tasks"},
+	{"S", "cycle status filter"},
+	{"~", "switch between pending and done"},
+	{"f", "filter"},
+	{"s", "sort"},
+	{"g", "group"},
+	{"{/}", "previous/next group"},
+	{"!", "next overdue task"},
+	{":", "go to task by ID (or 4+ character prefix)"},
+	{"ctrl+p", "command palette: filter, sort, group and other actions"},
+	{"/", "search"},
+	{"c", "clear search (keep filters)"},
+	{"R", "clear search, filters, sort and grouping"},
+	{"+/@", "filter by task's project/context"},
+	{"o", "open URL in task"},
+	{"#", "toggle row numbers"},
+	{"w", "toggle wrapping"},
+	{"a", "toggle created dates / ages"},
+	{"r", "toggle next due date on recurring tasks"},
+	{"=", "toggle aligned columns"},
+	{"F", "toggle file view"},
+	{"t", "show/hide future tasks (threshold date t: after today)"},
+	{"[/]", "previous/next page of done.txt (done.txt view)"},
+	{"A", "archive done tasks"},
+	{"X", "complete and archive"},
+	{"u", "undo complete (while the toast shows)"},
+	{"D", "purge done tasks"},
+	{"C", "archive done tasks in selected task's project"},
+	{"i", "show file paths and task counts"},
+	{"?", "show this help"},
+	{"q", "quit"},
+}
+
+// The selection modes' bindings, also shown as the info bar's hints
+var (
+	filterSelectBindings = []KeyBinding{
+		{"/", "search"}, {"d", "date"}, {"D", "has-due"}, {"u", "no-due"}, {"p", "project"}, {"P", "priority"}, {"t", "context"},
+		{"T", "tag"}, {"s", "status"}, {"b", "blocked"}, {"a", "actionable"}, {"f", "file"}, {"esc", "back"},
+	}
+	sortSelectBindings = []KeyBinding{
+		{"d", "date"}, {"p", "project"}, {"P", "priority"}, {"t", "context"}, {"n", "name"}, {"esc", "back"},
+	}
+	groupSelectBindings = []KeyBinding{
+		{"d", "date"}, {"p", "project"}, {"P", "priority"}, {"t", "context"}, {"f", "file"},
+		{"h", "project-depth"}, {"esc", "back"},
+	}
+	bulkSelectBindings = []KeyBinding{
+		{"p", "add-project"}, {"t", "add-context"}, {"P", "replace-projects"}, {"T", "replace-contexts"},
+		{"z", "shift-due"}, {"Z", "shift-or-set-due"}, {"esc", "back"},
+	}
+	directionBindings = []KeyBinding{
+		{"a", "ascending"}, {"d", "descending"}, {"esc", "back"},
+	}
+	editorBindings = []KeyBinding{
+		{"d", "due"}, {"r", "recurrence"}, {"R", "raw-line"}, {"p", "project"}, {"t", "context"}, {"P", "priority"}, {"0-6", "set-priority"},
+		{">/<", "promote/demote"}, {"enter", "save"}, {"n", "save+new"}, {"esc", "cancel"}, {"q", "quit"},
+	}
+)
+
+// normalBindings returns the normal mode bindings with the config applied
+func normalBindings() []KeyBinding {
+	if !config.Get().GetDisableXToggle() {
+		return normalModeBindings
+	}
+	bindings := slices.Clone(normalModeBindings)
+	for i, kb := range bindings {
+		if kb.Key == "space/x" {
+			bindings[i].Key = "space"
+		}
+	}
+	return bindings
+}
+
+// KeyBindingGroups returns the keybindings of each mode with the config
+// applied, as the help overlay and the info bar show them
+func KeyBindingGroups() []BindingGroup {
+	return []BindingGroup{
+		{"Normal", normalBindings()},
+		{"Filter (f)", filterSelectBindings},
+		{"Sort (s)", sortSelectBindings},
+		{"Group (g)", groupSelectBindings},
+		{"Bulk (b)", bulkSelectBindings},
+		{"Sort/group direction", directionBindings},
+		{"Task editor (enter)", editorBindings},
+	}
+}
+
+// bindingHints formats bindings as info bar hints ("key:desc  key:desc")
+func bindingHints(bindings []KeyBinding) string {
+	hints := make([]string, len(bindings))
+	for i, kb := range bindings {
+		hints[i] = kb.Key + ":" + kb.Desc
+	}
+	return strings.Join(hints, hintSeparator)
+}
+
+// renderHelp renders the keybinding overlay for a terminal width columns wide
+func renderHelp(width int) string {
+	var b strings.Builder
+	b.WriteString(helpTitleStyle.Render("Keybindings"))
+	b.WriteString("\n\n")
+	for _, kb := range normalBindings() {
+		b.WriteString(helpKeyStyle.Render(kb.Key) + kb.Desc + "\n")
+	}
+	b.WriteString("\n" + renderHintFooter([]string{"press any key to close"}, width, 0, 1))
+	return b.String()
+}
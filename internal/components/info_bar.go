@@ -1,6 +1,7 @@
 package components
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -21,15 +22,23 @@ type InfoBarModel struct {
 	SortState    *SortState
 	GroupState   *GroupState
 	SearchQuery  string
+	MatchCount   int
 	Message      string
 	Width        int
 	FileViewMode FileViewMode
+	// DonePageRange is the window of done.txt the done.txt view shows
+	DonePageRange string
+	// ShowFuture is set when tasks with a threshold date to come are listed
+	ShowFuture bool
+	// ScrollPercent is how far down the list the cursor is (-1 hides it)
+	ScrollPercent int
 }
 
 // NewInfoBar creates a new info bar
 func NewInfoBar() InfoBarModel {
 	return InfoBarModel{
-		Width: 80,
+		Width:         80,
+		ScrollPercent: -1,
 	}
 }
 
@@ -43,6 +52,23 @@ func (m *InfoBarModel) SetContext(ctx *InputModeContext, filter *FilterState, so
 	m.FileViewMode = fileViewMode
 }
 
+// SetMatchCount sets the number of tasks matching the current search
+func (m *InfoBarModel) SetMatchCount(count int) {
+	m.MatchCount = count
+}
+
+// SetScrollPosition sets the scroll indicator from the cursor's row in a
+// list of total rows: 0% on the first row, 100% on the last. Lists with
+// fewer than two rows hide the indicator.
+func (m *InfoBarModel) SetScrollPosition(cursor, total int) {
+	if total < 2 {
+		m.ScrollPercent = -1
+		return
+	}
+	cursor = min(max(cursor, 0), total-1)
+	m.ScrollPercent = cursor * 100 / (total - 1)
+}
+
 // SetMessage sets a temporary message
 func (m *InfoBarModel) SetMessage(msg string) {
 	m.Message = msg
@@ -53,7 +79,8 @@ func (m *InfoBarModel) ClearMessage() {
 	m.Message = ""
 }
 
-// View renders the info bar (3 fixed lines)
+// View renders the info bar: mode and hints (wrapped to the width), then
+// filters and search/message lines
 func (m *InfoBarModel) View() string {
 	var lines [3]string
 
@@ -76,56 +103,76 @@ func (m *InfoBarModel) renderModeLine() string {
 
 	if m.InputContext == nil {
 		mode = modeStyle.Render("[Normal]")
-		hints = hintStyle.Render("n:new  f:filter  s:sort  g:group  /:search  F:toggle-file  A:archive  enter:edit  space:toggle  q:quit")
+		hints = "n:new  f:filter  s:sort  g:group  /:search  F:toggle-file  A:archive  enter:edit  space:toggle  q:quit"
 	} else {
 		mode = modeStyle.Render("[" + m.InputContext.String() + "]")
 		hints = m.getHintsForMode()
 	}
 
-	return mode + "  " + hints
+	// Hints wrap under themselves rather than overflowing narrow terminals
+	indent := lipgloss.Width(mode) + 2
+	return mode + "  " + renderHintFooter(splitHints(hints), m.Width, indent, maxHintLines)
 }
 
+// getHintsForMode returns the unstyled hints for the current mode
 func (m *InfoBarModel) getHintsForMode() string {
 	if m.InputContext == nil {
-		return hintStyle.Render("n:new  f:filter  s:sort  g:group  /:search  enter:edit  space:toggle")
+		return "n:new  f:filter  s:sort  g:group  /:search  enter:edit  space:toggle"
 	}
 
 	switch m.InputContext.Mode {
 	case ModeNormal:
-		return hintStyle.Render("n:new  f:filter  s:sort  g:group  /:search  F:toggle-file  A:archive  enter:edit  space:toggle")
+		hints := "n:new  y:dup  |:split  z:defer  >/<:priority  v:mark  b:bulk  o:open-url  f:filter  S:status  ~:pending/done  +/@:filter-by-task  #:numbers  w:wrap  a:age  r:next-recurrence  =:align  NG:jump  ::goto-id  ctrl+p:palette  s:sort  g:group  /:search  c:clear-search  R:clear-all  F:toggle-file  A:archive  C:archive-project  D:purge  enter:edit  space/x:toggle  X:done+archive"
+		hints += "  ?:help"
+		if m.GroupState != nil && m.GroupState.IsActive() {
+			hints += "  {/}:prev/next group"
+		}
+		return hints
 
 	case ModeFilterSelect:
-		return hintStyle.Render("/:search  d:date  p:project  P:priority  t:context  s:status  f:file  esc:back")
+		return bindingHints(filterSelectBindings)
 
 	case ModeSortSelect:
-		return hintStyle.Render("d:date  p:project  P:priority  t:context  esc:back")
+		return bindingHints(sortSelectBindings)
+
+	case ModeBulkSelect:
+		return bindingHints(bulkSelectBindings)
 
 	case ModeGroupSelect:
-		return hintStyle.Render("d:date  p:project  P:priority  t:context  f:file  esc:back")
+		return bindingHints(groupSelectBindings)
 
 	case ModeSortDirection, ModeGroupDirection:
-		return hintStyle.Render("a:ascending  d:descending  esc:back")
+		return bindingHints(directionBindings)
 
 	case ModeSearch:
-		return hintStyle.Render("type to filter  j/k:navigate  enter:confirm  esc:clear")
+		return "type to filter  j/k:navigate  enter:confirm  esc:clear"
 
 	case ModeDateInput:
-		return hintStyle.Render("format: yyyy-MM-dd  enter:apply  esc:cancel")
+		return "format: yyyy-MM-dd  enter:apply  esc:cancel"
 
 	case ModeFuzzyPicker:
-		return hintStyle.Render("j/k:navigate  enter:select  esc:cancel")
+		return "j/k:navigate  enter:select  esc:cancel"
 
 	case ModeTaskEditor:
-		return hintStyle.Render("d:due  p:project  t:context  P:priority  enter:save  esc:cancel")
+		return bindingHints(editorBindings)
 
 	case ModeEditDueDate:
-		return hintStyle.Render("format: yyyy-MM-dd  enter:save  esc:cancel")
+		return "format: yyyy-MM-dd  enter:save  esc:cancel"
+
+	case ModeEditRecur:
+		return "format: 1d, 2w, 3m, 1y  enter:save  esc:cancel"
+
+	case ModeEditRaw:
+		return "todo.txt line  enter:save  esc:cancel"
 
 	case ModeEditProject, ModeEditContext:
-		return hintStyle.Render("j/k:navigate  enter:select  space:toggle  esc:cancel")
+		return "j/k:navigate  enter:select  space:toggle  esc:cancel"
+
+	case ModeGotoID:
+		return "type an ID or prefix  enter:go  esc:cancel"
 
 	case ModeConfirmation:
-		return hintStyle.Render("y/enter:yes  n/esc:no")
+		return "y/enter:yes  n/esc:no"
 	}
 
 	return ""
@@ -156,12 +203,23 @@ func (m *InfoBarModel) renderFiltersLine() string {
 			viewMode = "View: todo.txt + done.txt"
 		} else {
 			viewMode = "View: done.txt"
+			if m.DonePageRange != "" {
+				viewMode += " " + m.DonePageRange
+			}
 		}
 		parts = append(parts, lipgloss.NewStyle().
 			Foreground(lipgloss.Color("6")).
 			Render(viewMode))
 	}
 
+	if m.ShowFuture {
+		parts = append(parts, filterStyle.Render("Showing future tasks"))
+	}
+
+	if m.ScrollPercent >= 0 {
+		parts = append(parts, hintStyle.Render(fmt.Sprintf("%d%%", m.ScrollPercent)))
+	}
+
 	if len(parts) == 0 {
 		return "" // Empty line
 	}
@@ -175,8 +233,16 @@ func (m *InfoBarModel) renderSearchLine() string {
 	}
 
 	if m.SearchQuery != "" {
-		return searchStyle.Render("Search: \"" + m.SearchQuery + "\"")
+		return searchStyle.Render(fmt.Sprintf("Search: \"%s\" (%s)", m.SearchQuery, matchCountString(m.MatchCount)))
 	}
 
 	return "" // Empty line
 }
+
+// matchCountString formats a match count, e.g. "1 match" or "4 matches"
+func matchCountString(count int) string {
+	if count == 1 {
+		return "1 match"
+	}
+	return fmt.Sprintf("%d matches", count)
+}
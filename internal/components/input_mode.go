@@ -10,6 +10,7 @@ const (
 	ModeFilterSelect // 'f' pressed - choosing filter type
 	ModeSortSelect   // 's' pressed - choosing sort field
 	ModeGroupSelect  // 'g' pressed - choosing group field
+	ModeBulkSelect   // 'b' pressed - choosing what to set on the marked tasks
 
 	// Sub-modes for direction selection
 	ModeSortDirection  // after selecting sort field, choose asc/desc
@@ -20,10 +21,15 @@ const (
 	ModeDateInput   // entering date for filter
 	ModeFuzzyPicker // generic picker for project/context/file
 	ModeCreateTask  // 'n' pressed - entering new task name
+	ModeSplitTask   // '|' pressed - entering the delimiter to split a task on
+	ModeBulkShift   // 'b' then 'z'/'Z' - entering the days to shift due dates by
+	ModeGotoID      // ':' pressed - entering the ID (or prefix) of a task to jump to
 
 	// Task Editor modes
 	ModeTaskEditor  // viewing task details
 	ModeEditDueDate // 'd' in editor - date input
+	ModeEditRecur   // 'r' in editor - recurrence input
+	ModeEditRaw     // 'R' in editor - raw todo.txt line input
 	ModeEditContext // 't'/'c' in editor - context picker
 	ModeEditProject // 'p' in editor - project picker
 
@@ -70,8 +76,8 @@ func (c *InputModeContext) IsGroupMode() bool {
 
 // IsEditorMode returns true if in task editor mode
 func (c *InputModeContext) IsEditorMode() bool {
-	return c.Mode == ModeTaskEditor || c.Mode == ModeEditDueDate ||
-		c.Mode == ModeEditContext || c.Mode == ModeEditProject
+	return c.Mode == ModeTaskEditor || c.Mode == ModeEditDueDate || c.Mode == ModeEditRecur ||
+		c.Mode == ModeEditRaw || c.Mode == ModeEditContext || c.Mode == ModeEditProject
 }
 
 // TransitionTo moves to a new mode, preserving the previous mode
@@ -106,6 +112,8 @@ func (c *InputModeContext) String() string {
 		return "Sort"
 	case ModeGroupSelect:
 		return "Group"
+	case ModeBulkSelect:
+		return "Bulk"
 	case ModeSortDirection, ModeGroupDirection:
 		return "Direction"
 	case ModeSearch:
@@ -118,6 +126,10 @@ func (c *InputModeContext) String() string {
 		return "Editor"
 	case ModeEditDueDate:
 		return "Edit Due"
+	case ModeEditRecur:
+		return "Edit Recurrence"
+	case ModeEditRaw:
+		return "Edit Line"
 	case ModeEditContext:
 		return "Edit Context"
 	case ModeEditProject:
@@ -126,6 +138,12 @@ func (c *InputModeContext) String() string {
 		return "Confirmation"
 	case ModeCreateTask:
 		return "Create"
+	case ModeSplitTask:
+		return "Split"
+	case ModeBulkShift:
+		return "Reschedule"
+	case ModeGotoID:
+		return "Go to"
 	default:
 		return "Unknown"
 	}
@@ -0,0 +1,48 @@
+package components
+
+import (
+	"os/exec"
+	"regexp"
+	"runtime"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+var urlPattern = regexp.MustCompile(`https?://[^\s]+`)
+
+// OpenURLResultMsg is sent after trying to launch the OS URL opener
+type OpenURLResultMsg struct {
+	URL string
+	Err error
+}
+
+// extractURL returns the first URL in the task name, falling back to the
+// url: tag. Returns "" if the task has no URL.
+func extractURL(task data.Task) string {
+	if url := urlPattern.FindString(task.Name); url != "" {
+		return url
+	}
+	return urlPattern.FindString(task.Tags["url"])
+}
+
+// openURLCommand returns the command line that opens url on the given OS
+func openURLCommand(goos, url string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"open", url}
+	case "windows":
+		return []string{"cmd", "/c", "start", "", url}
+	default:
+		return []string{"xdg-open", url}
+	}
+}
+
+// openURL launches the OS opener for url without waiting for it to exit
+func openURL(url string) tea.Cmd {
+	return func() tea.Msg {
+		args := openURLCommand(runtime.GOOS, url)
+		err := exec.Command(args[0], args[1:]...).Start()
+		return OpenURLResultMsg{URL: url, Err: err}
+	}
+}
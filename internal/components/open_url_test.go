@@ -0,0 +1,74 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+func TestExtractURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"bare link in name", "Read https://example.com/docs later", "https://example.com/docs"},
+		{"url tag", "Read docs url:https://example.com/a?b=c", "https://example.com/a?b=c"},
+		{"name wins over tag", "See http://a.example url:https://b.example", "http://a.example"},
+		{"no url", "Buy milk +home", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			task := data.ParseTask(tc.input, "1", "todo.txt")
+			if got := extractURL(task); got != tc.expected {
+				t.Errorf("extractURL(%q) = %q, want %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestOpenURLCommand(t *testing.T) {
+	url := "https://example.com"
+	tests := []struct {
+		goos     string
+		expected []string
+	}{
+		{"linux", []string{"xdg-open", url}},
+		{"darwin", []string{"open", url}},
+		{"windows", []string{"cmd", "/c", "start", "", url}},
+	}
+
+	for _, tc := range tests {
+		if got := openURLCommand(tc.goos, url); !slicesEqual(got, tc.expected) {
+			t.Errorf("openURLCommand(%q) = %v, want %v", tc.goos, got, tc.expected)
+		}
+	}
+}
+
+func TestTaskManager_OpenURL(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		data.ParseTask("Buy milk", "1", data.GetTodoFilePath()),
+		data.ParseTask("Read https://example.com", "2", data.GetTodoFilePath()),
+	})
+
+	// No URL: no command, just a message
+	_, cmd := tm.handleNormalMode(runeKey('o'))
+	if cmd != nil {
+		t.Error("expected no command for a task without a URL")
+	}
+	if tm.infoBar.Message == "" {
+		t.Error("expected a message for a task without a URL")
+	}
+
+	tm.cursor = 1
+	_, cmd = tm.handleNormalMode(runeKey('o'))
+	if cmd == nil {
+		t.Fatal("expected an open command for a task with a URL")
+	}
+	if tm.infoBar.Message != "" {
+		t.Errorf("expected message to be cleared, got %q", tm.infoBar.Message)
+	}
+}
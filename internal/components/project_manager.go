@@ -1,6 +1,8 @@
 package components
 
 import (
+	"strings"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wyattlefevre/wydocli/internal/data"
 )
@@ -9,6 +11,12 @@ type ProjectManagerModel struct {
 	projects map[string]data.Project
 }
 
+// WithProjects sets the projects to display
+func (m *ProjectManagerModel) WithProjects(projects map[string]data.Project) *ProjectManagerModel {
+	m.projects = projects
+	return m
+}
+
 func (m *ProjectManagerModel) Init() tea.Cmd {
 	return nil
 }
@@ -19,6 +27,17 @@ func (m *ProjectManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *ProjectManagerModel) View() string {
-	// Implement view logic here
-	return "Project Manager"
+	if len(m.projects) == 0 {
+		return emptyStateStyle.Render(emptyNoProjectsMsg)
+	}
+
+	var b strings.Builder
+	for _, project := range data.SortProjects(m.projects) {
+		b.WriteString("  +" + project.Name)
+		if desc := project.Description; desc != "" {
+			b.WriteString("  " + emptyStateStyle.Render(desc))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
 }
@@ -0,0 +1,51 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// SessionState is the filter/sort/group restored between TUI runs, plus
+// flags for one-time UI hints
+type SessionState struct {
+	Filter FilterState `json:"filter"`
+	Sort   SortState   `json:"sort"`
+	Group  GroupState  `json:"group"`
+
+	// SeenHelp is set once the first-run "press ? for help" banner is dismissed
+	SeenHelp bool `json:"seen_help,omitempty"`
+}
+
+// SaveSessionState writes the session state as JSON, creating parent directories
+func SaveSessionState(path string, state SessionState) error {
+	if path == "" {
+		return fmt.Errorf("no session path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating directory: %v", err)
+	}
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644)
+}
+
+// LoadSessionState reads session state previously written by SaveSessionState
+func LoadSessionState(path string) (SessionState, error) {
+	state := SessionState{
+		Filter: NewFilterState(),
+		Sort:   NewSortState(),
+		Group:  NewGroupState(),
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(b, &state); err != nil {
+		return state, fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,116 @@
+package components
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+func TestSessionState_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "session.json")
+	state := SessionState{
+		Filter: FilterState{
+			SearchQuery:    "milk",
+			StatusFilter:   StatusPending,
+			DateFilter:     &DateFilter{Mode: DateBefore, Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+			ProjectFilter:  []string{"home"},
+			ContextFilter:  []string{"store"},
+			PriorityFilter: []data.Priority{data.PriorityA, data.PriorityB},
+		},
+		Sort:  SortState{Field: SortByDueDate, Ascending: false},
+		Group: GroupState{Field: GroupByProject, Ascending: true, ProjectDepth: 1},
+	}
+
+	if err := SaveSessionState(path, state); err != nil {
+		t.Fatalf("SaveSessionState: %v", err)
+	}
+	got, err := LoadSessionState(path)
+	if err != nil {
+		t.Fatalf("LoadSessionState: %v", err)
+	}
+
+	if got.Filter.SearchQuery != "milk" || got.Filter.StatusFilter != StatusPending {
+		t.Errorf("filter = %+v, want search %q status pending", got.Filter, "milk")
+	}
+	if got.Filter.DateFilter == nil || got.Filter.DateFilter.Mode != DateBefore || !got.Filter.DateFilter.Date.Equal(state.Filter.DateFilter.Date) {
+		t.Errorf("date filter = %+v, want %+v", got.Filter.DateFilter, state.Filter.DateFilter)
+	}
+	if !slicesEqual(got.Filter.ProjectFilter, []string{"home"}) || !slicesEqual(got.Filter.ContextFilter, []string{"store"}) {
+		t.Errorf("project/context filters = %v/%v", got.Filter.ProjectFilter, got.Filter.ContextFilter)
+	}
+	if len(got.Filter.PriorityFilter) != 2 || got.Filter.PriorityFilter[0] != data.PriorityA {
+		t.Errorf("priority filter = %v, want [A B]", got.Filter.PriorityFilter)
+	}
+	if got.Sort.Field != state.Sort.Field || got.Sort.Ascending != state.Sort.Ascending {
+		t.Errorf("sort = %+v, want %+v", got.Sort, state.Sort)
+	}
+	if got.Group != state.Group {
+		t.Errorf("group = %+v, want %+v", got.Group, state.Group)
+	}
+}
+
+func TestLoadSessionState_Missing(t *testing.T) {
+	state, err := LoadSessionState(filepath.Join(t.TempDir(), "missing.json"))
+	if !os.IsNotExist(err) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+	if !state.Filter.IsEmpty() || state.Sort.IsActive() || state.Group.IsActive() {
+		t.Errorf("expected default state, got %+v", state)
+	}
+}
+
+func TestTaskManager_SaveAndRestoreSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.filterState.StatusFilter = StatusDone
+	tm.sortState = SortState{Field: SortByPriority, Ascending: true}
+	if err := tm.SaveSession(path); err != nil {
+		t.Fatalf("SaveSession: %v", err)
+	}
+
+	restored := &TaskManagerModel{}
+	restored.Init()
+	restored.restoreSession(path)
+	if restored.filterState.StatusFilter != StatusDone {
+		t.Errorf("StatusFilter = %v, want %v", restored.filterState.StatusFilter, StatusDone)
+	}
+	if restored.sortState.Field != SortByPriority {
+		t.Errorf("sort field = %v, want %v", restored.sortState.Field, SortByPriority)
+	}
+}
+
+func TestTaskManager_FirstRunTip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.statePath = path
+	tm.loadFirstRunTip()
+	if !tm.firstRunTip || !strings.Contains(tm.View(), firstRunTipText) {
+		t.Fatal("expected first-run tip when the state file has no seen flag")
+	}
+
+	// Any key dismisses it and is still handled
+	tm.Update(runeKey('?'))
+	if tm.firstRunTip || !tm.showHelp {
+		t.Errorf("expected tip dismissed and help shown, got tip=%v help=%v", tm.firstRunTip, tm.showHelp)
+	}
+	tm.Update(runeKey('x'))
+	if tm.showHelp {
+		t.Error("expected any key to close the help overlay")
+	}
+
+	next := &TaskManagerModel{}
+	next.Init()
+	next.statePath = path
+	next.loadFirstRunTip()
+	if next.firstRunTip || strings.Contains(next.View(), firstRunTipText) {
+		t.Error("expected first-run tip to be suppressed once seen")
+	}
+}
@@ -1,7 +1,10 @@
 package components
 
 import (
+	"path/filepath"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/wyattlefevre/wydocli/internal/data"
@@ -16,12 +19,39 @@ const (
 	SortByProject
 	SortByPriority
 	SortByContext
+	SortByName
 )
 
+// ParseSortField returns the field for a sort key name as used in the
+// sort_tiebreakers config option
+func ParseSortField(name string) (SortField, bool) {
+	switch name {
+	case "due":
+		return SortByDueDate, true
+	case "project":
+		return SortByProject, true
+	case "priority":
+		return SortByPriority, true
+	case "context":
+		return SortByContext, true
+	case "name":
+		return SortByName, true
+	}
+	return SortByNone, false
+}
+
 // SortState holds sorting configuration
 type SortState struct {
 	Field     SortField
 	Ascending bool
+
+	// Tiebreakers order tasks that Field considers equal, tried in turn and
+	// always ascending. They come from config, so aren't saved in the session.
+	Tiebreakers []SortField `json:"-"`
+
+	// GroupFallback orders tasks within groups, ascending, when they're
+	// grouped by Field (see WithinGroups). From config, like Tiebreakers.
+	GroupFallback []SortField `json:"-"`
 }
 
 // NewSortState creates a new default sort state
@@ -37,12 +67,41 @@ func (s *SortState) IsActive() bool {
 	return s.Field != SortByNone
 }
 
-// Reset clears the sort state
+// Reset clears the sort state, keeping the configured tiebreakers
 func (s *SortState) Reset() {
 	s.Field = SortByNone
 	s.Ascending = true
 }
 
+// WithinGroups returns the sort to apply under grouping g. Grouping by the
+// sort field leaves every task in a group equal under it, so the
+// GroupFallback fields order them instead.
+func (s SortState) WithinGroups(g GroupState) SortState {
+	if len(s.GroupFallback) == 0 || !s.IsActive() || sortFieldForGroup(g.Field) != s.Field {
+		return s
+	}
+	s.Field = s.GroupFallback[0]
+	s.Ascending = true
+	s.Tiebreakers = append(slices.Clone(s.GroupFallback[1:]), s.Tiebreakers...)
+	return s
+}
+
+// sortFieldForGroup returns the sort field that orders tasks the way group
+// field g splits them (SortByNone if there isn't one)
+func sortFieldForGroup(g GroupField) SortField {
+	switch g {
+	case GroupByDueDate:
+		return SortByDueDate
+	case GroupByProject:
+		return SortByProject
+	case GroupByPriority:
+		return SortByPriority
+	case GroupByContext:
+		return SortByContext
+	}
+	return SortByNone
+}
+
 // String returns a display string for the current sort
 func (s *SortState) String() string {
 	if s.Field == SortByNone {
@@ -59,6 +118,8 @@ func (s *SortState) String() string {
 		field = "priority"
 	case SortByContext:
 		field = "context"
+	case SortByName:
+		field = "name"
 	}
 
 	dir := "asc"
@@ -81,10 +142,17 @@ const (
 	GroupByFile
 )
 
+// maxProjectDepth is the deepest project hierarchy level CycleProjectDepth
+// steps through before returning to full project names
+const maxProjectDepth = 3
+
 // GroupState holds grouping configuration
 type GroupState struct {
 	Field     GroupField
 	Ascending bool
+	// ProjectDepth rolls dotted projects (+work.clientA) up to their first
+	// N segments when grouping by project. 0 groups by the full name.
+	ProjectDepth int
 }
 
 // NewGroupState creates a new default group state
@@ -104,6 +172,13 @@ func (g *GroupState) IsActive() bool {
 func (g *GroupState) Reset() {
 	g.Field = GroupByNone
 	g.Ascending = true
+	g.ProjectDepth = 0
+}
+
+// CycleProjectDepth steps the project hierarchy depth through
+// full → 1 → 2 → ... → maxProjectDepth → full
+func (g *GroupState) CycleProjectDepth() {
+	g.ProjectDepth = (g.ProjectDepth + 1) % (maxProjectDepth + 1)
 }
 
 // String returns a display string for the current grouping
@@ -118,6 +193,9 @@ func (g *GroupState) String() string {
 		field = "due"
 	case GroupByProject:
 		field = "project"
+		if g.ProjectDepth > 0 {
+			field += ":" + strconv.Itoa(g.ProjectDepth)
+		}
 	case GroupByPriority:
 		field = "priority"
 	case GroupByContext:
@@ -152,21 +230,44 @@ func ApplySort(tasks []data.Task, state SortState) []data.Task {
 
 	sort.SliceStable(result, func(i, j int) bool {
 		cmp := compareTasksBy(result[i], result[j], state.Field)
-		if state.Ascending {
-			return cmp < 0
+		if !state.Ascending {
+			cmp = -cmp
 		}
-		return cmp > 0
+		for _, tb := range state.Tiebreakers {
+			if cmp != 0 {
+				break
+			}
+			cmp = compareTasksBy(result[i], result[j], tb)
+		}
+		return cmp < 0
 	})
 
 	return result
 }
 
+// MoveDoneToEnd returns the tasks with pending tasks first and completed
+// tasks last, preserving the relative order within each
+func MoveDoneToEnd(tasks []data.Task) []data.Task {
+	result := make([]data.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if !t.Done {
+			result = append(result, t)
+		}
+	}
+	for _, t := range tasks {
+		if t.Done {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
 func compareTasksBy(a, b data.Task, field SortField) int {
 	switch field {
 	case SortByDueDate:
-		dateA := a.GetDueDate()
-		dateB := b.GetDueDate()
-		// Empty dates sort to the end
+		dateA := a.ValidDueDate()
+		dateB := b.ValidDueDate()
+		// Empty (and invalid) dates sort to the end
 		if dateA == "" && dateB == "" {
 			return 0
 		}
@@ -222,6 +323,9 @@ func compareTasksBy(a, b data.Task, field SortField) int {
 			return -1
 		}
 		return strings.Compare(strings.ToLower(ctxA), strings.ToLower(ctxB))
+
+	case SortByName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
 	}
 
 	return 0
@@ -256,6 +360,9 @@ func ApplyGroups(tasks []data.Task, state GroupState) []TaskGroup {
 
 	for _, task := range tasks {
 		keys := getGroupKeys(task, state.Field)
+		if state.Field == GroupByProject && state.ProjectDepth > 0 {
+			keys = rollUpProjects(keys, state.ProjectDepth)
+		}
 		for _, key := range keys {
 			if _, exists := groupMap[key]; !exists {
 				groupOrder = append(groupOrder, key)
@@ -279,6 +386,8 @@ func ApplyGroups(tasks []data.Task, state GroupState) []TaskGroup {
 		label := key
 		if label == "" {
 			label = "(none)"
+		} else if state.Field == GroupByDueDate {
+			label = data.FormatDisplayDate(key)
 		}
 		result = append(result, TaskGroup{
 			Label: label,
@@ -289,6 +398,44 @@ func ApplyGroups(tasks []data.Task, state GroupState) []TaskGroup {
 	return result
 }
 
+// FileSection holds one source file's tasks, grouped as they would be
+// without sections (a single unlabelled group when grouping is off)
+type FileSection struct {
+	File   string // file name without its directory
+	Groups []TaskGroup
+}
+
+// ApplyFileSections splits tasks by source file, keeping their order within
+// each file, and groups each file's tasks. The todo file comes first, then
+// the rest by name.
+func ApplyFileSections(tasks []data.Task, state GroupState) []FileSection {
+	byFile := make(map[string][]data.Task)
+	var files []string
+	for _, task := range tasks {
+		if _, exists := byFile[task.File]; !exists {
+			files = append(files, task.File)
+		}
+		byFile[task.File] = append(byFile[task.File], task)
+	}
+
+	todo := data.GetTodoFilePath()
+	sort.Slice(files, func(i, j int) bool {
+		if (files[i] == todo) != (files[j] == todo) {
+			return files[i] == todo
+		}
+		return filepath.Base(files[i]) < filepath.Base(files[j])
+	})
+
+	sections := make([]FileSection, 0, len(files))
+	for _, file := range files {
+		sections = append(sections, FileSection{
+			File:   filepath.Base(file),
+			Groups: ApplyGroups(byFile[file], state),
+		})
+	}
+	return sections
+}
+
 func getGroupKeys(task data.Task, field GroupField) []string {
 	switch field {
 	case GroupByDueDate:
@@ -325,6 +472,22 @@ func getGroupKeys(task data.Task, field GroupField) []string {
 	return []string{""}
 }
 
+// rollUpProjects truncates dotted project names to their first depth
+// segments, dropping duplicates so a task appears once per rolled-up group
+func rollUpProjects(projects []string, depth int) []string {
+	var result []string
+	for _, p := range projects {
+		segments := strings.Split(p, ".")
+		if len(segments) > depth {
+			p = strings.Join(segments[:depth], ".")
+		}
+		if !slices.Contains(result, p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
 func compareGroupKeys(a, b string, field GroupField) int {
 	// Empty keys sort to the end
 	if a == "" && b == "" {
@@ -379,6 +542,23 @@ func ExtractUniqueContexts(tasks []data.Task) []string {
 	return result
 }
 
+// ExtractUniqueTags returns all unique key:value tags from tasks
+func ExtractUniqueTags(tasks []data.Task) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, task := range tasks {
+		for k, v := range task.Tags {
+			tag := k + ":" + v
+			if !seen[tag] {
+				seen[tag] = true
+				result = append(result, tag)
+			}
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 // ExtractUniqueFiles returns all unique file names from tasks
 func ExtractUniqueFiles(tasks []data.Task) []string {
 	seen := make(map[string]bool)
@@ -0,0 +1,230 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+func taskNames(tasks []data.Task) []string {
+	var names []string
+	for _, t := range tasks {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+func TestApplySort_InvalidDueDatesSortLast(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("broken due:2024-13-40", "1", "todo.txt"),
+		data.ParseTask("later due:2024-03-01", "2", "todo.txt"),
+		data.ParseTask("none", "3", "todo.txt"),
+		data.ParseTask("sooner due:2024-01-01", "4", "todo.txt"),
+	}
+
+	sorted := ApplySort(tasks, SortState{Field: SortByDueDate, Ascending: true})
+	got := taskNames(sorted)
+	expected := []string{"sooner", "later", "broken", "none"}
+	if !slicesEqual(got, expected) {
+		t.Errorf("sorted = %v, want %v", got, expected)
+	}
+}
+
+func TestApplyFilters_InvalidDueDateDoesNotMatchDateFilter(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("broken due:2024-13-40", "1", "todo.txt"),
+		data.ParseTask("valid due:2024-01-01", "2", "todo.txt"),
+	}
+	filter := FilterState{DateFilter: &DateFilter{Mode: DateBefore, Date: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}}
+
+	got := taskNames(ApplyFilters(tasks, filter))
+	if !slicesEqual(got, []string{"valid"}) {
+		t.Errorf("filtered = %v, want [valid]", got)
+	}
+}
+
+func TestApplyFilters_DuePresence(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("dated due:2024-01-01", "1", "todo.txt"),
+		data.ParseTask("undated", "2", "todo.txt"),
+		data.ParseTask("broken due:2024-13-40", "3", "todo.txt"),
+	}
+
+	got := taskNames(ApplyFilters(tasks, FilterState{DateFilter: &DateFilter{Mode: DatePresent}}))
+	if !slicesEqual(got, []string{"dated", "broken"}) {
+		t.Errorf("present = %v, want [dated broken]", got)
+	}
+	got = taskNames(ApplyFilters(tasks, FilterState{DateFilter: &DateFilter{Mode: DateMissing}}))
+	if !slicesEqual(got, []string{"undated"}) {
+		t.Errorf("missing = %v, want [undated]", got)
+	}
+}
+
+func groupLabels(groups []TaskGroup) map[string][]string {
+	result := make(map[string][]string)
+	for _, g := range groups {
+		result[g.Label] = taskNames(g.Tasks)
+	}
+	return result
+}
+
+func TestApplyGroups_ProjectHierarchy(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("call client A +work.clientA.billing", "1", "todo.txt"),
+		data.ParseTask("email client B +work.clientB", "2", "todo.txt"),
+		data.ParseTask("both clients +work.clientA +work.clientB", "3", "todo.txt"),
+		data.ParseTask("groceries +home", "4", "todo.txt"),
+	}
+
+	tests := []struct {
+		name     string
+		depth    int
+		expected map[string][]string
+	}{
+		{
+			name:  "depth 1 rolls up to the top level",
+			depth: 1,
+			expected: map[string][]string{
+				"work": {"call client A", "email client B", "both clients"},
+				"home": {"groceries"},
+			},
+		},
+		{
+			name:  "depth 2 keeps the second level",
+			depth: 2,
+			expected: map[string][]string{
+				"work.clientA": {"call client A", "both clients"},
+				"work.clientB": {"email client B", "both clients"},
+				"home":         {"groceries"},
+			},
+		},
+		{
+			name:  "depth 0 groups by full project name",
+			depth: 0,
+			expected: map[string][]string{
+				"work.clientA.billing": {"call client A"},
+				"work.clientA":         {"both clients"},
+				"work.clientB":         {"email client B", "both clients"},
+				"home":                 {"groceries"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			groups := ApplyGroups(tasks, GroupState{Field: GroupByProject, Ascending: true, ProjectDepth: tc.depth})
+			got := groupLabels(groups)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("groups = %v, want %v", got, tc.expected)
+			}
+			for label, names := range tc.expected {
+				if !slicesEqual(got[label], names) {
+					t.Errorf("group %q = %v, want %v", label, got[label], names)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupState_CycleProjectDepth(t *testing.T) {
+	g := GroupState{Field: GroupByProject, Ascending: true}
+	expected := []string{"project:1 asc", "project:2 asc", "project:3 asc", "project asc"}
+	for _, want := range expected {
+		g.CycleProjectDepth()
+		if got := g.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSortState_WithinGroupsFallsBackWhenGroupedBySortField(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("(A) pay rent due:2024-03-01", "1", "todo.txt"),
+		data.ParseTask("(B) walk dog", "2", "todo.txt"),
+		data.ParseTask("(A) buy milk", "3", "todo.txt"),
+		data.ParseTask("(A) call bank due:2024-02-01", "4", "todo.txt"),
+		data.ParseTask("(B) answer email due:2024-01-15", "5", "todo.txt"),
+	}
+	sortState := SortState{Field: SortByPriority, Ascending: false, GroupFallback: []SortField{SortByDueDate, SortByName}}
+	groupState := GroupState{Field: GroupByPriority, Ascending: true}
+
+	groups := groupLabels(ApplyGroups(ApplySort(tasks, sortState.WithinGroups(groupState)), groupState))
+	if got := groups["A"]; !slicesEqual(got, []string{"call bank", "pay rent", "buy milk"}) {
+		t.Errorf("group A = %v, want it ordered by due date", got)
+	}
+	if got := groups["B"]; !slicesEqual(got, []string{"answer email", "walk dog"}) {
+		t.Errorf("group B = %v, want it ordered by due date", got)
+	}
+
+	// Grouping by another field keeps the chosen sort
+	other := GroupState{Field: GroupByProject, Ascending: true}
+	if got := sortState.WithinGroups(other); got.Field != SortByPriority || got.Ascending {
+		t.Errorf("WithinGroups(project) = %+v, want the priority sort unchanged", got)
+	}
+}
+
+func TestApplySort_Tiebreakers(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("(B) walk dog", "1", "todo.txt"),
+		data.ParseTask("(A) pay rent due:2024-03-01", "2", "todo.txt"),
+		data.ParseTask("(A) call bank due:2024-02-01", "3", "todo.txt"),
+		data.ParseTask("(A) buy milk", "4", "todo.txt"),
+		data.ParseTask("(A) answer email due:2024-03-01", "5", "todo.txt"),
+	}
+
+	// Without tiebreakers, same-priority tasks keep file order
+	sorted := ApplySort(tasks, SortState{Field: SortByPriority, Ascending: true})
+	expected := []string{"pay rent", "call bank", "buy milk", "answer email", "walk dog"}
+	if got := taskNames(sorted); !slicesEqual(got, expected) {
+		t.Errorf("sorted = %v, want %v", got, expected)
+	}
+
+	// Same-priority tasks are then ordered by due date, then name
+	state := SortState{Field: SortByPriority, Ascending: true, Tiebreakers: []SortField{SortByDueDate, SortByName}}
+	sorted = ApplySort(tasks, state)
+	expected = []string{"call bank", "answer email", "pay rent", "buy milk", "walk dog"}
+	if got := taskNames(sorted); !slicesEqual(got, expected) {
+		t.Errorf("sorted with tiebreakers = %v, want %v", got, expected)
+	}
+
+	// Tiebreakers stay ascending when the primary sort is descending
+	state.Ascending = false
+	sorted = ApplySort(tasks, state)
+	expected = []string{"walk dog", "call bank", "answer email", "pay rent", "buy milk"}
+	if got := taskNames(sorted); !slicesEqual(got, expected) {
+		t.Errorf("sorted descending with tiebreakers = %v, want %v", got, expected)
+	}
+}
+
+func TestParseSortField(t *testing.T) {
+	for _, name := range []string{"due", "project", "priority", "context", "name"} {
+		if _, ok := ParseSortField(name); !ok {
+			t.Errorf("ParseSortField(%q) not recognized", name)
+		}
+	}
+	if _, ok := ParseSortField("size"); ok {
+		t.Error("expected unknown sort key to be rejected")
+	}
+}
+
+func TestExtractUniqueTags(t *testing.T) {
+	tasks := []data.Task{
+		{Name: "a", Tags: map[string]string{"due": "2024-03-01", "est": "1h"}},
+		{Name: "b", Tags: map[string]string{"est": "1h"}},
+		{Name: "c", Tags: map[string]string{"est": "2h"}},
+		{Name: "d", Tags: map[string]string{}},
+	}
+
+	got := ExtractUniqueTags(tasks)
+	want := []string{"due:2024-03-01", "est:1h", "est:2h"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -5,7 +5,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/ui"
 )
 
 var (
@@ -27,6 +29,11 @@ type TaskEditorModel struct {
 	allProjects  []string
 	allContexts  []string
 	Width        int
+	StrikeDone   bool // strike the name through when the task is done
+
+	// Size for the project/context pickers (0 until known)
+	pickerWidth  int
+	pickerHeight int
 }
 
 // TaskEditorResultMsg is sent when the editor closes
@@ -34,6 +41,9 @@ type TaskEditorResultMsg struct {
 	Task      data.Task
 	Saved     bool
 	Cancelled bool
+	// NewSibling asks for a new task with the saved task's projects,
+	// contexts and priority once it's saved
+	NewSibling bool
 }
 
 // NewTaskEditor creates a new task editor for the given task
@@ -95,10 +105,25 @@ func (m *TaskEditorModel) handleTaskEditorKeys(msg tea.KeyMsg) (tea.Model, tea.C
 		m.textInput.SetValue(m.task.GetDueDate())
 		return m, m.textInput.Focus()
 
+	case "r":
+		// Edit recurrence
+		m.inputContext.Mode = ModeEditRecur
+		m.textInput = NewTextInput("Recurrence", "1w, 2m, ... (empty to clear)", ValidateRecurrence)
+		m.textInput.SetValue(m.task.Tags["rec"])
+		return m, m.textInput.Focus()
+
+	case "R":
+		// Edit the raw todo.txt line
+		m.inputContext.Mode = ModeEditRaw
+		m.textInput = NewTextInput("Task Line", "(A) name +project @context due:yyyy-MM-dd", ValidateRawLine)
+		m.textInput.SetValue(m.task.String())
+		return m, m.textInput.Focus()
+
 	case "p":
 		// Edit projects
 		m.inputContext.Mode = ModeEditProject
 		m.fuzzyPicker = NewFuzzyPicker(m.allProjects, "Select Projects", true, true)
+		m.fuzzyPicker.SetSize(m.pickerWidth, m.pickerHeight)
 		m.fuzzyPicker.PreSelect(m.task.Projects)
 		return m, nil
 
@@ -106,6 +131,7 @@ func (m *TaskEditorModel) handleTaskEditorKeys(msg tea.KeyMsg) (tea.Model, tea.C
 		// Edit contexts
 		m.inputContext.Mode = ModeEditContext
 		m.fuzzyPicker = NewFuzzyPicker(m.allContexts, "Select Contexts", true, false)
+		m.fuzzyPicker.SetSize(m.pickerWidth, m.pickerHeight)
 		m.fuzzyPicker.PreSelect(m.task.Contexts)
 		return m, nil
 
@@ -114,6 +140,19 @@ func (m *TaskEditorModel) handleTaskEditorKeys(msg tea.KeyMsg) (tea.Model, tea.C
 		m.cyclePriority()
 		return m, nil
 
+	case "0", "1", "2", "3", "4", "5", "6":
+		// Set priority directly: 1-6 -> A-F, 0 -> none
+		m.task.Priority = priorityForDigit(msg.String())
+		return m, nil
+
+	case ">":
+		m.task.PromotePriority(lowestPriority())
+		return m, nil
+
+	case "<":
+		m.task.DemotePriority(lowestPriority())
+		return m, nil
+
 	case "enter":
 		// Save and close
 		return m, func() tea.Msg {
@@ -124,6 +163,16 @@ func (m *TaskEditorModel) handleTaskEditorKeys(msg tea.KeyMsg) (tea.Model, tea.C
 			}
 		}
 
+	case "n":
+		// Save and start a sibling task
+		return m, func() tea.Msg {
+			return TaskEditorResultMsg{
+				Task:       *m.task,
+				Saved:      true,
+				NewSibling: true,
+			}
+		}
+
 	case "esc":
 		// Cancel - restore original task
 		*m.task = m.originalTask
@@ -145,9 +194,9 @@ func (m *TaskEditorModel) updateFuzzyPicker(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !result.Cancelled {
 			switch m.inputContext.Mode {
 			case ModeEditProject:
-				m.task.Projects = result.Selected
+				m.task.SetProjects(result.Selected)
 			case ModeEditContext:
-				m.task.Contexts = result.Selected
+				m.task.SetContexts(result.Selected)
 			}
 		}
 		m.fuzzyPicker = nil
@@ -168,6 +217,10 @@ func (m *TaskEditorModel) updateTextInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch m.inputContext.Mode {
 			case ModeEditDueDate:
 				m.task.SetDueDate(result.Value)
+			case ModeEditRecur:
+				m.task.SetRecurrence(result.Value)
+			case ModeEditRaw:
+				m.setRawLine(result.Value)
 			}
 		}
 		m.textInput = nil
@@ -181,6 +234,12 @@ func (m *TaskEditorModel) updateTextInput(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// setRawLine replaces the task with one parsed from line, keeping its ID and
+// file so the edit saves over the same task
+func (m *TaskEditorModel) setRawLine(line string) {
+	*m.task = data.ParseTask(line, m.task.ID, m.task.File)
+}
+
 func (m *TaskEditorModel) cyclePriority() {
 	switch m.task.Priority {
 	case data.PriorityNone:
@@ -200,6 +259,33 @@ func (m *TaskEditorModel) cyclePriority() {
 	}
 }
 
+// lowestPriority returns the configured lowest priority for promote/demote
+func lowestPriority() data.Priority {
+	return data.Priority(config.Get().GetLowestPriority()[0])
+}
+
+// priorityForDigit maps "1"-"6" to priorities A-F and anything else to none
+func priorityForDigit(key string) data.Priority {
+	if len(key) == 1 && key[0] >= '1' && key[0] <= '6' {
+		return data.PriorityA + data.Priority(key[0]-'1')
+	}
+	return data.PriorityNone
+}
+
+// editingField reports whether a picker or text input is open for a field
+func (m *TaskEditorModel) editingField() bool {
+	return m.fuzzyPicker != nil || m.textInput != nil
+}
+
+// SetPickerSize sets the space available to the editor's pickers
+func (m *TaskEditorModel) SetPickerSize(width, height int) {
+	m.pickerWidth = width
+	m.pickerHeight = height
+	if m.fuzzyPicker != nil {
+		m.fuzzyPicker.SetSize(width, height)
+	}
+}
+
 // View implements tea.Model
 func (m *TaskEditorModel) View() string {
 	// If sub-component is active, show it
@@ -218,7 +304,12 @@ func (m *TaskEditorModel) View() string {
 
 	// Task name
 	content.WriteString(editorLabelStyle.Render("Name:"))
-	content.WriteString(editorValueStyle.Render(m.task.Name))
+	nameStyle := editorValueStyle
+	if m.task.Done {
+		// Done names look the same as in the task list
+		nameStyle = ui.NameStyle(*m.task, m.StrikeDone)
+	}
+	content.WriteString(nameStyle.Render(m.task.Name))
 	content.WriteString("\n")
 
 	// Priority
@@ -236,7 +327,7 @@ func (m *TaskEditorModel) View() string {
 
 	// Due date
 	content.WriteString(editorLabelStyle.Render("Due:"))
-	dueStr := m.task.GetDueDate()
+	dueStr := data.FormatDisplayDate(m.task.GetDueDate())
 	if dueStr == "" {
 		dueStr = "(none)"
 	}
@@ -247,6 +338,22 @@ func (m *TaskEditorModel) View() string {
 	}
 	content.WriteString("\n")
 
+	// Recurrence
+	content.WriteString(editorLabelStyle.Render("Recurs:"))
+	recStr := "(none)"
+	if rec := m.task.Tags["rec"]; rec != "" {
+		recStr = ui.RecurGlyph + rec
+		if next := m.task.NextOccurrence(data.Now()); next != "" {
+			recStr += "  (next due " + data.FormatDisplayDate(next) + " if done today)"
+		}
+	}
+	if m.task.Tags["rec"] != m.originalTask.Tags["rec"] {
+		content.WriteString(editorModifiedStyle.Render(recStr + " *"))
+	} else {
+		content.WriteString(editorValueStyle.Render(recStr))
+	}
+	content.WriteString("\n")
+
 	// Projects
 	content.WriteString(editorLabelStyle.Render("Projects:"))
 	projStr := "(none)"
@@ -274,9 +381,9 @@ func (m *TaskEditorModel) View() string {
 	content.WriteString("\n\n")
 
 	// Help
-	content.WriteString(editorHelpStyle.Render("[d] due  [p] projects  [t] contexts  [P] priority"))
+	content.WriteString(editorHelpStyle.Render("[d] due  [r] recur  [R] raw line  [p] projects  [t] contexts  [P] priority  [1-6/0] set priority  [>/<] promote/demote"))
 	content.WriteString("\n")
-	content.WriteString(editorHelpStyle.Render("[enter] save  [esc] cancel"))
+	content.WriteString(editorHelpStyle.Render("[enter] save  [n] save + new sibling  [esc] cancel  [q] quit"))
 
 	return editorBoxStyle.Width(m.Width).Render(content.String())
 }
@@ -289,13 +396,17 @@ func (m *TaskEditorModel) IsModified() bool {
 	if m.task.GetDueDate() != m.originalTask.GetDueDate() {
 		return true
 	}
+	if m.task.Tags["rec"] != m.originalTask.Tags["rec"] {
+		return true
+	}
 	if !slicesEqual(m.task.Projects, m.originalTask.Projects) {
 		return true
 	}
 	if !slicesEqual(m.task.Contexts, m.originalTask.Contexts) {
 		return true
 	}
-	return false
+	// Anything else, such as the name or other tags from the raw line
+	return m.task.String() != m.originalTask.String()
 }
 
 // slicesEqual compares two string slices for equality
@@ -1,10 +1,13 @@
 package components
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/ui"
 )
 
 func TestTaskEditor_DueDateEdit(t *testing.T) {
@@ -133,6 +136,20 @@ func TestTaskEditor_ProjectEdit(t *testing.T) {
 	}
 }
 
+func TestTaskEditor_ProjectEditNormalizes(t *testing.T) {
+	task := &data.Task{Name: "Test task", Tags: make(map[string]string)}
+	editor := NewTaskEditor(task, []string{"proj1", "proj2"}, nil)
+
+	model, _ := editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	editor = model.(*TaskEditorModel)
+	result := FuzzyPickerResultMsg{Selected: []string{"proj2", "proj1", "proj2"}}
+	editor.Update(result)
+
+	if len(task.Projects) != 2 || task.Projects[0] != "proj1" || task.Projects[1] != "proj2" {
+		t.Errorf("expected projects [proj1, proj2], got %v", task.Projects)
+	}
+}
+
 func TestFuzzyPicker_CreateNewInMultiSelect(t *testing.T) {
 	picker := NewFuzzyPicker([]string{"existing1", "existing2"}, "Select Projects", true, true)
 
@@ -213,6 +230,104 @@ func TestFuzzyPicker_CreateNewWithExisting(t *testing.T) {
 	}
 }
 
+func TestFuzzyPicker_CreateNewDroppedOnQueryChange(t *testing.T) {
+	picker := NewFuzzyPicker([]string{"existing1", "existing2"}, "Select Projects", true, true)
+
+	// Create "foo", then change the query to "bar" without creating it
+	picker.Query = "foo"
+	picker.filterItems()
+	picker.Cursor = len(picker.Filtered)
+	picker.toggleCurrent()
+	picker.Query = "bar"
+	picker.filterItems()
+
+	result := picker.confirm()().(FuzzyPickerResultMsg)
+	if len(result.Selected) != 0 {
+		t.Errorf("expected stale 'foo' to be dropped, got %v", result.Selected)
+	}
+
+	// Going back to "foo" needs it created again
+	picker.Query = "foo"
+	picker.filterItems()
+	if picker.Selected["foo"] {
+		t.Error("expected 'foo' to stay unselected until created again")
+	}
+	picker.Cursor = len(picker.Filtered)
+	picker.toggleCurrent()
+
+	result = picker.confirm()().(FuzzyPickerResultMsg)
+	if len(result.Selected) != 1 || result.Selected[0] != "foo" {
+		t.Errorf("expected [foo] after creating it again, got %v", result.Selected)
+	}
+}
+
+func TestTaskEditor_RecurrenceEdit(t *testing.T) {
+	task := &data.Task{Name: "Water plants", Tags: map[string]string{"rec": "1w"}}
+	editor := NewTaskEditor(task, nil, nil)
+	if !strings.Contains(editor.View(), ui.RecurGlyph+"1w") {
+		t.Errorf("expected the interval in the editor, got:\n%s", editor.View())
+	}
+
+	model, _ := editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	editor = model.(*TaskEditorModel)
+	if editor.inputContext.Mode != ModeEditRecur || editor.textInput == nil {
+		t.Fatalf("expected the recurrence input, got mode %v", editor.inputContext.Mode)
+	}
+	if editor.textInput.Value() != "1w" {
+		t.Errorf("expected the input prefilled with 1w, got %q", editor.textInput.Value())
+	}
+
+	model, _ = editor.Update(TextInputResultMsg{Value: "2m"})
+	editor = model.(*TaskEditorModel)
+	if task.Tags["rec"] != "2m" {
+		t.Errorf("rec = %q, want 2m", task.Tags["rec"])
+	}
+	if !editor.IsModified() {
+		t.Error("expected a changed interval to count as a modification")
+	}
+
+	// An empty value clears it
+	editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	editor.Update(TextInputResultMsg{Value: ""})
+	if _, ok := task.Tags["rec"]; ok {
+		t.Errorf("expected rec: removed, got %q", task.Tags["rec"])
+	}
+
+	if ValidateRecurrence("weekly") == nil {
+		t.Error("expected an invalid interval to be rejected")
+	}
+}
+
+func TestTaskEditor_RawLineEdit(t *testing.T) {
+	task := &data.Task{ID: "abc123", File: "todo.txt", Name: "Pay rent", Projects: []string{"home"}, Tags: map[string]string{}}
+	editor := NewTaskEditor(task, nil, nil)
+
+	model, _ := editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	editor = model.(*TaskEditorModel)
+	if editor.inputContext.Mode != ModeEditRaw || editor.textInput == nil {
+		t.Fatalf("expected the raw line input, got mode %v", editor.inputContext.Mode)
+	}
+	if editor.textInput.Value() != "Pay rent +home" {
+		t.Errorf("expected the input prefilled with the line, got %q", editor.textInput.Value())
+	}
+
+	model, _ = editor.Update(TextInputResultMsg{Value: "(B) Pay rent +home ref:lease"})
+	editor = model.(*TaskEditorModel)
+	if task.Tags["ref"] != "lease" || task.Priority != data.PriorityB || task.Name != "Pay rent" {
+		t.Errorf("expected the parsed line, got %+v", *task)
+	}
+	if task.ID != "abc123" || task.File != "todo.txt" {
+		t.Errorf("expected ID and file kept, got %q in %q", task.ID, task.File)
+	}
+	if !editor.IsModified() {
+		t.Error("expected a raw line edit to count as a modification")
+	}
+
+	if ValidateRawLine("(A) ") == nil {
+		t.Error("expected a line without a name to be rejected")
+	}
+}
+
 func TestTaskEditor_ContextEdit(t *testing.T) {
 	task := &data.Task{
 		Name:     "Test task",
@@ -290,6 +405,35 @@ func TestTaskEditor_PriorityCycle(t *testing.T) {
 	}
 }
 
+func TestTaskEditor_PriorityDirectKeys(t *testing.T) {
+	task := &data.Task{
+		Name:     "Test task",
+		Priority: data.PriorityA,
+		Tags:     make(map[string]string),
+	}
+
+	editor := NewTaskEditor(task, nil, nil)
+
+	tests := []struct {
+		key      rune
+		expected data.Priority
+	}{
+		{'3', data.PriorityC},
+		{'6', data.PriorityF},
+		{'1', data.PriorityA},
+		{'0', data.PriorityNone},
+	}
+
+	for _, tc := range tests {
+		model, _ := editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tc.key}})
+		editor = model.(*TaskEditorModel)
+
+		if task.Priority != tc.expected {
+			t.Errorf("key %q: expected priority %v, got %v", tc.key, tc.expected, task.Priority)
+		}
+	}
+}
+
 func TestTaskEditor_SaveAndClose(t *testing.T) {
 	task := &data.Task{
 		Name:     "Test task",
@@ -384,3 +528,74 @@ func TestTaskEditor_IsModified(t *testing.T) {
 		t.Error("expected not modified after restoration")
 	}
 }
+
+func TestDateInput_NormalizesFlexibleFormats(t *testing.T) {
+	input := NewDateInput("Due Date")
+	input.SetValue("01/15/2025")
+
+	_, cmd := input.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected command from enter")
+	}
+	result, ok := cmd().(TextInputResultMsg)
+	if !ok {
+		t.Fatal("expected TextInputResultMsg")
+	}
+	if result.Value != "2025-01-15" {
+		t.Errorf("expected normalized value '2025-01-15', got '%s'", result.Value)
+	}
+
+	// Unrecognized input is rejected by the validator
+	input.SetValue("someday")
+	_, cmd = input.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd != nil || input.Error == "" {
+		t.Error("expected validation error for unrecognized date")
+	}
+}
+
+func TestTaskEditor_PromoteDemote(t *testing.T) {
+	task := &data.Task{
+		Name:     "Test task",
+		Priority: data.PriorityNone,
+		Tags:     make(map[string]string),
+	}
+
+	editor := NewTaskEditor(task, nil, nil)
+
+	tests := []struct {
+		key      rune
+		expected data.Priority
+	}{
+		{'>', data.PriorityA}, // promote from none
+		{'>', data.PriorityA}, // stops at A
+		{'<', data.PriorityB},
+		{'6', data.PriorityF},
+		{'<', data.PriorityNone}, // demoting the lowest removes the priority
+		{'<', data.PriorityNone},
+	}
+
+	for i, tc := range tests {
+		model, _ := editor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tc.key}})
+		editor = model.(*TaskEditorModel)
+
+		if task.Priority != tc.expected {
+			t.Errorf("step %d (%q): expected priority %v, got %v", i+1, tc.key, tc.expected, task.Priority)
+		}
+	}
+}
+
+func TestTaskEditor_DisplayDateFormat(t *testing.T) {
+	defer func(orig string) { config.Get().DisplayDateFormat = orig }(config.Get().DisplayDateFormat)
+	config.Get().DisplayDateFormat = "eu"
+
+	task := &data.Task{Name: "Test task", Tags: map[string]string{"due": "2025-01-15"}}
+	editor := NewTaskEditor(task, nil, nil)
+
+	view := editor.View()
+	if !strings.Contains(view, "15/01") || strings.Contains(view, "2025-01-15") {
+		t.Errorf("expected the due date as 15/01, got:\n%s", view)
+	}
+	if task.GetDueDate() != "2025-01-15" || !strings.Contains(task.String(), "due:2025-01-15") {
+		t.Errorf("expected the stored due date to stay ISO, got %q", task.String())
+	}
+}
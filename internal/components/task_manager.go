@@ -2,12 +2,17 @@ package components
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
 	"github.com/wyattlefevre/wydocli/internal/ui"
 	"github.com/wyattlefevre/wydocli/logs"
@@ -15,7 +20,20 @@ import (
 
 var (
 	groupHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5")).MarginTop(1)
+	fileHeaderStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("4")).MarginTop(1)
 	cursorStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	emptyStateStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	rowNumberStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	markStyle        = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("3"))
+)
+
+// Empty-state messages shown when a view has nothing to display
+const (
+	emptyNoTasksMsg     = "No tasks yet — press n to add one"
+	emptyNoMatchesMsg   = "No tasks match the current filters — press esc to clear them"
+	emptyNoTodoTasksMsg = "No tasks in todo.txt — press n to add one or F to change view"
+	emptyNoDoneTasksMsg = "No completed tasks in done.txt — press F to change view"
+	emptyNoProjectsMsg  = "No projects found"
 )
 
 // FileViewMode determines which file(s) to display tasks from
@@ -27,11 +45,36 @@ const (
 	FileViewDoneOnly
 )
 
+// ParseFileViewMode converts a config value ("todo", "all", "done") to a FileViewMode.
+// Unknown values fall back to FileViewTodoOnly.
+func ParseFileViewMode(s string) FileViewMode {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "all":
+		return FileViewAll
+	case "done":
+		return FileViewDoneOnly
+	default:
+		return FileViewTodoOnly
+	}
+}
+
+// defaultFileViewMode returns the configured startup file view mode
+func defaultFileViewMode() FileViewMode {
+	return ParseFileViewMode(config.Get().GetDefaultFileView())
+}
+
 // TaskUpdateMsg is sent when a task is updated
 type TaskUpdateMsg struct {
 	Task data.Task
 }
 
+// TasksUpdateMsg is sent to update several tasks, and delete the tasks
+// with the Deleted IDs, with a single write
+type TasksUpdateMsg struct {
+	Tasks   []data.Task
+	Deleted []string
+}
+
 // TaskEditorOpenMsg is sent to open the task editor
 type TaskEditorOpenMsg struct {
 	Task *data.Task
@@ -40,12 +83,18 @@ type TaskEditorOpenMsg struct {
 // ToggleFileViewMsg is sent to cycle file view mode
 type ToggleFileViewMsg struct{}
 
+// QuitRequestMsg asks the app to quit, sent once the task editor's changes
+// have been saved or discarded
+type QuitRequestMsg struct{}
+
 // StartArchiveMsg is sent to start the archive flow
 type StartArchiveMsg struct{}
 
-// ArchiveRequestMsg is sent to request archiving tasks
+// ArchiveRequestMsg is sent to request archiving tasks. A non-empty Project
+// limits the archive to that project's completed tasks.
 type ArchiveRequestMsg struct {
-	Count int
+	Count   int
+	Project string
 }
 
 // ArchiveCompleteMsg is sent when archive operation completes
@@ -53,15 +102,78 @@ type ArchiveCompleteMsg struct {
 	Count int
 }
 
+// PurgeRequestMsg is sent to request deleting completed tasks
+type PurgeRequestMsg struct {
+	Count int
+}
+
+// PurgeCompleteMsg is sent when the purge operation completes
+type PurgeCompleteMsg struct {
+	Count int
+}
+
+// DonePageRequestMsg is sent to request a page of done.txt for the done.txt
+// view
+type DonePageRequestMsg struct {
+	Offset int
+	Limit  int
+}
+
+// DonePageLoadedMsg carries the page of done.txt starting at Offset
+type DonePageLoadedMsg struct {
+	Offset int
+	Tasks  []data.Task
+}
+
+// UndoToastExpiredMsg is sent when the undo toast's window runs out
+type UndoToastExpiredMsg struct {
+	seq int
+}
+
+// undoToastDuration is how long "u" can revert a single-key complete
+const undoToastDuration = 5 * time.Second
+
+// undoToastText is shown in the info bar while an undo is available
+const undoToastText = "Undone? press u"
+
+// maxUndo caps how many reverted task states are kept
+const maxUndo = 20
+
+// Actions awaiting a confirmation modal answer
+const (
+	confirmArchive        = "archive"
+	confirmPurge          = "purge"
+	confirmArchiveProject = "archive-project"
+	confirmQuit           = "quit"
+)
+
 // TaskManagerModel manages the task list view with filtering, sorting, and grouping
 type TaskManagerModel struct {
 	// Data
 	tasks        []data.Task
 	displayTasks []data.Task
 	taskGroups   []TaskGroup
+	fileSections []FileSection // set instead of drawing groups flat when file headers show
 
 	// Navigation
-	cursor int
+	cursor      int
+	showNumbers bool   // prefix each displayed task with its 1-based row number
+	countBuffer string // digits typed in normal mode, consumed by G/enter to jump
+
+	// Cursor memory: the cursor last used under each filter key, so going
+	// back to an earlier filter restores its selection
+	cursorMemory map[string]int
+	filterKey    string
+
+	// Bulk actions: IDs of tasks marked with 'v'
+	marked map[string]bool
+
+	// Undo: task states from before each single-key complete, newest last.
+	// "u" pops the newest while the toast is up (until undoDeadline, or the
+	// next key); undoSeq ties each expiry tick to the toast it was made for
+	undoStack    []data.Task
+	undoDeadline time.Time
+	undoSeq      int
 
 	// State
 	inputContext InputModeContext
@@ -75,10 +187,58 @@ type TaskManagerModel struct {
 	textInput         *TextInputModel
 	taskEditor        *TaskEditorModel
 	confirmationModal *ConfirmationModal
+	confirmAction     string // which action the confirmation modal is for
+	confirmProject    string // project for confirmArchiveProject
 
 	// File view mode
 	fileViewMode FileViewMode
 
+	// The done.txt view lists donePage, the window of done.txt starting at
+	// doneOffset, so large archives aren't listed in full. Until a page has
+	// loaded (donePaged) it falls back to the done tasks in tasks.
+	donePage   []data.Task
+	doneOffset int
+	donePaged  bool
+
+	// inlineCompleted shows done tasks struck-through at the bottom of the All view
+	inlineCompleted bool
+
+	// fileHeaders puts each file's tasks under a header in the All view
+	fileHeaders bool
+
+	// strikeDone strikes done names through in every view; hideDone drops
+	// done tasks from every view but done.txt's
+	strikeDone bool
+	hideDone   bool
+
+	// showFuture lists pending tasks whose threshold date (t:) hasn't come
+	// yet, which are hidden by default as in wydo list
+	showFuture bool
+
+	// Help: showHelp displays the keybinding overlay; firstRunTip shows a
+	// one-time banner pointing to it until any key is pressed, after which
+	// the seen flag is recorded in the state file at statePath
+	showHelp    bool
+	showFiles   bool // the resolved file paths and per-file task counts ('i')
+	firstRunTip bool
+	statePath   string
+
+	// Layout: size from the last WindowSizeMsg (0 until known); long names
+	// are truncated to fit, or wrapped when wrapNames is set
+	width     int
+	height    int
+	wrapNames bool
+
+	// createdAge shows created dates as relative ages ("12d ago")
+	createdAge bool
+
+	// nextOccurrence shows when a recurring task would next be due
+	nextOccurrence bool
+
+	// alignColumns pads names so task metadata lines up in columns
+	alignColumns bool
+	metaColumn   int // where aligned metadata starts, set on each render
+
 	// Inline search
 	searchActive     bool
 	searchFilterMode bool // true when actively typing in search filter
@@ -88,21 +248,54 @@ type TaskManagerModel struct {
 	allProjects []string
 	allContexts []string
 	allFiles    []string
+	allTags     []string
+
+	// newTaskTemplate seeds the next created task's projects, contexts and
+	// priority (set by the editor's save + new sibling)
+	newTaskTemplate *data.Task
 
 	// Picker context (what are we picking for)
-	pickerContext string // "filter-project", "filter-context", "filter-file", etc.
+	pickerContext string           // "filter-project", "filter-context", "filter-file", etc.
+	palette       []paletteCommand // commands listed while the command palette is open
 }
 
-// WithTasks sets the tasks and extracts metadata
+// WithTasks sets the tasks and extracts metadata.
+// If the task editor is open, its in-progress edits are carried over to the
+// refreshed task list so a reload never silently drops them.
 func (m *TaskManagerModel) WithTasks(tasks []data.Task) *TaskManagerModel {
+	var editing *data.Task
+	if m.taskEditor != nil {
+		edited := *m.taskEditor.task
+		editing = &edited
+	}
+
 	m.tasks = tasks
 	m.allProjects = ExtractUniqueProjects(tasks)
 	m.allContexts = ExtractUniqueContexts(tasks)
 	m.allFiles = ExtractUniqueFiles(tasks)
+	m.allTags = ExtractUniqueTags(tasks)
 	m.refreshDisplayTasks()
+
+	if editing != nil {
+		m.rebindTaskEditor(*editing)
+	}
 	return m
 }
 
+// rebindTaskEditor points the open editor at the refreshed copy of the task
+// it is editing, preserving the edits. Tasks that no longer appear in the
+// list (e.g. unsaved new tasks) keep a private copy instead of a stale pointer.
+func (m *TaskManagerModel) rebindTaskEditor(edited data.Task) {
+	for i := range m.displayTasks {
+		if m.displayTasks[i].ID == edited.ID {
+			m.displayTasks[i] = edited
+			m.taskEditor.task = &m.displayTasks[i]
+			return
+		}
+	}
+	m.taskEditor.task = &edited
+}
+
 // Init implements tea.Model
 func (m *TaskManagerModel) Init() tea.Cmd {
 	m.inputContext = NewInputModeContext()
@@ -110,10 +303,93 @@ func (m *TaskManagerModel) Init() tea.Cmd {
 	m.sortState = NewSortState()
 	m.groupState = NewGroupState()
 	m.infoBar = NewInfoBar()
-	m.fileViewMode = FileViewTodoOnly
+	m.fileViewMode = defaultFileViewMode()
+	m.inlineCompleted = config.Get().GetInlineCompleted()
+	m.fileHeaders = config.Get().GetFileHeaders()
+	m.strikeDone = config.Get().GetStrikeDone()
+	m.hideDone = config.Get().GetHideDone()
+	m.createdAge = config.Get().GetShowCreatedAge()
+	m.alignColumns = config.Get().GetAlignColumns()
+	m.statePath = config.GetSessionPath()
+	m.loadFirstRunTip()
+	if config.Get().GetRestoreSession() {
+		m.restoreSession(m.statePath)
+	}
+	m.sortState.Tiebreakers = parseSortFields(config.Get().GetSortTiebreakers())
+	m.sortState.GroupFallback = parseSortFields(config.Get().GetGroupSortFallback())
 	return nil
 }
 
+// parseSortFields returns the sort keys from a config option as fields,
+// skipping any that aren't sort keys (such as "none")
+func parseSortFields(names []string) []SortField {
+	var fields []SortField
+	for _, name := range names {
+		if f, ok := ParseSortField(name); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// SessionState returns the active filter/sort/group
+func (m *TaskManagerModel) SessionState() SessionState {
+	return SessionState{
+		Filter:   m.filterState,
+		Sort:     m.sortState,
+		Group:    m.groupState,
+		SeenHelp: !m.firstRunTip,
+	}
+}
+
+// SaveSession persists the active filter/sort/group to path
+func (m *TaskManagerModel) SaveSession(path string) error {
+	return SaveSessionState(path, m.SessionState())
+}
+
+// restoreSession applies a saved filter/sort/group. A missing or unreadable
+// session file leaves the defaults in place.
+func (m *TaskManagerModel) restoreSession(path string) {
+	state, err := LoadSessionState(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logs.Logger.Printf("Could not restore session: %v", err)
+		}
+		return
+	}
+	m.filterState = state.Filter
+	m.sortState = state.Sort
+	m.groupState = state.Group
+}
+
+// loadFirstRunTip shows the first-run banner unless the state file records
+// that it has already been seen
+func (m *TaskManagerModel) loadFirstRunTip() {
+	state, err := LoadSessionState(m.statePath)
+	if err != nil && !os.IsNotExist(err) {
+		logs.Logger.Printf("Could not read state file: %v", err)
+	}
+	m.firstRunTip = !state.SeenHelp
+}
+
+// dismissFirstRunTip hides the first-run banner and records that it was seen
+// so it doesn't reappear. Other saved state in the file is left untouched.
+func (m *TaskManagerModel) dismissFirstRunTip() {
+	m.firstRunTip = false
+	if m.statePath == "" {
+		return
+	}
+	state, err := LoadSessionState(m.statePath)
+	if err != nil && !os.IsNotExist(err) {
+		logs.Logger.Printf("Could not read state file: %v", err)
+		return
+	}
+	state.SeenHelp = true
+	if err := SaveSessionState(m.statePath, state); err != nil {
+		logs.Logger.Printf("Could not save state file: %v", err)
+	}
+}
+
 // Update implements tea.Model
 func (m *TaskManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle sub-component results first
@@ -137,6 +413,16 @@ func (m *TaskManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ToggleFileViewMsg:
 		m.cycleFileViewMode()
 		m.refreshDisplayTasks()
+		return m, m.requestDonePage(0)
+	case DonePageLoadedMsg:
+		if len(msg.Tasks) == 0 && msg.Offset > 0 {
+			m.infoBar.SetMessage("No more done tasks")
+			return m, nil
+		}
+		m.donePage = msg.Tasks
+		m.doneOffset = msg.Offset
+		m.donePaged = true
+		m.refreshDisplayTasks()
 		return m, nil
 	case StartArchiveMsg:
 		return m.handleStartArchive()
@@ -145,6 +431,42 @@ func (m *TaskManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case ArchiveCompleteMsg:
 		m.confirmationModal = nil
 		return m, tea.Printf("✓ Archived %d tasks to done.txt", msg.Count)
+	case PurgeCompleteMsg:
+		m.confirmationModal = nil
+		return m, tea.Printf("✓ Purged %d completed tasks", msg.Count)
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.infoBar.Width = msg.Width
+		if m.fuzzyPicker != nil {
+			m.fuzzyPicker.SetSize(m.width, m.pickerHeight())
+		}
+		if m.taskEditor != nil {
+			m.taskEditor.SetPickerSize(m.width, m.pickerHeight())
+		}
+		return m, nil
+	case UndoToastExpiredMsg:
+		if msg.seq == m.undoSeq && !m.undoDeadline.IsZero() {
+			m.dismissUndoToast()
+		}
+		return m, nil
+	case OpenURLResultMsg:
+		if msg.Err != nil {
+			m.infoBar.SetMessage("Could not open " + msg.URL + ": " + msg.Err.Error())
+		}
+		return m, nil
+	}
+
+	// The first-run banner goes away on any key, which is still handled
+	if _, ok := msg.(tea.KeyMsg); ok && m.firstRunTip {
+		m.dismissFirstRunTip()
+	}
+
+	// The help and file overlays close on any key
+	if _, ok := msg.(tea.KeyMsg); ok && (m.showHelp || m.showFiles) {
+		m.showHelp = false
+		m.showFiles = false
+		return m, nil
 	}
 
 	// Handle inline search mode (before other sub-components)
@@ -178,6 +500,9 @@ func (m *TaskManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 	}
 	if m.taskEditor != nil {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && isQuitKey(keyMsg) && !m.taskEditor.editingField() {
+			return m.quitFromEditor()
+		}
 		var cmd tea.Cmd
 		_, cmd = m.taskEditor.Update(msg)
 		return m, cmd
@@ -203,6 +528,8 @@ func (m *TaskManagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.handleSortDirection(msg)
 		case ModeGroupDirection:
 			return m.handleGroupDirection(msg)
+		case ModeBulkSelect:
+			return m.handleBulkSelect(msg)
 		}
 	}
 
@@ -215,11 +542,28 @@ func (m *TaskManagerModel) View() string {
 
 	// Update info bar with current state
 	m.infoBar.SetContext(&m.inputContext, &m.filterState, &m.sortState, &m.groupState, m.filterState.SearchQuery, m.fileViewMode)
+	m.infoBar.DonePageRange = m.donePageRange()
+	m.infoBar.ShowFuture = m.showFuture
+	m.infoBar.SetMatchCount(len(m.displayTasks))
+	m.infoBar.SetScrollPosition(m.cursor, len(m.displayTasks))
 
 	// Info bar (always visible)
 	b.WriteString(m.infoBar.View())
 	b.WriteString("\n\n")
 
+	if m.showHelp {
+		b.WriteString(renderHelp(m.width))
+		return b.String()
+	}
+	if m.showFiles {
+		b.WriteString(renderFileStatus(m.tasks, m.width))
+		return b.String()
+	}
+	if m.firstRunTip {
+		b.WriteString(firstRunTipStyle.Render(firstRunTipText))
+		b.WriteString("\n\n")
+	}
+
 	// Sub-component overlays (except search - which is inline)
 	if m.confirmationModal != nil {
 		modal := m.confirmationModal.View()
@@ -264,7 +608,10 @@ func (m *TaskManagerModel) View() string {
 	}
 
 	// Task list
-	if m.groupState.IsActive() && len(m.taskGroups) > 0 {
+	m.metaColumn = m.alignedColumn()
+	if len(m.fileSections) > 0 {
+		b.WriteString(m.renderFileSections())
+	} else if m.groupState.IsActive() && len(m.taskGroups) > 0 {
 		b.WriteString(m.renderGroupedTasks())
 	} else {
 		b.WriteString(m.renderFlatTasks())
@@ -277,21 +624,83 @@ func (m *TaskManagerModel) renderFlatTasks() string {
 	var b strings.Builder
 
 	if len(m.displayTasks) == 0 {
-		b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Render("No tasks found."))
+		b.WriteString(emptyStateStyle.Render(m.emptyStateMessage()))
 		return b.String()
 	}
 
 	for i, task := range m.displayTasks {
-		prefix := "  "
-		if i == m.cursor {
-			prefix = cursorStyle.Render("> ")
-		}
-		b.WriteString(prefix + ui.StyledTaskLine(task) + "\n")
+		b.WriteString(m.renderTaskRow(i, task) + "\n")
 	}
 
 	return b.String()
 }
 
+// renderTaskRow renders one task with its cursor marker and row number.
+// Wrapped continuation lines are indented past both.
+func (m *TaskManagerModel) renderTaskRow(idx int, task data.Task) string {
+	prefix := "  "
+	if idx == m.cursor {
+		prefix = cursorStyle.Render("> ")
+	}
+	if m.marked[task.ID] {
+		// The mark takes the cursor's trailing space
+		if idx == m.cursor {
+			prefix = cursorStyle.Render(">") + markStyle.Render("*")
+		} else {
+			prefix = " " + markStyle.Render("*")
+		}
+	}
+	prefix += m.rowNumber(idx)
+
+	opts := m.lineOptions()
+	if opts.Width > 0 {
+		opts.Width = max(opts.Width-lipgloss.Width(prefix), 1)
+	}
+	line := ui.StyledTaskLineWithOptions(task, opts)
+	line = strings.ReplaceAll(line, "\n", "\n"+strings.Repeat(" ", lipgloss.Width(prefix)))
+	return prefix + line
+}
+
+// alignedColumn returns the column task metadata starts at when columns are
+// aligned, measured after the cursor and row number, or 0 when they aren't
+func (m *TaskManagerModel) alignedColumn() int {
+	if !m.alignColumns {
+		return 0
+	}
+	opts := m.lineOptions()
+	if opts.Width > 0 {
+		opts.Width = max(opts.Width-2-lipgloss.Width(m.rowNumber(0)), 1)
+	}
+	return ui.MetaColumn(m.displayTasks, opts)
+}
+
+// emptyStateMessage explains why the task list is empty
+func (m *TaskManagerModel) emptyStateMessage() string {
+	if len(m.tasks) == 0 {
+		return emptyNoTasksMsg
+	}
+	if !m.filterState.IsEmpty() {
+		return emptyNoMatchesMsg
+	}
+	switch m.fileViewMode {
+	case FileViewTodoOnly:
+		return emptyNoTodoTasksMsg
+	case FileViewDoneOnly:
+		return emptyNoDoneTasksMsg
+	}
+	return emptyNoTasksMsg
+}
+
+// rowNumber returns the right-aligned 1-based row number for a display index,
+// or an empty string when row numbers are hidden
+func (m *TaskManagerModel) rowNumber(idx int) string {
+	if !m.showNumbers {
+		return ""
+	}
+	width := len(fmt.Sprintf("%d", len(m.displayTasks)))
+	return rowNumberStyle.Render(fmt.Sprintf("%*d ", width, idx+1))
+}
+
 func (m *TaskManagerModel) renderGroupedTasks() string {
 	var b strings.Builder
 
@@ -302,11 +711,7 @@ func (m *TaskManagerModel) renderGroupedTasks() string {
 		b.WriteString("\n")
 
 		for _, task := range group.Tasks {
-			prefix := "  "
-			if taskIndex == m.cursor {
-				prefix = cursorStyle.Render("> ")
-			}
-			b.WriteString(prefix + ui.StyledTaskLine(task) + "\n")
+			b.WriteString(m.renderTaskRow(taskIndex, task) + "\n")
 			taskIndex++
 		}
 	}
@@ -316,17 +721,109 @@ func (m *TaskManagerModel) renderGroupedTasks() string {
 
 // Input handlers
 
+// renderFileSections renders a header per source file with that file's
+// tasks, and any group headers nested under it
+func (m *TaskManagerModel) renderFileSections() string {
+	var b strings.Builder
+
+	taskIndex := 0
+	for _, section := range m.fileSections {
+		b.WriteString(fileHeaderStyle.Render("── " + section.File + " ──"))
+		b.WriteString("\n")
+
+		for _, group := range section.Groups {
+			if m.groupState.IsActive() {
+				b.WriteString(groupHeaderStyle.Render("  ── " + group.Label + " ──"))
+				b.WriteString("\n")
+			}
+			for _, task := range group.Tasks {
+				b.WriteString(m.renderTaskRow(taskIndex, task) + "\n")
+				taskIndex++
+			}
+		}
+	}
+
+	return b.String()
+}
+
 func (m *TaskManagerModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.String() {
+	key := msg.String()
+	undoable := m.undoToastActive()
+	if !m.undoDeadline.IsZero() {
+		m.dismissUndoToast()
+	}
+	m.infoBar.ClearMessage()
+
+	// Numeric jump: digits accumulate until G or enter
+	if isDigitKey(key) && (key != "0" || m.countBuffer != "") {
+		m.countBuffer += key
+		return m, nil
+	}
+	if m.countBuffer != "" {
+		count := m.countBuffer
+		m.countBuffer = ""
+		if key == "G" || key == "enter" {
+			m.jumpToRow(count)
+			return m, nil
+		}
+		if key == "z" {
+			days, _ := strconv.Atoi(count)
+			return m.deferTask(days)
+		}
+	}
+
+	switch key {
+	case "G":
+		m.cursor = len(m.displayTasks) - 1
+		m.moveCursor(0)
+	case "#":
+		m.showNumbers = !m.showNumbers
+	case "w":
+		m.wrapNames = !m.wrapNames
+	case "t":
+		m.toggleFuture()
+	case "]":
+		return m, m.requestDonePage(m.doneOffset + donePageSize)
+	case "[":
+		return m, m.requestDonePage(max(m.doneOffset-donePageSize, 0))
+	case "a":
+		m.createdAge = !m.createdAge
+	case "r":
+		m.nextOccurrence = !m.nextOccurrence
+	case "=":
+		m.alignColumns = !m.alignColumns
+	case "v":
+		m.toggleMarked()
+	case "b":
+		if len(m.bulkTargets()) > 0 {
+			m.inputContext.TransitionTo(ModeBulkSelect)
+			m.inputContext.Category = "bulk"
+		}
 	case "j", "down":
 		m.moveCursor(1)
 	case "k", "up":
 		m.moveCursor(-1)
+	case "}":
+		m.moveCursorToGroup(1)
+	case "{":
+		m.moveCursorToGroup(-1)
+	case "!":
+		m.moveCursorToNextOverdue()
+	case ":":
+		return m.startGotoID()
+	case "ctrl+p":
+		return m.startCommandPalette()
 	case "enter":
 		return m.openTaskEditor()
 	case "f":
 		m.inputContext.TransitionTo(ModeFilterSelect)
 		m.inputContext.Category = "filter"
+	case "S":
+		m.filterState.CycleStatusFilter()
+		m.refreshDisplayTasks()
+	case "~":
+		m.filterState.TogglePendingDone()
+		m.refreshDisplayTasks()
 	case "s":
 		m.inputContext.TransitionTo(ModeSortSelect)
 		m.inputContext.Category = "sort"
@@ -337,8 +834,48 @@ func (m *TaskManagerModel) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 		return m.startSearch()
 	case " ":
 		return m.toggleTaskDone()
+	case "x":
+		if !config.Get().GetDisableXToggle() {
+			return m.toggleTaskDone()
+		}
+	case "X":
+		return m.completeAndArchiveTask()
+	case "u":
+		if undoable {
+			return m.undo()
+		}
+	case "D":
+		return m.handleStartPurge()
+	case "C":
+		return m.handleStartCleanProject()
 	case "n":
 		return m.startNewTask()
+	case "y":
+		return m.duplicateTask()
+	case "z":
+		return m.deferTask(1)
+	case ">":
+		return m.shiftPriority(true)
+	case "<":
+		return m.shiftPriority(false)
+	case "|":
+		return m.startSplitTask()
+	case "?":
+		m.showHelp = true
+		return m, nil
+	case "i":
+		m.showFiles = true
+		return m, nil
+	case "o":
+		return m.openSelectedURL()
+	case "c":
+		m.clearSearch()
+	case "R":
+		m.clearAllFilters()
+	case "+":
+		m.filterBySelectedProject()
+	case "@":
+		m.filterBySelectedContext()
 	}
 	return m, nil
 }
@@ -349,6 +886,12 @@ func (m *TaskManagerModel) handleFilterSelect(msg tea.KeyMsg) (tea.Model, tea.Cm
 		return m.startSearch()
 	case "d":
 		return m.startDateFilter()
+	case "D":
+		m.toggleDuePresenceFilter(DatePresent)
+		m.inputContext.Reset()
+	case "u":
+		m.toggleDuePresenceFilter(DateMissing)
+		m.inputContext.Reset()
 	case "p":
 		return m.startProjectFilter()
 	case "P":
@@ -360,8 +903,47 @@ func (m *TaskManagerModel) handleFilterSelect(msg tea.KeyMsg) (tea.Model, tea.Cm
 		m.filterState.CycleStatusFilter()
 		m.refreshDisplayTasks()
 		m.inputContext.Reset()
+	case "b":
+		m.filterState.CycleBlockedFilter()
+		m.refreshDisplayTasks()
+		m.inputContext.Reset()
+	case "a":
+		m.filterState.Actionable = !m.filterState.Actionable
+		m.refreshDisplayTasks()
+		m.inputContext.Reset()
 	case "f":
 		return m.startFileFilter()
+	case "T":
+		return m.startTagFilter()
+	}
+	return m, nil
+}
+
+// toggleDuePresenceFilter filters to tasks with (DatePresent) or without
+// (DateMissing) a due date, or clears the filter if it's already set
+func (m *TaskManagerModel) toggleDuePresenceFilter(mode DateFilterMode) {
+	if m.filterState.DateFilter != nil && m.filterState.DateFilter.Mode == mode {
+		m.filterState.DateFilter = nil
+	} else {
+		m.filterState.DateFilter = &DateFilter{Mode: mode}
+	}
+	m.refreshDisplayTasks()
+}
+
+func (m *TaskManagerModel) handleBulkSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "p":
+		return m.startBulkPicker("bulk-project", false)
+	case "P":
+		return m.startBulkPicker("bulk-project", true)
+	case "t", "c":
+		return m.startBulkPicker("bulk-context", false)
+	case "T", "C":
+		return m.startBulkPicker("bulk-context", true)
+	case "z":
+		return m.startBulkShift(false)
+	case "Z":
+		return m.startBulkShift(true)
 	}
 	return m, nil
 }
@@ -380,6 +962,9 @@ func (m *TaskManagerModel) handleSortSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 	case "t", "c":
 		m.inputContext.Field = "context"
 		m.inputContext.TransitionTo(ModeSortDirection)
+	case "n":
+		m.inputContext.Field = "name"
+		m.inputContext.TransitionTo(ModeSortDirection)
 	}
 	return m, nil
 }
@@ -392,6 +977,10 @@ func (m *TaskManagerModel) handleGroupSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd
 	case "p":
 		m.inputContext.Field = "project"
 		m.inputContext.TransitionTo(ModeGroupDirection)
+	case "h":
+		m.groupState.CycleProjectDepth()
+		m.refreshDisplayTasks()
+		m.inputContext.Reset()
 	case "P":
 		m.inputContext.Field = "priority"
 		m.inputContext.TransitionTo(ModeGroupDirection)
@@ -502,6 +1091,9 @@ func (m *TaskManagerModel) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 func (m *TaskManagerModel) handleEscape() (tea.Model, tea.Cmd) {
 	// Close any open sub-component
 	if m.confirmationModal != nil {
+		if m.confirmAction == confirmQuit {
+			return m.handleQuitConfirmation(ConfirmationResultMsg{Cancelled: true})
+		}
 		m.confirmationModal = nil
 		m.inputContext.Reset()
 		return m, nil
@@ -531,13 +1123,45 @@ func (m *TaskManagerModel) handleEscape() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
+	// A pending row number is discarded before anything else
+	if m.countBuffer != "" {
+		m.countBuffer = ""
+		return m, nil
+	}
+
+	// Then marked tasks, before filters
+	if len(m.marked) > 0 {
+		m.marked = nil
+		return m, nil
+	}
+
 	// In normal mode, clear filters and file view mode
+	m.fileViewMode = defaultFileViewMode()
+	m.clearAllFilters()
+	m.infoBar.ClearMessage()
+	return m, nil
+}
+
+// clearSearch drops the search query, keeping the other filters
+func (m *TaskManagerModel) clearSearch() {
+	if m.filterState.SearchQuery == "" {
+		m.infoBar.SetMessage("No search to clear")
+		return
+	}
+	m.searchInput.SetValue("")
+	m.filterState.SearchQuery = ""
+	m.refreshDisplayTasks()
+	m.infoBar.SetMessage("Search cleared")
+}
+
+// clearAllFilters resets the search, filters, sort and grouping
+func (m *TaskManagerModel) clearAllFilters() {
+	m.searchInput.SetValue("")
 	m.filterState.Reset()
 	m.sortState.Reset()
 	m.groupState.Reset()
-	m.fileViewMode = FileViewTodoOnly
 	m.refreshDisplayTasks()
-	return m, nil
+	m.infoBar.SetMessage("Filters, sort and grouping cleared")
 }
 
 // Actions
@@ -563,20 +1187,16 @@ func (m *TaskManagerModel) startNewTask() (tea.Model, tea.Cmd) {
 }
 
 func (m *TaskManagerModel) createNewTaskAndOpenEditor(taskName string) (tea.Model, tea.Cmd) {
+	template := m.newTaskTemplate
+	m.newTaskTemplate = nil
 	if strings.TrimSpace(taskName) == "" {
 		m.inputContext.Reset()
 		return m, nil
 	}
 
-	// Generate a unique ID for the new task
-	// Use timestamp + random component to ensure uniqueness
-	timestamp := time.Now().Format("20060102150405")
-	randomPart := fmt.Sprintf("%d", time.Now().UnixNano()%10000)
-	newID := data.HashTaskLine(timestamp + randomPart)
-
 	// Create new task
 	newTask := &data.Task{
-		ID:       newID,
+		ID:       newTaskID(),
 		Name:     taskName,
 		Projects: []string{},
 		Contexts: []string{},
@@ -585,53 +1205,388 @@ func (m *TaskManagerModel) createNewTaskAndOpenEditor(taskName string) (tea.Mode
 		Priority: data.PriorityNone,
 		File:     data.GetTodoFilePath(),
 	}
+	if template != nil {
+		newTask.SetProjects(template.Projects)
+		newTask.SetContexts(template.Contexts)
+		newTask.Priority = template.Priority
+	}
 
 	// Open editor with the new task
 	m.taskEditor = NewTaskEditor(newTask, m.allProjects, m.allContexts)
+	m.taskEditor.StrikeDone = m.strikesDone()
+	m.taskEditor.SetPickerSize(m.width, m.pickerHeight())
 	m.inputContext.TransitionTo(ModeTaskEditor)
 	return m, nil
 }
 
-func (m *TaskManagerModel) startDateFilter() (tea.Model, tea.Cmd) {
-	m.textInput = NewDateInput("Due date filter")
-	m.inputContext.TransitionTo(ModeDateInput)
-	return m, m.textInput.Focus()
+// newTaskID generates a unique ID for a task created in the TUI.
+// Uses timestamp + random component to ensure uniqueness.
+func newTaskID() string {
+	timestamp := time.Now().Format("20060102150405")
+	randomPart := fmt.Sprintf("%d", time.Now().UnixNano()%10000)
+	return data.HashTaskLine(timestamp + randomPart)
 }
 
-func (m *TaskManagerModel) startProjectFilter() (tea.Model, tea.Cmd) {
-	m.fuzzyPicker = NewFuzzyPicker(m.allProjects, "Filter by Project", true, false)
-	m.fuzzyPicker.PreSelect(m.filterState.ProjectFilter)
-	m.pickerContext = "filter-project"
-	m.inputContext.TransitionTo(ModeFuzzyPicker)
-	return m, nil
+// shiftPriority promotes or demotes the selected task's priority one step
+func (m *TaskManagerModel) shiftPriority(promote bool) (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+	updated := *task
+	if promote {
+		updated.PromotePriority(lowestPriority())
+	} else {
+		updated.DemotePriority(lowestPriority())
+	}
+	if updated.Priority == task.Priority {
+		return m, nil
+	}
+	return m, func() tea.Msg {
+		return TaskUpdateMsg{Task: updated}
+	}
 }
 
-func (m *TaskManagerModel) startContextFilter() (tea.Model, tea.Cmd) {
-	m.fuzzyPicker = NewFuzzyPicker(m.allContexts, "Filter by Context", true, false)
-	m.fuzzyPicker.PreSelect(m.filterState.ContextFilter)
-	m.pickerContext = "filter-context"
-	m.inputContext.TransitionTo(ModeFuzzyPicker)
-	return m, nil
+// deferTask pushes the selected task's due date back by days, starting from
+// today when it has none
+func (m *TaskManagerModel) deferTask(days int) (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil || days < 1 {
+		return m, nil
+	}
+	deferred := *task
+	deferred.Tags = maps.Clone(task.Tags)
+	deferred.Defer(days, data.Now())
+	m.infoBar.SetMessage("Deferred to " + data.FormatDisplayDate(deferred.GetDueDate()))
+	return m, func() tea.Msg {
+		return TaskUpdateMsg{Task: deferred}
+	}
 }
 
-func (m *TaskManagerModel) startFileFilter() (tea.Model, tea.Cmd) {
-	m.fuzzyPicker = NewFuzzyPicker(m.allFiles, "Filter by File", true, false)
-	m.fuzzyPicker.PreSelect(m.filterState.FileFilter)
-	m.pickerContext = "filter-file"
-	m.inputContext.TransitionTo(ModeFuzzyPicker)
-	return m, nil
+// duplicateTask creates a pending copy of the selected task in todo.txt
+func (m *TaskManagerModel) duplicateTask() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+
+	dup := task.Duplicate(data.Today())
+	dup.ID = newTaskID()
+	dup.File = data.GetTodoFilePath()
+	return m, func() tea.Msg {
+		return TaskUpdateMsg{Task: dup}
+	}
 }
 
-func (m *TaskManagerModel) cyclePriorityFilter() {
-	priorities := []data.Priority{
-		data.PriorityA, data.PriorityB, data.PriorityC,
-		data.PriorityD, data.PriorityE, data.PriorityF,
+// defaultSplitDelimiter is used when the split prompt is left empty
+const defaultSplitDelimiter = ";"
+
+func (m *TaskManagerModel) startSplitTask() (tea.Model, tea.Cmd) {
+	if m.selectedTask() == nil {
+		return m, nil
+	}
+	label := "Split on (deletes original)"
+	if config.Get().GetSplitCompletesOriginal() {
+		label = "Split on (completes original)"
 	}
+	m.textInput = NewTextInput(label, defaultSplitDelimiter, nil)
+	m.inputContext.TransitionTo(ModeSplitTask)
+	return m, m.textInput.Focus()
+}
 
-	if len(m.filterState.PriorityFilter) == 0 {
-		m.filterState.PriorityFilter = []data.Priority{data.PriorityA}
-	} else {
-		current := m.filterState.PriorityFilter[0]
+// splitSelectedTask replaces the selected task with one new task per
+// sep-separated part of its name. The original is deleted, as wydo split
+// does, or marked done with split_completes_original.
+func (m *TaskManagerModel) splitSelectedTask(sep string) (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+	if sep == "" {
+		sep = defaultSplitDelimiter
+	}
+
+	today := data.Today()
+	parts := task.Split(sep, today)
+	if parts == nil {
+		m.infoBar.SetMessage(fmt.Sprintf("Nothing to split on %q", sep))
+		return m, nil
+	}
+
+	msg := TasksUpdateMsg{}
+	if config.Get().GetSplitCompletesOriginal() {
+		original := *task
+		if !original.Done {
+			original.Complete(today, config.Get().GetPreservePriority())
+		}
+		msg.Tasks = append(msg.Tasks, original)
+	} else {
+		msg.Deleted = []string{task.ID}
+	}
+	for _, part := range parts {
+		part.ID = newTaskID()
+		part.File = data.GetTodoFilePath()
+		msg.Tasks = append(msg.Tasks, part)
+	}
+	return m, func() tea.Msg {
+		return msg
+	}
+}
+
+// openSelectedURL opens the first URL in the selected task, or shows a
+// message if it has none
+func (m *TaskManagerModel) openSelectedURL() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+	url := extractURL(*task)
+	if url == "" {
+		m.infoBar.SetMessage("No URL in task")
+		return m, nil
+	}
+	return m, openURL(url)
+}
+
+func (m *TaskManagerModel) startDateFilter() (tea.Model, tea.Cmd) {
+	m.textInput = NewDateInput("Due date filter")
+	m.inputContext.TransitionTo(ModeDateInput)
+	return m, m.textInput.Focus()
+}
+
+func (m *TaskManagerModel) startProjectFilter() (tea.Model, tea.Cmd) {
+	m.fuzzyPicker = m.newPicker(m.allProjects, "Filter by Project", true, false)
+	m.fuzzyPicker.PreSelect(m.filterState.ProjectFilter)
+	m.pickerContext = "filter-project"
+	m.inputContext.TransitionTo(ModeFuzzyPicker)
+	return m, nil
+}
+
+func (m *TaskManagerModel) startContextFilter() (tea.Model, tea.Cmd) {
+	m.fuzzyPicker = m.newPicker(m.allContexts, "Filter by Context", true, false)
+	m.fuzzyPicker.PreSelect(m.filterState.ContextFilter)
+	m.pickerContext = "filter-context"
+	m.inputContext.TransitionTo(ModeFuzzyPicker)
+	return m, nil
+}
+
+// newPicker creates a fuzzy picker fitted to the space below the info bar
+func (m *TaskManagerModel) newPicker(items []string, title string, multiSelect bool, allowCreate bool) *FuzzyPickerModel {
+	picker := NewFuzzyPicker(items, title, multiSelect, allowCreate)
+	picker.SetSize(m.width, m.pickerHeight())
+	return picker
+}
+
+// pickerHeight is the height available to pickers: the terminal minus the
+// info bar and the blank line after it (0 until the size is known)
+func (m *TaskManagerModel) pickerHeight() int {
+	if m.height == 0 {
+		return 0
+	}
+	return max(m.height-2, 1)
+}
+
+func (m *TaskManagerModel) startTagFilter() (tea.Model, tea.Cmd) {
+	m.fuzzyPicker = m.newPicker(m.allTags, "Filter by Tag", true, false)
+	m.fuzzyPicker.PreSelect(m.filterState.TagFilter)
+	m.pickerContext = "filter-tag"
+	m.inputContext.TransitionTo(ModeFuzzyPicker)
+	return m, nil
+}
+
+// toggleMarked marks or unmarks the task under the cursor for bulk actions
+func (m *TaskManagerModel) toggleMarked() {
+	task := m.selectedTask()
+	if task == nil {
+		return
+	}
+	if m.marked == nil {
+		m.marked = make(map[string]bool)
+	}
+	if m.marked[task.ID] {
+		delete(m.marked, task.ID)
+	} else {
+		m.marked[task.ID] = true
+	}
+}
+
+// bulkTargets returns the marked tasks, or the task under the cursor when
+// nothing is marked
+func (m *TaskManagerModel) bulkTargets() []data.Task {
+	var targets []data.Task
+	for _, t := range m.tasks {
+		if m.marked[t.ID] {
+			targets = append(targets, t)
+		}
+	}
+	if len(targets) == 0 {
+		if task := m.selectedTask(); task != nil {
+			targets = append(targets, *task)
+		}
+	}
+	return targets
+}
+
+// startBulkPicker opens a multi-select picker of projects or contexts to set
+// on the bulk targets. With replace, the chosen set replaces the tasks'
+// existing ones instead of being added to them.
+func (m *TaskManagerModel) startBulkPicker(context string, replace bool) (tea.Model, tea.Cmd) {
+	items, noun := m.allProjects, "Projects"
+	if context == "bulk-context" {
+		items, noun = m.allContexts, "Contexts"
+	}
+	verb := "Add"
+	if replace {
+		verb = "Replace"
+	}
+	m.fuzzyPicker = m.newPicker(items, fmt.Sprintf("%s %s (%d tasks)", verb, noun, len(m.bulkTargets())), true, true)
+	m.pickerContext = context
+	m.inputContext.Direction = ""
+	if replace {
+		m.inputContext.Direction = "replace"
+	}
+	m.inputContext.TransitionTo(ModeFuzzyPicker)
+	return m, nil
+}
+
+// applyBulk sets the selected projects or contexts on every bulk target and
+// sends them as one update, then clears the marks
+func (m *TaskManagerModel) applyBulk(context string, selected []string) tea.Cmd {
+	replace := m.inputContext.Direction == "replace"
+	targets := m.bulkTargets()
+	if context == "bulk-project" && config.Get().GetCascadeProjects() {
+		targets = m.withSubtasks(targets)
+	}
+	m.marked = nil
+
+	// The targets are shallow copies of the live tasks, so build fresh
+	// slices rather than appending into the shared ones
+	for i := range targets {
+		t := &targets[i]
+		if context == "bulk-project" {
+			var base []string
+			if !replace {
+				base = t.Projects
+			}
+			t.SetProjects(append(slices.Clone(base), selected...))
+		} else {
+			var base []string
+			if !replace {
+				base = t.Contexts
+			}
+			t.SetContexts(append(slices.Clone(base), selected...))
+		}
+	}
+	return func() tea.Msg {
+		return TasksUpdateMsg{Tasks: targets}
+	}
+}
+
+// withSubtasks returns targets followed by the subtasks of each target that
+// aren't targets already
+func (m *TaskManagerModel) withSubtasks(targets []data.Task) []data.Task {
+	included := make(map[string]bool)
+	for _, t := range targets {
+		included[t.ID] = true
+	}
+	for _, parent := range targets {
+		for _, i := range data.SubtaskIndexes(m.tasks, parent) {
+			if !included[m.tasks[i].ID] {
+				included[m.tasks[i].ID] = true
+				targets = append(targets, m.tasks[i])
+			}
+		}
+	}
+	return targets
+}
+
+// startBulkShift prompts for the number of days to move the bulk targets'
+// due dates by. With create set, tasks without a due date get one that many
+// days from today; otherwise they are left alone.
+func (m *TaskManagerModel) startBulkShift(create bool) (tea.Model, tea.Cmd) {
+	m.textInput = NewTextInput(fmt.Sprintf("Shift due dates by days (%d tasks)", len(m.bulkTargets())), "7", validateDayCount)
+	m.inputContext.Direction = ""
+	if create {
+		m.inputContext.Direction = "create"
+	}
+	m.inputContext.TransitionTo(ModeBulkShift)
+	return m, m.textInput.Focus()
+}
+
+// validateDayCount accepts a non-zero whole number of days, negative to
+// move dates earlier
+func validateDayCount(s string) error {
+	days, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || days == 0 {
+		return fmt.Errorf("enter a whole number of days, like 7 or -3")
+	}
+	return nil
+}
+
+// shiftBulkDueDates moves the due dates of the bulk targets by days
+// (relative to today, data.Now, for those without one when create is set)
+// and saves them with a single write
+func (m *TaskManagerModel) shiftBulkDueDates(days int, create bool) tea.Cmd {
+	targets := m.bulkTargets()
+	m.marked = nil
+
+	var shifted []data.Task
+	for _, t := range targets {
+		if days == 0 || (t.GetDueDate() == "" && !create) {
+			continue
+		}
+		t.Tags = maps.Clone(t.Tags)
+		t.Defer(days, data.Now())
+		shifted = append(shifted, t)
+	}
+	if len(shifted) == 0 {
+		m.infoBar.SetMessage("No due dates to shift")
+		return nil
+	}
+	m.infoBar.SetMessage(fmt.Sprintf("Shifted %d due date(s) by %d day(s)", len(shifted), days))
+	return func() tea.Msg {
+		return TasksUpdateMsg{Tasks: shifted}
+	}
+}
+
+func (m *TaskManagerModel) startFileFilter() (tea.Model, tea.Cmd) {
+	m.fuzzyPicker = m.newPicker(m.allFiles, "Filter by File", true, false)
+	m.fuzzyPicker.PreSelect(m.filterState.FileFilter)
+	m.pickerContext = "filter-file"
+	m.inputContext.TransitionTo(ModeFuzzyPicker)
+	return m, nil
+}
+
+// filterBySelectedProject filters the list to the selected task's first project
+func (m *TaskManagerModel) filterBySelectedProject() {
+	task := m.selectedTask()
+	if task == nil || len(task.Projects) == 0 {
+		return
+	}
+	m.filterState.ProjectFilter = []string{task.Projects[0]}
+	m.refreshDisplayTasks()
+}
+
+// filterBySelectedContext filters the list to the selected task's first context
+func (m *TaskManagerModel) filterBySelectedContext() {
+	task := m.selectedTask()
+	if task == nil || len(task.Contexts) == 0 {
+		return
+	}
+	m.filterState.ContextFilter = []string{task.Contexts[0]}
+	m.refreshDisplayTasks()
+}
+
+func (m *TaskManagerModel) cyclePriorityFilter() {
+	priorities := []data.Priority{
+		data.PriorityA, data.PriorityB, data.PriorityC,
+		data.PriorityD, data.PriorityE, data.PriorityF,
+	}
+
+	if len(m.filterState.PriorityFilter) == 0 {
+		m.filterState.PriorityFilter = []data.Priority{data.PriorityA}
+	} else {
+		current := m.filterState.PriorityFilter[0]
 		nextIdx := -1
 		for i, p := range priorities {
 			if p == current {
@@ -659,6 +1614,8 @@ func (m *TaskManagerModel) applySortField(ascending bool) {
 		field = SortByPriority
 	case "context":
 		field = SortByContext
+	case "name":
+		field = SortByName
 	}
 
 	m.sortState.Field = field
@@ -695,22 +1652,96 @@ func (m *TaskManagerModel) openTaskEditor() (tea.Model, tea.Cmd) {
 	}
 
 	m.taskEditor = NewTaskEditor(task, m.allProjects, m.allContexts)
+	m.taskEditor.StrikeDone = m.strikesDone()
+	m.taskEditor.SetPickerSize(m.width, m.pickerHeight())
 	m.inputContext.TransitionTo(ModeTaskEditor)
 	return m, nil
 }
 
 func (m *TaskManagerModel) toggleTaskDone() (tea.Model, tea.Cmd) {
-	logs.Logger.Println("space pressed")
+	logs.Logger.Println("toggle done pressed")
 	task := m.selectedTask()
 	if task == nil {
 		logs.Logger.Println("no selected task")
 		return m, nil
 	}
 
-	task.Done = !task.Done
-	return m, func() tea.Msg {
+	update := func() tea.Msg {
 		return TaskUpdateMsg{Task: *task}
 	}
+	if task.Done {
+		// Nothing is pushed to undo, so there's no undo toast either
+		task.Uncomplete()
+		// WriteData forces everything in done.txt to done, so move it back
+		if task.File == data.GetDoneFilePath() {
+			task.File = data.GetTodoFilePath()
+		}
+		return m, update
+	}
+	m.pushUndo(*task)
+	task.Complete(data.Today(), config.Get().GetPreservePriority())
+	return m, tea.Batch(update, m.showUndoToast())
+}
+
+// completeAndArchiveTask marks the selected task done and moves it to
+// done.txt in one update
+func (m *TaskManagerModel) completeAndArchiveTask() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil {
+		return m, nil
+	}
+
+	m.pushUndo(*task)
+	if !task.Done {
+		task.Complete(data.Today(), config.Get().GetPreservePriority())
+	}
+	task.File = data.GetDoneFilePath()
+	return m, tea.Batch(func() tea.Msg {
+		return TaskUpdateMsg{Task: *task}
+	}, m.showUndoToast())
+}
+
+// pushUndo records a copy of task as it was before a single-key complete
+func (m *TaskManagerModel) pushUndo(task data.Task) {
+	task.Tags = maps.Clone(task.Tags)
+	m.undoStack = append(m.undoStack, task)
+	if len(m.undoStack) > maxUndo {
+		m.undoStack = m.undoStack[len(m.undoStack)-maxUndo:]
+	}
+}
+
+// showUndoToast puts the undo toast in the info bar and returns a tick that
+// takes it down after undoToastDuration
+func (m *TaskManagerModel) showUndoToast() tea.Cmd {
+	m.undoSeq++
+	seq := m.undoSeq
+	m.undoDeadline = data.Now().Add(undoToastDuration)
+	m.infoBar.SetMessage(undoToastText)
+	return tea.Tick(undoToastDuration, func(time.Time) tea.Msg {
+		return UndoToastExpiredMsg{seq: seq}
+	})
+}
+
+// undoToastActive reports whether the undo toast is up and within its window
+func (m *TaskManagerModel) undoToastActive() bool {
+	return len(m.undoStack) > 0 && !m.undoDeadline.IsZero() && data.Now().Before(m.undoDeadline)
+}
+
+func (m *TaskManagerModel) dismissUndoToast() {
+	m.undoDeadline = time.Time{}
+	if m.infoBar.Message == undoToastText {
+		m.infoBar.ClearMessage()
+	}
+}
+
+// undo writes back the task state saved by the newest single-key complete
+func (m *TaskManagerModel) undo() (tea.Model, tea.Cmd) {
+	restored := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+	m.infoBar.SetMessage("Restored " + restored.Name)
+	return m, func() tea.Msg {
+		return TaskUpdateMsg{Task: restored}
+	}
 }
 
 // Result handlers
@@ -724,12 +1755,23 @@ func (m *TaskManagerModel) handlePickerResult(msg FuzzyPickerResultMsg) (tea.Mod
 	}
 
 	switch m.pickerContext {
+	case "palette":
+		m.inputContext.Reset()
+		m.pickerContext = ""
+		return m.runPaletteCommand(msg.Selected)
+	case "bulk-project", "bulk-context":
+		cmd := m.applyBulk(m.pickerContext, msg.Selected)
+		m.inputContext.Reset()
+		m.pickerContext = ""
+		return m, cmd
 	case "filter-project":
 		m.filterState.ProjectFilter = msg.Selected
 	case "filter-context":
 		m.filterState.ContextFilter = msg.Selected
 	case "filter-file":
 		m.filterState.FileFilter = msg.Selected
+	case "filter-tag":
+		m.filterState.TagFilter = msg.Selected
 	}
 
 	m.refreshDisplayTasks()
@@ -742,6 +1784,7 @@ func (m *TaskManagerModel) handleTextInputResult(msg TextInputResultMsg) (tea.Mo
 	m.textInput = nil
 
 	if msg.Cancelled {
+		m.newTaskTemplate = nil
 		m.inputContext.Reset()
 		return m, nil
 	}
@@ -752,6 +1795,18 @@ func (m *TaskManagerModel) handleTextInputResult(msg TextInputResultMsg) (tea.Mo
 	} else if m.inputContext.Mode == ModeCreateTask {
 		// Create new task and open editor
 		return m.createNewTaskAndOpenEditor(msg.Value)
+	} else if m.inputContext.Mode == ModeSplitTask {
+		m.inputContext.Reset()
+		return m.splitSelectedTask(msg.Value)
+	} else if m.inputContext.Mode == ModeGotoID {
+		m.inputContext.Reset()
+		m.gotoTaskID(strings.TrimSpace(msg.Value))
+		return m, nil
+	} else if m.inputContext.Mode == ModeBulkShift {
+		create := m.inputContext.Direction == "create"
+		m.inputContext.Reset()
+		days, _ := strconv.Atoi(strings.TrimSpace(msg.Value))
+		return m, m.shiftBulkDueDates(days, create)
 	}
 
 	m.inputContext.Reset()
@@ -767,25 +1822,50 @@ func (m *TaskManagerModel) handleEditorResult(msg TaskEditorResultMsg) (tea.Mode
 	}
 
 	// Send update message
-	return m, func() tea.Msg {
+	update := func() tea.Msg {
 		return TaskUpdateMsg{Task: msg.Task}
 	}
+	if msg.NewSibling {
+		template := msg.Task
+		m.newTaskTemplate = &template
+		_, focus := m.startNewTask()
+		return m, tea.Batch(update, focus)
+	}
+	return m, update
 }
 
 // Helpers
 
 func (m *TaskManagerModel) refreshDisplayTasks() {
 	// Apply filters
-	filtered := ApplyFilters(m.tasks, m.filterState)
+	filtered := ApplyFilters(m.listSource(), m.filterState)
 
 	// Apply file view filter
 	filtered = m.applyFileViewFilter(filtered)
+	filtered = m.hideFutureTasks(filtered)
 
 	// Apply sort
-	sorted := ApplySort(filtered, m.sortState)
+	sorted := ApplySort(filtered, m.sortState.WithinGroups(m.groupState))
+	if m.showsCompletedInline() {
+		sorted = MoveDoneToEnd(sorted)
+	}
 
-	// Apply grouping
-	if m.groupState.IsActive() {
+	// Apply file sections, then grouping
+	m.fileSections = nil
+	if m.showsFileHeaders() {
+		m.fileSections = ApplyFileSections(sorted, m.groupState)
+		// Flatten for cursor navigation, keeping group jumps working
+		m.displayTasks = nil
+		m.taskGroups = nil
+		for _, section := range m.fileSections {
+			for _, g := range section.Groups {
+				m.displayTasks = append(m.displayTasks, g.Tasks...)
+				if m.groupState.IsActive() {
+					m.taskGroups = append(m.taskGroups, g)
+				}
+			}
+		}
+	} else if m.groupState.IsActive() {
 		m.taskGroups = ApplyGroups(sorted, m.groupState)
 		// Flatten for cursor navigation
 		m.displayTasks = nil
@@ -797,6 +1877,8 @@ func (m *TaskManagerModel) refreshDisplayTasks() {
 		m.taskGroups = nil
 	}
 
+	m.restoreCursorForFilter()
+
 	// Clamp cursor
 	if m.cursor >= len(m.displayTasks) {
 		m.cursor = len(m.displayTasks) - 1
@@ -806,6 +1888,54 @@ func (m *TaskManagerModel) refreshDisplayTasks() {
 	}
 }
 
+// currentFilterKey identifies the active filters, search, and file view
+func (m *TaskManagerModel) currentFilterKey() string {
+	return fmt.Sprintf("%s|search=%s|view=%d", m.filterState.Summary(), m.filterState.SearchQuery, m.fileViewMode)
+}
+
+// restoreCursorForFilter remembers the cursor under the previous filter key
+// and, when the key has changed, moves it to where it was last left under
+// the new one (the top for a filter not seen before)
+func (m *TaskManagerModel) restoreCursorForFilter() {
+	key := m.currentFilterKey()
+	if key == m.filterKey {
+		return
+	}
+	if m.cursorMemory == nil {
+		m.cursorMemory = make(map[string]int)
+	}
+	m.cursorMemory[m.filterKey] = m.cursor
+	m.cursor = m.cursorMemory[key]
+	m.filterKey = key
+}
+
+// showsCompletedInline reports whether done tasks are interleaved struck-through
+func (m *TaskManagerModel) showsCompletedInline() bool {
+	return m.inlineCompleted && m.fileViewMode == FileViewAll
+}
+
+// strikesDone reports whether done task names are drawn struck-through
+func (m *TaskManagerModel) strikesDone() bool {
+	return m.strikeDone || m.showsCompletedInline()
+}
+
+// showsFileHeaders reports whether tasks are drawn under a header per file
+func (m *TaskManagerModel) showsFileHeaders() bool {
+	return m.fileHeaders && m.fileViewMode == FileViewAll
+}
+
+// lineOptions returns the task line rendering options for the current view
+func (m *TaskManagerModel) lineOptions() ui.LineOptions {
+	return ui.LineOptions{
+		StrikeDone:     m.strikesDone(),
+		Width:          m.width,
+		Wrap:           m.wrapNames,
+		CreatedAge:     m.createdAge,
+		NextOccurrence: m.nextOccurrence,
+		MetaColumn:     m.metaColumn,
+	}
+}
+
 func (m *TaskManagerModel) moveCursor(delta int) {
 	m.cursor += delta
 	if m.cursor < 0 {
@@ -816,6 +1946,105 @@ func (m *TaskManagerModel) moveCursor(delta int) {
 	}
 }
 
+// isDigitKey reports whether a key is a single digit
+func isDigitKey(key string) bool {
+	return len(key) == 1 && key[0] >= '0' && key[0] <= '9'
+}
+
+// jumpToRow moves the cursor to a 1-based row number, clamping to the list
+func (m *TaskManagerModel) jumpToRow(row string) {
+	n, err := strconv.Atoi(row)
+	if err != nil {
+		return
+	}
+	m.cursor = n - 1
+	m.moveCursor(0)
+}
+
+// groupStartIndices returns the display index of the first task in each group
+func (m *TaskManagerModel) groupStartIndices() []int {
+	var starts []int
+	idx := 0
+	for _, g := range m.taskGroups {
+		if len(g.Tasks) == 0 {
+			continue
+		}
+		starts = append(starts, idx)
+		idx += len(g.Tasks)
+	}
+	return starts
+}
+
+// moveCursorToGroup moves the cursor to the first task of the next (delta > 0)
+// or previous (delta < 0) group. Moving backwards from inside a group first
+// jumps to the start of the current group.
+func (m *TaskManagerModel) moveCursorToGroup(delta int) {
+	if !m.groupState.IsActive() {
+		return
+	}
+	starts := m.groupStartIndices()
+	if len(starts) == 0 {
+		return
+	}
+
+	if delta > 0 {
+		for _, start := range starts {
+			if start > m.cursor {
+				m.cursor = start
+				return
+			}
+		}
+		return
+	}
+
+	for i := len(starts) - 1; i >= 0; i-- {
+		if starts[i] < m.cursor {
+			m.cursor = starts[i]
+			return
+		}
+	}
+}
+
+// moveCursorToNextOverdue moves the cursor to the next pending task, after the
+// current one and wrapping around, whose due date is before today (data.Now)
+func (m *TaskManagerModel) moveCursorToNextOverdue() {
+	today := data.Today()
+	n := len(m.displayTasks)
+	for i := 1; i <= n; i++ {
+		idx := (m.cursor + i) % n
+		t := m.displayTasks[idx]
+		if due := t.ValidDueDate(); !t.Done && due != "" && due < today {
+			m.cursor = idx
+			return
+		}
+	}
+	m.infoBar.SetMessage("No overdue tasks")
+}
+
+// startGotoID prompts for the ID of a task to move the cursor to
+func (m *TaskManagerModel) startGotoID() (tea.Model, tea.Cmd) {
+	m.textInput = NewTextInput("Go to task ID", "ID or prefix (4+ characters)", nil)
+	m.inputContext.TransitionTo(ModeGotoID)
+	return m, m.textInput.Focus()
+}
+
+// gotoTaskID moves the cursor to the task with the given ID or ID prefix,
+// or explains why it can't: no such task, or the view is hiding it
+func (m *TaskManagerModel) gotoTaskID(partialID string) {
+	task, err := data.FindByPartialID(m.tasks, partialID)
+	if err != nil {
+		m.infoBar.SetMessage(err.Error())
+		return
+	}
+	for i, t := range m.displayTasks {
+		if t.ID == task.ID {
+			m.cursor = i
+			return
+		}
+	}
+	m.infoBar.SetMessage(fmt.Sprintf("%q is hidden by the current filters or file view", task.Name))
+}
+
 func (m *TaskManagerModel) selectedTask() *data.Task {
 	if m.cursor >= 0 && m.cursor < len(m.displayTasks) {
 		return &m.displayTasks[m.cursor]
@@ -823,17 +2052,22 @@ func (m *TaskManagerModel) selectedTask() *data.Task {
 	return nil
 }
 
-// handleStartArchive initiates the archive flow
-func (m *TaskManagerModel) handleStartArchive() (tea.Model, tea.Cmd) {
-	// Count completed tasks in todo.txt
+// archiveCount returns how many completed tasks in todo.txt an archive would
+// move, limited to project when it is non-empty
+func (m *TaskManagerModel) archiveCount(project string) int {
 	todoPath := data.GetTodoFilePath()
 	count := 0
 	for _, task := range m.tasks {
-		if task.Done && task.File == todoPath {
+		if task.Done && task.File == todoPath && (project == "" || task.HasProject(project)) {
 			count++
 		}
 	}
+	return count
+}
 
+// handleStartArchive initiates the archive flow
+func (m *TaskManagerModel) handleStartArchive() (tea.Model, tea.Cmd) {
+	count := m.archiveCount("")
 	if count == 0 {
 		return m, tea.Printf("No completed tasks to archive")
 	}
@@ -844,46 +2078,162 @@ func (m *TaskManagerModel) handleStartArchive() (tea.Model, tea.Cmd) {
 		"This will move completed tasks from todo.txt to done.txt",
 		50,
 	)
+	m.confirmAction = confirmArchive
+	m.inputContext.TransitionTo(ModeConfirmation)
+	return m, nil
+}
+
+// handleStartCleanProject asks for confirmation before archiving the
+// completed tasks in the selected task's (first) project
+func (m *TaskManagerModel) handleStartCleanProject() (tea.Model, tea.Cmd) {
+	task := m.selectedTask()
+	if task == nil || len(task.Projects) == 0 {
+		return m, tea.Printf("Selected task has no project")
+	}
+	project := task.Projects[0]
+
+	count := m.archiveCount(project)
+	if count == 0 {
+		return m, tea.Printf("No completed tasks to archive in +%s", project)
+	}
+
+	m.confirmationModal = NewConfirmationModal(
+		fmt.Sprintf("Archive %d completed task(s) in +%s?", count, project),
+		"Other projects' completed tasks stay in todo.txt",
+		50,
+	)
+	m.confirmAction = confirmArchiveProject
+	m.confirmProject = project
+	m.inputContext.TransitionTo(ModeConfirmation)
+	return m, nil
+}
+
+// purgeCount returns how many tasks a purge would delete
+func (m *TaskManagerModel) purgeCount() int {
+	return len(m.tasks) - len(data.PurgeDone(m.tasks, config.Get().GetPurgeDoneFile()))
+}
+
+// handleStartPurge asks for confirmation before deleting completed tasks
+func (m *TaskManagerModel) handleStartPurge() (tea.Model, tea.Cmd) {
+	count := m.purgeCount()
+	if count == 0 {
+		return m, tea.Printf("No completed tasks to purge")
+	}
+
+	details := "This permanently deletes completed tasks from todo.txt"
+	if config.Get().GetPurgeDoneFile() {
+		details = "This permanently deletes completed tasks from todo.txt and done.txt"
+	}
+	m.confirmationModal = NewConfirmationModal(
+		fmt.Sprintf("Purge %d completed task(s)?", count),
+		details,
+		50,
+	)
+	m.confirmAction = confirmPurge
 	m.inputContext.TransitionTo(ModeConfirmation)
 	return m, nil
 }
 
+// isQuitKey reports whether a key quits the app
+func isQuitKey(msg tea.KeyMsg) bool {
+	return msg.String() == "q" || msg.String() == "ctrl+c"
+}
+
+// quitFromEditor quits with the task editor open, first asking whether to
+// save or discard its changes if there are any
+func (m *TaskManagerModel) quitFromEditor() (tea.Model, tea.Cmd) {
+	quit := func() tea.Msg { return QuitRequestMsg{} }
+	if !m.taskEditor.IsModified() {
+		return m, quit
+	}
+	m.confirmationModal = NewConfirmationModal(
+		"Save changes before quitting?",
+		"The task being edited has unsaved changes",
+		50,
+	)
+	m.confirmationModal.AllowDiscard = true
+	m.confirmAction = confirmQuit
+	m.inputContext.TransitionTo(ModeConfirmation)
+	return m, nil
+}
+
+// handleQuitConfirmation saves or discards the editor's changes and quits,
+// or goes back to the editor when cancelled
+func (m *TaskManagerModel) handleQuitConfirmation(msg ConfirmationResultMsg) (tea.Model, tea.Cmd) {
+	m.confirmationModal = nil
+	m.confirmAction = ""
+	editor := m.taskEditor
+	quit := func() tea.Msg { return QuitRequestMsg{} }
+
+	switch {
+	case msg.Confirmed:
+		_, update := m.handleEditorResult(TaskEditorResultMsg{Task: *editor.task, Saved: true})
+		// The update has to reach the app before it flushes writes and quits
+		return m, tea.Sequence(update, quit)
+	case msg.Discarded:
+		*editor.task = editor.originalTask
+		m.handleEditorResult(TaskEditorResultMsg{Task: editor.originalTask, Cancelled: true})
+		return m, quit
+	}
+	m.inputContext.Back()
+	return m, nil
+}
+
 // handleConfirmationResult processes the confirmation modal result
 func (m *TaskManagerModel) handleConfirmationResult(msg ConfirmationResultMsg) (tea.Model, tea.Cmd) {
+	if m.confirmAction == confirmQuit {
+		return m.handleQuitConfirmation(msg)
+	}
+	action := m.confirmAction
+	project := m.confirmProject
 	m.confirmationModal = nil
+	m.confirmAction = ""
+	m.confirmProject = ""
 	m.inputContext.Reset()
 
-	if msg.Confirmed {
-		// Count tasks to archive
-		todoPath := data.GetTodoFilePath()
-		count := 0
-		for _, task := range m.tasks {
-			if task.Done && task.File == todoPath {
-				count++
-			}
+	if !msg.Confirmed {
+		return m, nil
+	}
+
+	switch action {
+	case confirmPurge:
+		count := m.purgeCount()
+		return m, func() tea.Msg {
+			return PurgeRequestMsg{Count: count}
+		}
+	case confirmArchiveProject:
+		count := m.archiveCount(project)
+		return m, func() tea.Msg {
+			return ArchiveRequestMsg{Count: count, Project: project}
 		}
+	default:
+		count := m.archiveCount("")
 		// Send archive request to AppModel
 		return m, func() tea.Msg {
 			return ArchiveRequestMsg{Count: count}
 		}
 	}
-
-	return m, nil
 }
 
 // IsInModalState returns true if the task manager is in a mode that should
 // block global key handling (editor, picker, input, search, or any non-normal mode)
 func (m *TaskManagerModel) IsInModalState() bool {
-	if m.taskEditor != nil || m.fuzzyPicker != nil || m.textInput != nil || m.searchActive || m.confirmationModal != nil {
+	if m.taskEditor != nil || m.fuzzyPicker != nil || m.textInput != nil || m.searchActive || m.confirmationModal != nil || m.showHelp || m.showFiles {
 		return true
 	}
 	return m.inputContext.Mode != ModeNormal
 }
 
-// cycleFileViewMode cycles through file view modes: All -> TodoOnly -> DoneOnly -> All
+// cycleFileViewMode cycles through file view modes: TodoOnly -> All -> DoneOnly -> TodoOnly
 func (m *TaskManagerModel) cycleFileViewMode() {
-	m.fileViewMode = (m.fileViewMode + 1) % 3
-	m.cursor = 0 // Reset cursor position
+	switch m.fileViewMode {
+	case FileViewTodoOnly:
+		m.fileViewMode = FileViewAll
+	case FileViewAll:
+		m.fileViewMode = FileViewDoneOnly
+	default:
+		m.fileViewMode = FileViewTodoOnly
+	}
 }
 
 // fileViewModeString returns a display string for the current file view mode
@@ -898,8 +2248,98 @@ func (m *TaskManagerModel) fileViewModeString() string {
 	}
 }
 
-// applyFileViewFilter filters tasks based on the current file view mode
+// donePageSize is how many done.txt tasks the done.txt view loads at a time
+const donePageSize = 200
+
+// requestDonePage asks for the page of done.txt at offset when the done.txt
+// view is showing
+func (m *TaskManagerModel) requestDonePage(offset int) tea.Cmd {
+	if m.fileViewMode != FileViewDoneOnly {
+		return nil
+	}
+	return func() tea.Msg {
+		return DonePageRequestMsg{Offset: offset, Limit: donePageSize}
+	}
+}
+
+// ShowWarning puts a warning in the info bar until the next key press
+func (m *TaskManagerModel) ShowWarning(warning string) {
+	m.infoBar.SetMessage("Warning: " + warning)
+}
+
+// RefreshDonePage reloads the done.txt view's current page after the files
+// change. It does nothing in the other views.
+func (m *TaskManagerModel) RefreshDonePage() tea.Cmd {
+	return m.requestDonePage(m.doneOffset)
+}
+
+// listSource returns the tasks the list is built from: in the done.txt view,
+// the loaded page of done.txt, using any newer copy of a task from tasks
+// (edits not yet reloaded) and dropping those moved out of done.txt since;
+// otherwise every task
+func (m *TaskManagerModel) listSource() []data.Task {
+	if m.fileViewMode != FileViewDoneOnly || !m.donePaged {
+		return m.tasks
+	}
+	latest := make(map[string]data.Task, len(m.tasks))
+	for _, t := range m.tasks {
+		latest[t.ID] = t
+	}
+	var page []data.Task
+	for _, t := range m.donePage {
+		if cur, ok := latest[t.ID]; ok {
+			t = cur
+		}
+		if t.File == data.GetDoneFilePath() {
+			page = append(page, t)
+		}
+	}
+	return page
+}
+
+// donePageRange describes the loaded window of done.txt, e.g. "201-400", or
+// is empty before a page has loaded
+func (m *TaskManagerModel) donePageRange() string {
+	if !m.donePaged || len(m.donePage) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", m.doneOffset+1, m.doneOffset+len(m.donePage))
+}
+
+// toggleFuture shows or hides the tasks whose threshold date is to come
+func (m *TaskManagerModel) toggleFuture() {
+	m.showFuture = !m.showFuture
+	m.refreshDisplayTasks()
+}
+
+// hideFutureTasks drops pending tasks whose threshold date is after today,
+// unless showFuture is on
+func (m *TaskManagerModel) hideFutureTasks(tasks []data.Task) []data.Task {
+	if m.showFuture {
+		return tasks
+	}
+	today := data.Today()
+	var shown []data.Task
+	for _, task := range tasks {
+		if task.Done || !task.IsFuture(today) {
+			shown = append(shown, task)
+		}
+	}
+	return shown
+}
+
+// applyFileViewFilter filters tasks based on the current file view mode,
+// dropping done tasks outside the done.txt view when hide_done is set
 func (m *TaskManagerModel) applyFileViewFilter(tasks []data.Task) []data.Task {
+	if m.hideDone && m.fileViewMode != FileViewDoneOnly {
+		var pending []data.Task
+		for _, task := range tasks {
+			if !task.Done {
+				pending = append(pending, task)
+			}
+		}
+		tasks = pending
+	}
 	if m.fileViewMode == FileViewAll {
 		return tasks
 	}
@@ -1,10 +1,17 @@
 package components
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/ui"
 )
 
 func TestTaskManager_ForwardsTextInputResultToTaskEditor(t *testing.T) {
@@ -203,6 +210,58 @@ func TestTaskManager_TaskEditorCloseReturnsToTaskList(t *testing.T) {
 	}
 }
 
+func TestTaskManager_EditorSaveAndNewSibling(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "Write report", Priority: data.PriorityB, Projects: []string{"work"}, Contexts: []string{"desk"},
+			Tags: map[string]string{"due": "2024-03-01"}, File: data.GetTodoFilePath()},
+	})
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd := tm.taskEditor.Update(runeKey('n'))
+	result, ok := cmd().(TaskEditorResultMsg)
+	if !ok || !result.Saved || !result.NewSibling {
+		t.Fatalf("expected a save asking for a sibling, got %+v", result)
+	}
+
+	_, cmd = tm.Update(result)
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("expected BatchMsg, got %T", cmd())
+	}
+	saved, ok := batch[0]().(TaskUpdateMsg)
+	if !ok || saved.Task.ID != "1" {
+		t.Fatalf("expected the original task to be saved first, got %+v", saved)
+	}
+	if tm.textInput == nil || tm.inputContext.Mode != ModeCreateTask {
+		t.Fatalf("expected the new task name prompt, got mode %v", tm.inputContext.Mode)
+	}
+
+	tm.Update(TextInputResultMsg{Value: "Send report"})
+	if tm.taskEditor == nil {
+		t.Fatal("expected a fresh editor for the sibling")
+	}
+	sibling := tm.taskEditor.task
+	if sibling.ID == "1" || sibling.Name != "Send report" {
+		t.Errorf("expected a new task named Send report, got %+v", sibling)
+	}
+	if sibling.Priority != data.PriorityB || !slicesEqual(sibling.Projects, []string{"work"}) || !slicesEqual(sibling.Contexts, []string{"desk"}) {
+		t.Errorf("expected inherited priority/projects/contexts, got %+v", sibling)
+	}
+	if sibling.GetDueDate() != "" {
+		t.Errorf("expected no inherited due date, got %q", sibling.GetDueDate())
+	}
+
+	// The template is used once: a plain new task starts empty
+	tm.handleEditorResult(TaskEditorResultMsg{Cancelled: true})
+	tm.startNewTask()
+	tm.Update(TextInputResultMsg{Value: "Unrelated"})
+	if len(tm.taskEditor.task.Projects) != 0 || tm.taskEditor.task.Priority != data.PriorityNone {
+		t.Errorf("expected a plain new task, got %+v", tm.taskEditor.task)
+	}
+}
+
 // Search filter mode tests
 
 func TestTaskManager_SearchStartsInFilterMode(t *testing.T) {
@@ -416,3 +475,1567 @@ func TestTaskManager_SearchDoubleEscExitsSearchMode(t *testing.T) {
 		t.Error("expected search mode to be exited after second esc")
 	}
 }
+
+// Group navigation tests
+
+func newGroupedTaskManager() *TaskManagerModel {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tasks := []data.Task{
+		{Name: "alpha one", Projects: []string{"alpha"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "alpha two", Projects: []string{"alpha"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "beta one", Projects: []string{"beta"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "gamma one", Projects: []string{"gamma"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "gamma two", Projects: []string{"gamma"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+	tm.groupState = GroupState{Field: GroupByProject, Ascending: true}
+	tm.WithTasks(tasks)
+	return tm
+}
+
+func TestTaskManager_NextGroupMovesToFirstTaskOfNextGroup(t *testing.T) {
+	tm := newGroupedTaskManager()
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'}'}})
+	if tm.cursor != 2 {
+		t.Fatalf("expected cursor at 2 (beta), got %d", tm.cursor)
+	}
+	if tm.selectedTask().Name != "beta one" {
+		t.Errorf("expected 'beta one', got '%s'", tm.selectedTask().Name)
+	}
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'}'}})
+	if tm.cursor != 3 {
+		t.Errorf("expected cursor at 3 (gamma), got %d", tm.cursor)
+	}
+
+	// Already in the last group: cursor stays put
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'}'}})
+	if tm.cursor != 3 {
+		t.Errorf("expected cursor to stay at 3, got %d", tm.cursor)
+	}
+}
+
+func TestTaskManager_PrevGroupMovesToFirstTaskOfPrevGroup(t *testing.T) {
+	tm := newGroupedTaskManager()
+	tm.cursor = 4 // gamma two
+
+	// First press jumps to the start of the current group
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'{'}})
+	if tm.cursor != 3 {
+		t.Fatalf("expected cursor at 3 (gamma), got %d", tm.cursor)
+	}
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'{'}})
+	if tm.cursor != 2 {
+		t.Errorf("expected cursor at 2 (beta), got %d", tm.cursor)
+	}
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'{'}})
+	if tm.cursor != 0 {
+		t.Errorf("expected cursor at 0 (alpha), got %d", tm.cursor)
+	}
+}
+
+func TestTaskManager_GroupNavigationNoopWhenUngrouped(t *testing.T) {
+	tm := newGroupedTaskManager()
+	tm.groupState.Reset()
+	tm.refreshDisplayTasks()
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'}'}})
+	if tm.cursor != 0 {
+		t.Errorf("expected cursor to stay at 0, got %d", tm.cursor)
+	}
+}
+
+func TestTaskManager_NextOverdueCycles(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "no due", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "2", Name: "late", Tags: map[string]string{"due": "2024-03-01"}, File: data.GetTodoFilePath()},
+		{ID: "3", Name: "due today", Tags: map[string]string{"due": "2024-03-15"}, File: data.GetTodoFilePath()},
+		{ID: "4", Name: "very late", Tags: map[string]string{"due": "2024-02-01"}, File: data.GetTodoFilePath()},
+		{ID: "5", Name: "upcoming", Tags: map[string]string{"due": "2024-04-01"}, File: data.GetTodoFilePath()},
+	})
+
+	var visited []string
+	for range 3 {
+		tm.handleNormalMode(runeKey('!'))
+		visited = append(visited, tm.selectedTask().Name)
+	}
+	if want := []string{"late", "very late", "late"}; !slicesEqual(visited, want) {
+		t.Errorf("visited %v, want %v", visited, want)
+	}
+}
+
+func TestTaskManager_NextOverdueNoneShowsMessage(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "upcoming", Tags: map[string]string{"due": "2024-04-01"}, File: data.GetTodoFilePath()},
+	})
+
+	tm.handleNormalMode(runeKey('!'))
+	if tm.cursor != 0 {
+		t.Errorf("expected cursor to stay at 0, got %d", tm.cursor)
+	}
+	if tm.infoBar.Message != "No overdue tasks" {
+		t.Errorf("Message = %q, want %q", tm.infoBar.Message, "No overdue tasks")
+	}
+}
+
+// File view mode tests
+
+func TestTaskManager_FileViewCycle(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.fileViewMode = FileViewTodoOnly
+	tasks := []data.Task{
+		{Name: "pending task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "archived task", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	}
+	tm.WithTasks(tasks)
+
+	if len(tm.displayTasks) != 1 || tm.displayTasks[0].Done {
+		t.Fatalf("expected only the pending task in TodoOnly view, got %v", tm.displayTasks)
+	}
+
+	// TodoOnly -> All
+	tm.Update(ToggleFileViewMsg{})
+	if tm.fileViewMode != FileViewAll {
+		t.Fatalf("expected FileViewAll, got %v", tm.fileViewMode)
+	}
+	if len(tm.displayTasks) != 2 {
+		t.Errorf("expected 2 tasks in All view, got %d", len(tm.displayTasks))
+	}
+
+	// All -> DoneOnly
+	tm.Update(ToggleFileViewMsg{})
+	if tm.fileViewMode != FileViewDoneOnly {
+		t.Fatalf("expected FileViewDoneOnly, got %v", tm.fileViewMode)
+	}
+	if len(tm.displayTasks) != 1 || !tm.displayTasks[0].Done {
+		t.Errorf("expected only the done task in DoneOnly view, got %v", tm.displayTasks)
+	}
+
+	// DoneOnly -> TodoOnly
+	tm.Update(ToggleFileViewMsg{})
+	if tm.fileViewMode != FileViewTodoOnly {
+		t.Errorf("expected FileViewTodoOnly, got %v", tm.fileViewMode)
+	}
+}
+
+func TestTaskManager_DoneViewPages(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "p", Name: "pending task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "d1", Name: "first archived", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+		{ID: "d2", Name: "second archived", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	})
+	if _, cmd := tm.handleNormalMode(runeKey(']')); cmd != nil {
+		t.Error("expected ] to do nothing outside the done.txt view")
+	}
+
+	tm.fileViewMode = FileViewAll
+	_, cmd := tm.Update(ToggleFileViewMsg{})
+	if cmd == nil {
+		t.Fatal("expected entering the done.txt view to request a page")
+	}
+	if req, ok := cmd().(DonePageRequestMsg); !ok || req.Offset != 0 || req.Limit != donePageSize {
+		t.Fatalf("expected a request for the first page, got %+v", cmd())
+	}
+
+	// Only the loaded page is listed, with unsaved edits applied
+	tm.Update(DonePageLoadedMsg{Offset: donePageSize, Tasks: []data.Task{
+		{ID: "d2", Name: "second archived", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	}})
+	tm.WithTasks([]data.Task{
+		{ID: "d2", Name: "second edited", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	})
+	if len(tm.displayTasks) != 1 || tm.displayTasks[0].Name != "second edited" {
+		t.Fatalf("expected the edited page task, got %v", tm.displayTasks)
+	}
+	if got := tm.donePageRange(); got != "201-201" {
+		t.Errorf("donePageRange = %q, want 201-201", got)
+	}
+
+	_, cmd = tm.handleNormalMode(runeKey('['))
+	if req, ok := cmd().(DonePageRequestMsg); !ok || req.Offset != 0 {
+		t.Errorf("expected [ to request the previous page, got %+v", cmd())
+	}
+
+	// Paging past the end keeps the current page
+	tm.Update(DonePageLoadedMsg{Offset: 2 * donePageSize})
+	if tm.doneOffset != donePageSize || len(tm.displayTasks) != 1 {
+		t.Errorf("expected the page kept past the end, got offset %d, %v", tm.doneOffset, tm.displayTasks)
+	}
+
+	// A task reopened since the page loaded leaves the view
+	tm.WithTasks([]data.Task{
+		{ID: "d2", Name: "second edited", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+	if len(tm.displayTasks) != 0 {
+		t.Errorf("expected the reopened task dropped, got %v", tm.displayTasks)
+	}
+}
+
+func TestTaskManager_HidesFutureTasksUntilToggled(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "start now", Tags: map[string]string{"t": "2024-03-15"}, File: data.GetTodoFilePath()},
+		{ID: "2", Name: "start later", Tags: map[string]string{"t": "2024-04-01"}, File: data.GetTodoFilePath()},
+	})
+	if len(tm.displayTasks) != 1 || tm.displayTasks[0].Name != "start now" {
+		t.Fatalf("expected the future task hidden by default, got %v", tm.displayTasks)
+	}
+
+	tm.handleNormalMode(runeKey('t'))
+	if len(tm.displayTasks) != 2 {
+		t.Errorf("expected t to show the future task, got %v", tm.displayTasks)
+	}
+	tm.handleNormalMode(runeKey('t'))
+	if len(tm.displayTasks) != 1 {
+		t.Errorf("expected t again to hide it, got %v", tm.displayTasks)
+	}
+}
+
+func TestParseFileViewMode(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected FileViewMode
+	}{
+		{"todo", FileViewTodoOnly},
+		{"all", FileViewAll},
+		{"done", FileViewDoneOnly},
+		{"ALL", FileViewAll},
+		{"", FileViewTodoOnly},
+		{"bogus", FileViewTodoOnly},
+	}
+
+	for _, tc := range tests {
+		if got := ParseFileViewMode(tc.input); got != tc.expected {
+			t.Errorf("ParseFileViewMode(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestTaskManager_SearchLineShowsMatchCount(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tasks := []data.Task{
+		{Name: "buy groceries", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "buy gifts for robert", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "write report", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+	tm.WithTasks(tasks)
+
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	tm = model.(*TaskManagerModel)
+	for _, r := range "bgr" {
+		model, _ = tm.handleSearchMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		tm = model.(*TaskManagerModel)
+	}
+
+	tm.View()
+	line := tm.infoBar.renderSearchLine()
+	if !strings.Contains(line, `Search: "bgr" (2 matches)`) {
+		t.Errorf("expected search line to include match count, got %q", line)
+	}
+
+	// Narrow the query to a single match
+	model, _ = tm.handleSearchMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	tm = model.(*TaskManagerModel)
+	tm.View()
+	line = tm.infoBar.renderSearchLine()
+	if !strings.Contains(line, `Search: "bgrc" (1 match)`) {
+		t.Errorf("expected search line to include singular match count, got %q", line)
+	}
+}
+
+func TestTaskManager_ReloadPreservesEditorEdits(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tasks := []data.Task{
+		{ID: "task1", Name: "first task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "task2", Name: "second task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+	tm.WithTasks(tasks)
+
+	// Open the editor on the second task and change its priority
+	tm.cursor = 1
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyEnter})
+	tm = model.(*TaskManagerModel)
+	tm.taskEditor.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if tm.taskEditor.task.Priority != data.PriorityA {
+		t.Fatalf("expected priority A after edit, got %q", tm.taskEditor.task.Priority)
+	}
+
+	// Simulate a reload with fresh data from disk
+	reloaded := []data.Task{
+		{ID: "task1", Name: "first task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "task2", Name: "second task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+	tm.WithTasks(reloaded)
+
+	if tm.taskEditor == nil {
+		t.Fatal("expected task editor to remain open after reload")
+	}
+	if tm.taskEditor.task.ID != "task2" {
+		t.Errorf("expected editor bound to task2, got %q", tm.taskEditor.task.ID)
+	}
+	if tm.taskEditor.task.Priority != data.PriorityA {
+		t.Errorf("expected in-progress priority A to survive reload, got %q", tm.taskEditor.task.Priority)
+	}
+
+	// Saving after the reload should emit the edited task
+	_, cmd := tm.taskEditor.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	result, ok := cmd().(TaskEditorResultMsg)
+	if !ok {
+		t.Fatal("expected TaskEditorResultMsg")
+	}
+	if result.Task.ID != "task2" || result.Task.Priority != data.PriorityA {
+		t.Errorf("expected saved task2 with priority A, got %s (%q)", result.Task.ID, result.Task.Priority)
+	}
+}
+
+// Empty state tests
+
+func TestTaskManager_EmptyStateMessages(t *testing.T) {
+	t.Run("no tasks", func(t *testing.T) {
+		tm := &TaskManagerModel{}
+		tm.Init()
+		tm.WithTasks(nil)
+
+		if view := tm.View(); !strings.Contains(view, emptyNoTasksMsg) {
+			t.Errorf("expected %q in view, got:\n%s", emptyNoTasksMsg, view)
+		}
+	})
+
+	t.Run("filters exclude everything", func(t *testing.T) {
+		tm := &TaskManagerModel{}
+		tm.Init()
+		tm.WithTasks([]data.Task{
+			{Name: "alpha task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		})
+		tm.filterState.SearchQuery = "zzz"
+		tm.refreshDisplayTasks()
+
+		if view := tm.View(); !strings.Contains(view, emptyNoMatchesMsg) {
+			t.Errorf("expected %q in view, got:\n%s", emptyNoMatchesMsg, view)
+		}
+	})
+
+	t.Run("done view with no done tasks", func(t *testing.T) {
+		tm := &TaskManagerModel{}
+		tm.Init()
+		tm.fileViewMode = FileViewDoneOnly
+		tm.WithTasks([]data.Task{
+			{Name: "alpha task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		})
+
+		if view := tm.View(); !strings.Contains(view, emptyNoDoneTasksMsg) {
+			t.Errorf("expected %q in view, got:\n%s", emptyNoDoneTasksMsg, view)
+		}
+	})
+}
+
+func TestProjectManager_EmptyStateMessage(t *testing.T) {
+	pm := &ProjectManagerModel{}
+	pm.WithProjects(map[string]data.Project{})
+
+	if view := pm.View(); !strings.Contains(view, emptyNoProjectsMsg) {
+		t.Errorf("expected %q in view, got:\n%s", emptyNoProjectsMsg, view)
+	}
+
+	pm.WithProjects(map[string]data.Project{"work": {Name: "work"}})
+	if view := pm.View(); strings.Contains(view, emptyNoProjectsMsg) || !strings.Contains(view, "+work") {
+		t.Errorf("expected project list without empty state, got:\n%s", view)
+	}
+}
+
+func TestTaskManager_FilterBySelectedProjectAndContext(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tasks := []data.Task{
+		{Name: "plan launch", Projects: []string{"bigproject"}, Contexts: []string{"office"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "write spec", Projects: []string{"bigproject"}, Contexts: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "buy milk", Contexts: []string{"office"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+	tm.WithTasks(tasks)
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	if len(tm.filterState.ProjectFilter) != 1 || tm.filterState.ProjectFilter[0] != "bigproject" {
+		t.Fatalf("expected project filter [bigproject], got %v", tm.filterState.ProjectFilter)
+	}
+	if len(tm.displayTasks) != 2 {
+		t.Errorf("expected 2 tasks in bigproject, got %d", len(tm.displayTasks))
+	}
+
+	tm.filterState.Reset()
+	tm.refreshDisplayTasks()
+	tm.cursor = 0
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'@'}})
+	if len(tm.filterState.ContextFilter) != 1 || tm.filterState.ContextFilter[0] != "office" {
+		t.Fatalf("expected context filter [office], got %v", tm.filterState.ContextFilter)
+	}
+	if len(tm.displayTasks) != 2 {
+		t.Errorf("expected 2 tasks in @office, got %d", len(tm.displayTasks))
+	}
+}
+
+func TestTaskManager_FilterBySelectedProjectNoopWithoutProject(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "buy milk", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'+'}})
+	if len(tm.filterState.ProjectFilter) != 0 {
+		t.Errorf("expected no project filter, got %v", tm.filterState.ProjectFilter)
+	}
+}
+
+func TestTaskManager_InlineCompletedSortsDoneLast(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.inlineCompleted = true
+	tm.fileViewMode = FileViewAll
+	tm.groupState = GroupState{Field: GroupByProject, Ascending: true}
+	tasks := []data.Task{
+		{Name: "a done", Done: true, Projects: []string{"a"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "a pending", Projects: []string{"a"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "b archived", Done: true, Projects: []string{"b"}, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+		{Name: "b pending", Projects: []string{"b"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+	tm.WithTasks(tasks)
+
+	var names []string
+	for _, task := range tm.displayTasks {
+		names = append(names, task.Name)
+	}
+	expected := []string{"a pending", "a done", "b pending", "b archived"}
+	if !slicesEqual(names, expected) {
+		t.Errorf("display order = %v, want %v", names, expected)
+	}
+	if !tm.lineOptions().StrikeDone {
+		t.Error("expected done tasks to be struck through in the All view")
+	}
+
+	// Outside the All view the option has no effect
+	tm.fileViewMode = FileViewTodoOnly
+	tm.refreshDisplayTasks()
+	if tm.lineOptions().StrikeDone {
+		t.Error("expected no strikethrough outside the All view")
+	}
+}
+
+func TestTaskManager_FileHeaders(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.fileHeaders = true
+	tm.fileViewMode = FileViewAll
+	tm.groupState = GroupState{Field: GroupByProject, Ascending: true}
+	tm.WithTasks([]data.Task{
+		{Name: "archived", Done: true, Projects: []string{"a"}, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+		{Name: "b pending", Projects: []string{"b"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "a pending", Projects: []string{"a"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	var names []string
+	for _, task := range tm.displayTasks {
+		names = append(names, task.Name)
+	}
+	if want := []string{"a pending", "b pending", "archived"}; !slicesEqual(names, want) {
+		t.Errorf("display order = %v, want todo.txt's tasks then done.txt's: %v", names, want)
+	}
+
+	out := tm.renderFileSections()
+	todo := strings.Index(out, "── todo.txt ──")
+	done := strings.Index(out, "── done.txt ──")
+	if todo < 0 || done < 0 || todo > done {
+		t.Fatalf("expected todo.txt then done.txt headers, got:\n%s", out)
+	}
+	// Groups are nested inside each file
+	if strings.Count(out, "── a ──") != 2 || !strings.Contains(out[todo:done], "── b ──") {
+		t.Errorf("expected project groups nested under each file, got:\n%s", out)
+	}
+
+	// Group jumps still follow the nested groups: todo/a, todo/b, done/a
+	tm.moveCursorToGroup(1)
+	tm.moveCursorToGroup(1)
+	if tm.selectedTask().Name != "archived" {
+		t.Errorf("expected to land on done.txt's group, got %q", tm.selectedTask().Name)
+	}
+
+	// Headers only show in the All view
+	tm.fileViewMode = FileViewTodoOnly
+	tm.refreshDisplayTasks()
+	if tm.fileSections != nil {
+		t.Error("expected no file sections outside the All view")
+	}
+}
+
+func TestApplyFileSections_Ungrouped(t *testing.T) {
+	sections := ApplyFileSections([]data.Task{
+		{Name: "one", File: "/tmp/work.txt"},
+		{Name: "two", File: data.GetTodoFilePath()},
+		{Name: "three", File: "/tmp/work.txt"},
+	}, GroupState{})
+
+	if len(sections) != 2 || sections[0].File != "todo.txt" || sections[1].File != "work.txt" {
+		t.Fatalf("sections = %+v, want todo.txt then work.txt", sections)
+	}
+	if len(sections[1].Groups) != 1 || len(sections[1].Groups[0].Tasks) != 2 {
+		t.Errorf("expected work.txt's tasks in a single group, got %+v", sections[1].Groups)
+	}
+}
+
+func TestTaskManager_BlockedFilter(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "open", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "on hold", Tags: map[string]string{"blocked": "legal"}, File: data.GetTodoFilePath()},
+		{Name: "waiting", Contexts: []string{"waiting"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	names := func() []string {
+		var names []string
+		for _, task := range tm.displayTasks {
+			names = append(names, task.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		summary string
+		want    []string
+	}{
+		{"blocked", []string{"on hold", "waiting"}},
+		{"unblocked", []string{"open"}},
+		{"", []string{"open", "on hold", "waiting"}},
+	}
+	for _, tc := range tests {
+		pressKeys(tm, runeKey('f'))
+		tm.handleFilterSelect(runeKey('b'))
+		if got := names(); !slicesEqual(got, tc.want) {
+			t.Errorf("%q: display = %v, want %v", tc.summary, got, tc.want)
+		}
+		if got := tm.filterState.Summary(); got != tc.summary {
+			t.Errorf("summary = %q, want %q", got, tc.summary)
+		}
+	}
+}
+
+// Row number tests
+
+func newNumberedTaskManager(count int) *TaskManagerModel {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	var tasks []data.Task
+	for i := 0; i < count; i++ {
+		tasks = append(tasks, data.Task{Name: fmt.Sprintf("task %d", i+1), Tags: make(map[string]string), File: data.GetTodoFilePath()})
+	}
+	tm.WithTasks(tasks)
+	return tm
+}
+
+func pressKeys(tm *TaskManagerModel, keys ...tea.KeyMsg) {
+	for _, k := range keys {
+		tm.handleNormalMode(k)
+	}
+}
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestTaskManager_NumericJump(t *testing.T) {
+	tm := newNumberedTaskManager(15)
+
+	pressKeys(tm, runeKey('1'), runeKey('2'), runeKey('G'))
+	if tm.cursor != 11 {
+		t.Errorf("expected cursor at row 12 (index 11), got %d", tm.cursor)
+	}
+
+	pressKeys(tm, runeKey('3'), tea.KeyMsg{Type: tea.KeyEnter})
+	if tm.cursor != 2 {
+		t.Errorf("expected cursor at row 3 (index 2), got %d", tm.cursor)
+	}
+	if tm.taskEditor != nil {
+		t.Error("enter after a number should jump, not open the editor")
+	}
+
+	// Numbers past the end clamp to the last row
+	pressKeys(tm, runeKey('9'), runeKey('9'), runeKey('G'))
+	if tm.cursor != 14 {
+		t.Errorf("expected cursor clamped to last index 14, got %d", tm.cursor)
+	}
+}
+
+func TestTaskManager_ToggleRowNumbers(t *testing.T) {
+	tm := newNumberedTaskManager(12)
+
+	if strings.Contains(tm.renderFlatTasks(), "12 ") {
+		t.Error("expected no row numbers by default")
+	}
+
+	pressKeys(tm, runeKey('#'))
+	out := tm.renderFlatTasks()
+	if !strings.Contains(out, " 1 ") || !strings.Contains(out, "12 ") {
+		t.Errorf("expected padded row numbers, got:\n%s", out)
+	}
+}
+
+func TestTaskManager_StatusQuickFilterCycles(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "pending", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "done", Done: true, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	tests := []struct {
+		status   StatusFilter
+		expected []string
+	}{
+		{StatusPending, []string{"pending"}},
+		{StatusDone, []string{"done"}},
+		{StatusAll, []string{"pending", "done"}},
+	}
+
+	for _, tc := range tests {
+		pressKeys(tm, runeKey('S'))
+		if tm.filterState.StatusFilter != tc.status {
+			t.Errorf("StatusFilter = %v, want %v", tm.filterState.StatusFilter, tc.status)
+		}
+		var names []string
+		for _, task := range tm.displayTasks {
+			names = append(names, task.Name)
+		}
+		if !slicesEqual(names, tc.expected) {
+			t.Errorf("status %q: displayed %v, want %v", tm.filterState.StatusFilterString(), names, tc.expected)
+		}
+	}
+}
+
+func TestTaskManager_CompleteAndArchive(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "ship it", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	_, cmd := tm.handleNormalMode(runeKey('X'))
+	msg := completeUpdateFrom(t, cmd)
+	if !msg.Task.Done || msg.Task.CompletionDate == "" {
+		t.Errorf("expected task to be completed, got %+v", msg.Task)
+	}
+	if msg.Task.File != data.GetDoneFilePath() {
+		t.Errorf("File = %q, want %q", msg.Task.File, data.GetDoneFilePath())
+	}
+}
+
+// completeUpdateFrom returns the TaskUpdateMsg from a single-key complete,
+// which is batched with the undo toast's expiry tick (not run, as it sleeps)
+func completeUpdateFrom(t *testing.T, cmd tea.Cmd) TaskUpdateMsg {
+	t.Helper()
+	if cmd == nil {
+		t.Fatal("expected an update command")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok {
+		t.Fatalf("expected BatchMsg, got %T", cmd())
+	}
+	msg, ok := batch[0]().(TaskUpdateMsg)
+	if !ok {
+		t.Fatalf("expected TaskUpdateMsg, got %T", batch[0]())
+	}
+	return msg
+}
+
+func TestTaskManager_UndoToastRestoresArchivedTask(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	data.Now = func() time.Time { return now }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "ship it", Priority: data.PriorityA, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	_, cmd := tm.handleNormalMode(runeKey('X'))
+	completeUpdateFrom(t, cmd)
+	if tm.infoBar.Message != undoToastText {
+		t.Fatalf("Message = %q, want the undo toast", tm.infoBar.Message)
+	}
+
+	now = now.Add(2 * time.Second)
+	_, cmd = tm.handleNormalMode(runeKey('u'))
+	if cmd == nil {
+		t.Fatal("expected u to restore the task while the toast is up")
+	}
+	restored := cmd().(TaskUpdateMsg).Task
+	if restored.Done || restored.File != data.GetTodoFilePath() || restored.Priority != data.PriorityA {
+		t.Errorf("restored = %+v, want the pending task back in todo.txt", restored)
+	}
+
+	// Nothing left to undo
+	if _, cmd = tm.handleNormalMode(runeKey('u')); cmd != nil {
+		t.Error("expected a second u to do nothing")
+	}
+}
+
+func TestTaskManager_UncompleteShowsNoUndoToast(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "ship it", Done: true, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	_, cmd := tm.handleNormalMode(runeKey(' '))
+	if cmd == nil {
+		t.Fatal("expected an update command")
+	}
+	if msg, ok := cmd().(TaskUpdateMsg); !ok || msg.Task.Done {
+		t.Errorf("expected a pending TaskUpdateMsg, got %+v", cmd())
+	}
+	if tm.infoBar.Message == undoToastText || len(tm.undoStack) != 0 {
+		t.Errorf("Message = %q, undo stack %d, want no undo offered", tm.infoBar.Message, len(tm.undoStack))
+	}
+}
+
+func TestTaskManager_UndoToastExpires(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	data.Now = func() time.Time { return now }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "ship it", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	tm.handleNormalMode(runeKey(' '))
+	tm.Update(UndoToastExpiredMsg{seq: tm.undoSeq})
+	if tm.infoBar.Message != "" {
+		t.Errorf("Message = %q, want the toast gone after it expires", tm.infoBar.Message)
+	}
+
+	tm.handleNormalMode(runeKey(' ')) // uncomplete: nothing to undo
+	tm.handleNormalMode(runeKey(' '))
+	now = now.Add(undoToastDuration)
+	if _, cmd := tm.handleNormalMode(runeKey('u')); cmd != nil {
+		t.Error("expected u to do nothing once the window has passed")
+	}
+}
+
+func TestTaskManager_FitsNamesToWindowWidth(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "a very long task name that will not fit", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+	tm.Update(tea.WindowSizeMsg{Width: 30, Height: 20})
+
+	out := strings.TrimRight(tm.renderFlatTasks(), "\n")
+	if !strings.HasSuffix(out, ui.Ellipsis) {
+		t.Errorf("expected truncated name, got %q", out)
+	}
+	if w := lipgloss.Width(out); w != 30 {
+		t.Errorf("row width = %d, want 30", w)
+	}
+
+	pressKeys(tm, runeKey('w'))
+	out = strings.TrimRight(tm.renderFlatTasks(), "\n")
+	lines := strings.Split(out, "\n")
+	if len(lines) < 2 {
+		t.Fatalf("expected wrapped name, got %q", out)
+	}
+	if !strings.HasPrefix(lines[1], strings.Repeat(" ", 6)) {
+		t.Errorf("expected continuation aligned under the name, got %q", lines[1])
+	}
+}
+
+func TestTaskManager_PurgeRequiresConfirmation(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "pending", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "done", Done: true, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "archived", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	})
+
+	tm.handleNormalMode(runeKey('D'))
+	if tm.confirmationModal == nil {
+		t.Fatal("expected a confirmation modal")
+	}
+
+	// Cancelling does nothing
+	_, cmd := tm.handleConfirmationResult(ConfirmationResultMsg{Cancelled: true})
+	if cmd != nil {
+		t.Error("expected no command after cancelling")
+	}
+
+	tm.handleNormalMode(runeKey('D'))
+	_, cmd = tm.handleConfirmationResult(ConfirmationResultMsg{Confirmed: true})
+	if cmd == nil {
+		t.Fatal("expected a purge request")
+	}
+	msg, ok := cmd().(PurgeRequestMsg)
+	if !ok {
+		t.Fatalf("expected PurgeRequestMsg, got %T", cmd())
+	}
+	if msg.Count != 1 {
+		t.Errorf("Count = %d, want 1", msg.Count)
+	}
+}
+
+func TestTaskManager_CleanProjectArchivesOnlyThatProject(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "pending", Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "shipped", Done: true, Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "groceries", Done: true, Projects: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+	for i, task := range tm.displayTasks {
+		if task.Name == "pending" {
+			tm.cursor = i
+		}
+	}
+
+	tm.handleNormalMode(runeKey('C'))
+	if tm.confirmationModal == nil {
+		t.Fatal("expected a confirmation modal")
+	}
+	_, cmd := tm.handleConfirmationResult(ConfirmationResultMsg{Confirmed: true})
+	if cmd == nil {
+		t.Fatal("expected an archive request")
+	}
+	msg, ok := cmd().(ArchiveRequestMsg)
+	if !ok {
+		t.Fatalf("expected ArchiveRequestMsg, got %T", cmd())
+	}
+	if msg.Project != "work" || msg.Count != 1 {
+		t.Errorf("got Project %q Count %d, want work and 1", msg.Project, msg.Count)
+	}
+}
+
+func TestTaskManager_SplitTask(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "pack; book hotel; rent car", Priority: data.PriorityA, Projects: []string{"trip"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	pressKeys(tm, runeKey('|'))
+	if tm.inputContext.Mode != ModeSplitTask || tm.textInput == nil {
+		t.Fatalf("expected split prompt, got mode %v", tm.inputContext.Mode)
+	}
+
+	_, cmd := tm.handleTextInputResult(TextInputResultMsg{Value: ""})
+	if cmd == nil {
+		t.Fatal("expected an update command")
+	}
+	msg, ok := cmd().(TasksUpdateMsg)
+	if !ok {
+		t.Fatalf("expected TasksUpdateMsg, got %T", cmd())
+	}
+	// Like wydo split, the original is deleted by default
+	if !slicesEqual(msg.Deleted, []string{"1"}) {
+		t.Errorf("Deleted = %v, want the original", msg.Deleted)
+	}
+	var names []string
+	for _, part := range msg.Tasks {
+		names = append(names, part.Name)
+		if part.ID == "" || part.ID == "1" || part.Priority != data.PriorityA || !part.HasProject("trip") {
+			t.Errorf("unexpected part %+v", part)
+		}
+	}
+	if !slicesEqual(names, []string{"pack", "book hotel", "rent car"}) {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestTaskManager_SplitTaskCompletesOriginal(t *testing.T) {
+	defer func(orig bool) { config.Get().SplitCompletesOriginal = orig }(config.Get().SplitCompletesOriginal)
+	config.Get().SplitCompletesOriginal = true
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "pack; book hotel", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	pressKeys(tm, runeKey('|'))
+	_, cmd := tm.handleTextInputResult(TextInputResultMsg{Value: ";"})
+	msg := cmd().(TasksUpdateMsg)
+	if len(msg.Deleted) != 0 {
+		t.Errorf("Deleted = %v, want the original kept", msg.Deleted)
+	}
+	if len(msg.Tasks) != 3 {
+		t.Fatalf("expected original plus 2 parts, got %d tasks", len(msg.Tasks))
+	}
+	if original := msg.Tasks[0]; original.ID != "1" || !original.Done {
+		t.Errorf("expected the original completed, got %+v", original)
+	}
+}
+
+func TestTaskManager_BulkSetProject(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "one", Projects: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "2", Name: "two", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "3", Name: "three", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	// Mark the first two tasks, then add +work to both
+	pressKeys(tm, runeKey('v'), runeKey('j'), runeKey('v'), runeKey('b'))
+	if tm.inputContext.Mode != ModeBulkSelect {
+		t.Fatalf("expected bulk mode, got %v", tm.inputContext.Mode)
+	}
+	tm.handleBulkSelect(runeKey('p'))
+	if tm.fuzzyPicker == nil || !tm.fuzzyPicker.MultiSelect {
+		t.Fatal("expected a multi-select project picker")
+	}
+
+	_, cmd := tm.handlePickerResult(FuzzyPickerResultMsg{Selected: []string{"work"}})
+	if cmd == nil {
+		t.Fatal("expected an update command")
+	}
+	msg, ok := cmd().(TasksUpdateMsg)
+	if !ok {
+		t.Fatalf("expected TasksUpdateMsg, got %T", cmd())
+	}
+	if len(msg.Tasks) != 2 {
+		t.Fatalf("expected 2 updated tasks, got %d", len(msg.Tasks))
+	}
+	if !slicesEqual(msg.Tasks[0].Projects, []string{"home", "work"}) {
+		t.Errorf("task one projects = %v, want [home work]", msg.Tasks[0].Projects)
+	}
+	if !slicesEqual(msg.Tasks[1].Projects, []string{"work"}) {
+		t.Errorf("task two projects = %v, want [work]", msg.Tasks[1].Projects)
+	}
+	if len(tm.marked) != 0 {
+		t.Error("expected marks to be cleared after the bulk action")
+	}
+}
+
+func TestTaskManager_BulkSetProjectLeavesLiveTasksAlone(t *testing.T) {
+	// Spare capacity means an append would write into the live task's array
+	projects := make([]string, 1, 4)
+	projects[0] = "zoo"
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "one", Projects: projects, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	pressKeys(tm, runeKey('b'))
+	tm.handleBulkSelect(runeKey('p'))
+	_, cmd := tm.handlePickerResult(FuzzyPickerResultMsg{Selected: []string{"home"}})
+	msg := cmd().(TasksUpdateMsg)
+
+	if !slicesEqual(msg.Tasks[0].Projects, []string{"home", "zoo"}) {
+		t.Errorf("updated projects = %v, want [home zoo]", msg.Tasks[0].Projects)
+	}
+	if got := projects[:2]; !slicesEqual(got, []string{"zoo", ""}) {
+		t.Errorf("live task's projects array = %v, want it untouched", got)
+	}
+}
+
+func TestTaskManager_BulkShiftDueDates(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "one", Tags: map[string]string{"due": "2024-03-20"}, File: data.GetTodoFilePath()},
+		{ID: "2", Name: "two", Tags: map[string]string{"due": "2024-04-01"}, File: data.GetTodoFilePath()},
+		{ID: "3", Name: "three", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	// Mark all three; the one without a due date is skipped
+	pressKeys(tm, runeKey('v'), runeKey('j'), runeKey('v'), runeKey('j'), runeKey('v'), runeKey('b'))
+	tm.handleBulkSelect(runeKey('z'))
+	if tm.textInput == nil || tm.inputContext.Mode != ModeBulkShift {
+		t.Fatalf("expected a days prompt, got mode %v", tm.inputContext.Mode)
+	}
+
+	_, cmd := tm.handleTextInputResult(TextInputResultMsg{Value: "7"})
+	if cmd == nil {
+		t.Fatal("expected an update command")
+	}
+	msg, ok := cmd().(TasksUpdateMsg)
+	if !ok {
+		t.Fatalf("expected TasksUpdateMsg, got %T", cmd())
+	}
+	var dues []string
+	for _, task := range msg.Tasks {
+		dues = append(dues, task.GetDueDate())
+	}
+	if want := []string{"2024-03-27", "2024-04-08"}; !slicesEqual(dues, want) {
+		t.Errorf("due dates = %v, want %v", dues, want)
+	}
+	if tm.tasks[0].GetDueDate() != "2024-03-20" {
+		t.Error("shifting must not modify the displayed task before the update is applied")
+	}
+	if len(tm.marked) != 0 {
+		t.Error("expected marks to be cleared after the bulk action")
+	}
+
+	// Z also gives tasks without a due date one, counted from today
+	tm.cursor = 2
+	pressKeys(tm, runeKey('b'))
+	tm.handleBulkSelect(runeKey('Z'))
+	_, cmd = tm.handleTextInputResult(TextInputResultMsg{Value: "-2"})
+	msg = cmd().(TasksUpdateMsg)
+	if len(msg.Tasks) != 1 || msg.Tasks[0].GetDueDate() != "2024-03-13" {
+		t.Errorf("expected three due 2024-03-13, got %+v", msg.Tasks)
+	}
+
+	if validateDayCount("0") == nil || validateDayCount("soon") == nil || validateDayCount(" -3 ") != nil {
+		t.Error("expected only non-zero whole numbers of days to be accepted")
+	}
+}
+
+func TestTaskManager_BulkReplaceContexts(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "one", Contexts: []string{"home", "phone"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	// Nothing marked: the task under the cursor is the target
+	pressKeys(tm, runeKey('b'))
+	tm.handleBulkSelect(runeKey('T'))
+	_, cmd := tm.handlePickerResult(FuzzyPickerResultMsg{Selected: []string{"office"}})
+	msg := cmd().(TasksUpdateMsg)
+	if len(msg.Tasks) != 1 || !slicesEqual(msg.Tasks[0].Contexts, []string{"office"}) {
+		t.Errorf("expected contexts replaced with [office], got %+v", msg.Tasks)
+	}
+}
+
+func TestTaskManager_DeferTask(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC) }
+
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "1", Name: "no due", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "2", Name: "has due", Tags: map[string]string{"due": "2024-04-01"}, File: data.GetTodoFilePath()},
+	})
+
+	_, cmd := tm.handleNormalMode(runeKey('z'))
+	msg := cmd().(TaskUpdateMsg)
+	if got := msg.Task.GetDueDate(); got != "2024-03-16" {
+		t.Errorf("due = %q, want tomorrow %q", got, "2024-03-16")
+	}
+
+	pressKeys(tm, runeKey('j'), runeKey('3'))
+	_, cmd = tm.handleNormalMode(runeKey('z'))
+	msg = cmd().(TaskUpdateMsg)
+	if got := msg.Task.GetDueDate(); got != "2024-04-04" {
+		t.Errorf("due = %q, want %q", got, "2024-04-04")
+	}
+	if tm.tasks[1].GetDueDate() != "2024-04-01" {
+		t.Error("deferring must not modify the displayed task before the update is applied")
+	}
+}
+
+func TestTaskManager_TagFilterNarrowsList(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "t1", Name: "Write report", Tags: map[string]string{"est": "1h"}, File: data.GetTodoFilePath()},
+		{ID: "t2", Name: "Review PR", Tags: map[string]string{"est": "2h"}, File: data.GetTodoFilePath()},
+		{ID: "t3", Name: "Call bank", Tags: map[string]string{}, File: data.GetTodoFilePath()},
+	})
+
+	tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'f'}})
+	tm.handleFilterSelect(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'T'}})
+	if tm.pickerContext != "filter-tag" || tm.fuzzyPicker == nil {
+		t.Fatalf("expected tag picker, got context %q", tm.pickerContext)
+	}
+
+	tm.handlePickerResult(FuzzyPickerResultMsg{Selected: []string{"est:1h"}})
+
+	if names := taskNames(tm.displayTasks); len(names) != 1 || names[0] != "Write report" {
+		t.Errorf("expected only %q, got %v", "Write report", names)
+	}
+	if !strings.Contains(tm.filterState.Summary(), "tag=est:1h") {
+		t.Errorf("expected summary to mention the tag, got %q", tm.filterState.Summary())
+	}
+}
+
+func TestTaskManager_TogglePendingDoneKeepsProjectFilter(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "write docs", Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "ship release", Done: true, Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "mow lawn", Projects: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "fix sink", Done: true, Projects: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+	tm.filterState.ProjectFilter = []string{"work"}
+	tm.refreshDisplayTasks()
+
+	want := []struct {
+		status StatusFilter
+		name   string
+	}{
+		{StatusPending, "write docs"},
+		{StatusDone, "ship release"},
+		{StatusPending, "write docs"},
+	}
+	for _, w := range want {
+		tm.handleNormalMode(runeKey('~'))
+		if tm.filterState.StatusFilter != w.status {
+			t.Fatalf("StatusFilter = %v, want %v", tm.filterState.StatusFilter, w.status)
+		}
+		if names := taskNames(tm.displayTasks); len(names) != 1 || names[0] != w.name {
+			t.Errorf("status %v: got %v, want [%s]", w.status, names, w.name)
+		}
+		if len(tm.filterState.ProjectFilter) != 1 || tm.filterState.ProjectFilter[0] != "work" {
+			t.Fatalf("project filter changed to %v", tm.filterState.ProjectFilter)
+		}
+	}
+}
+
+func TestTaskManager_CursorRememberedPerFilter(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "work 1", Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "home 1", Projects: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "work 2", Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "home 2", Projects: []string{"home"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "work 3", Projects: []string{"work"}, Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	tm.filterState.ProjectFilter = []string{"work"}
+	tm.refreshDisplayTasks()
+	pressKeys(tm, runeKey('j'), runeKey('j'))
+	if tm.cursor != 2 {
+		t.Fatalf("cursor = %d, want 2", tm.cursor)
+	}
+
+	tm.filterState.ProjectFilter = []string{"home"}
+	tm.refreshDisplayTasks()
+	if tm.cursor != 0 {
+		t.Errorf("cursor under a new filter = %d, want 0", tm.cursor)
+	}
+	pressKeys(tm, runeKey('j'))
+
+	tm.filterState.ProjectFilter = []string{"work"}
+	tm.refreshDisplayTasks()
+	if tm.cursor != 2 || tm.displayTasks[tm.cursor].Name != "work 3" {
+		t.Errorf("cursor back under the work filter = %d, want 2 (work 3)", tm.cursor)
+	}
+
+	tm.filterState.ProjectFilter = []string{"home"}
+	tm.refreshDisplayTasks()
+	if tm.cursor != 1 {
+		t.Errorf("cursor back under the home filter = %d, want 1", tm.cursor)
+	}
+}
+
+func TestTaskManager_FileStatusOverlay(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "one", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "two", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{Name: "old", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	})
+
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}})
+	tm = model.(*TaskManagerModel)
+	if !tm.showFiles || !tm.IsInModalState() {
+		t.Fatal("expected 'i' to open the file status overlay")
+	}
+
+	out := tm.View()
+	for _, want := range []string{
+		data.GetTodoFilePath() + "  (2 tasks)",
+		data.GetDoneFilePath() + "  (1 task)",
+		config.Get().GetProjDir(),
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected overlay to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	model, _ = tm.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if model.(*TaskManagerModel).showFiles {
+		t.Error("expected any key to close the file status overlay")
+	}
+}
+
+func TestTaskManager_ScrollIndicator(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	var tasks []data.Task
+	for i := range 5 {
+		tasks = append(tasks, data.Task{Name: fmt.Sprintf("task %d", i), Tags: make(map[string]string), File: data.GetTodoFilePath()})
+	}
+	tm.WithTasks(tasks)
+
+	for _, tc := range []struct {
+		cursor int
+		want   string
+	}{
+		{0, "0%"},
+		{2, "50%"},
+		{4, "100%"},
+	} {
+		tm.cursor = tc.cursor
+		tm.View()
+		if line := tm.infoBar.renderFiltersLine(); !strings.Contains(line, tc.want) {
+			t.Errorf("cursor %d: indicator line = %q, want it to contain %q", tc.cursor, line, tc.want)
+		}
+	}
+
+	tm.WithTasks(tasks[:1])
+	tm.View()
+	if tm.infoBar.ScrollPercent != -1 {
+		t.Errorf("single task: ScrollPercent = %d, want the indicator hidden", tm.infoBar.ScrollPercent)
+	}
+}
+
+func TestTaskManager_XTogglesDone(t *testing.T) {
+	defer func(orig bool) { config.Get().DisableXToggle = orig }(config.Get().DisableXToggle)
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "task", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	})
+
+	_, cmd := tm.handleNormalMode(runeKey('x'))
+	msg := completeUpdateFrom(t, cmd)
+	if !msg.Task.Done || msg.Task.CompletionDate == "" || msg.Task.File != data.GetTodoFilePath() {
+		t.Errorf("expected x to complete the task in place, got %+v", msg.Task)
+	}
+
+	tm.WithTasks([]data.Task{msg.Task})
+	_, cmd = tm.handleNormalMode(runeKey('x'))
+	if msg, ok := cmd().(TaskUpdateMsg); !ok || msg.Task.Done {
+		t.Errorf("expected x to reopen the done task, got %+v", cmd())
+	}
+
+	config.Get().DisableXToggle = true
+	if _, cmd := tm.handleNormalMode(runeKey('x')); cmd != nil {
+		t.Error("expected x to do nothing with disable_x_toggle set")
+	}
+}
+
+func TestTaskManager_BulkProjectCascadesToSubtasks(t *testing.T) {
+	defer func(orig bool) { config.Get().CascadeProjects = orig }(config.Get().CascadeProjects)
+	newManager := func() *TaskManagerModel {
+		tm := &TaskManagerModel{}
+		tm.Init()
+		tm.WithTasks([]data.Task{
+			{ID: "1", Name: "Plan trip", Tags: map[string]string{"id": "trip"}, File: data.GetTodoFilePath()},
+			{ID: "2", Name: "Book flights", Tags: map[string]string{"parent": "trip"}, File: data.GetTodoFilePath()},
+			{ID: "3", Name: "Unrelated", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+			{ID: "4", Name: "Book hotel", Tags: map[string]string{"parent": "trip"}, File: data.GetTodoFilePath()},
+		})
+		return tm
+	}
+	setWork := func(tm *TaskManagerModel) []data.Task {
+		t.Helper()
+		pressKeys(tm, runeKey('b'))
+		tm.handleBulkSelect(runeKey('p'))
+		_, cmd := tm.handlePickerResult(FuzzyPickerResultMsg{Selected: []string{"work"}})
+		return cmd().(TasksUpdateMsg).Tasks
+	}
+
+	config.Get().CascadeProjects = false
+	if tasks := setWork(newManager()); len(tasks) != 1 || tasks[0].ID != "1" {
+		t.Errorf("without cascade_projects: updated %v, want only the parent", tasks)
+	}
+
+	config.Get().CascadeProjects = true
+	tasks := setWork(newManager())
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		if !slicesEqual(task.Projects, []string{"work"}) {
+			t.Errorf("task %s projects = %v, want [work]", task.ID, task.Projects)
+		}
+	}
+	if !slicesEqual(ids, []string{"1", "2", "4"}) {
+		t.Errorf("with cascade_projects: updated %v, want the parent and both subtasks", ids)
+	}
+}
+
+func TestTaskManager_GotoTaskID(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{ID: "aaaa1111", Name: "first", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "bbbb2222", Name: "second", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "cccc3333", Name: "archived", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()},
+	})
+
+	pressKeys(tm, runeKey(':'))
+	if tm.textInput == nil || tm.inputContext.Mode != ModeGotoID {
+		t.Fatal("expected ':' to prompt for a task ID")
+	}
+	tm.handleTextInputResult(TextInputResultMsg{Value: "bbbb"})
+	if tm.cursor != 1 {
+		t.Errorf("cursor = %d, want 1 (the task with ID prefix bbbb)", tm.cursor)
+	}
+	if tm.inputContext.Mode != ModeNormal {
+		t.Errorf("mode = %v, want normal after jumping", tm.inputContext.Mode)
+	}
+
+	tm.startGotoID()
+	tm.handleTextInputResult(TextInputResultMsg{Value: "cccc"})
+	if tm.cursor != 1 || !strings.Contains(tm.infoBar.Message, "hidden") {
+		t.Errorf("done.txt task: cursor %d message %q, want the cursor kept and a hidden message", tm.cursor, tm.infoBar.Message)
+	}
+
+	tm.startGotoID()
+	tm.handleTextInputResult(TextInputResultMsg{Value: "zzzz"})
+	if !strings.Contains(tm.infoBar.Message, "no task found") {
+		t.Errorf("message = %q, want a not-found message", tm.infoBar.Message)
+	}
+}
+
+func TestTaskManager_StrikeAndHideDone(t *testing.T) {
+	defer func(strike, hide bool) {
+		config.Get().StrikeDone, config.Get().HideDone = strike, hide
+	}(config.Get().StrikeDone, config.Get().HideDone)
+	tasks := []data.Task{
+		{ID: "1", Name: "pending", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+		{ID: "2", Name: "finished", Done: true, CompletionDate: "2024-03-01", Tags: make(map[string]string), File: data.GetTodoFilePath()},
+	}
+
+	config.Get().StrikeDone, config.Get().HideDone = true, false
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks(tasks)
+	if !tm.lineOptions().StrikeDone {
+		t.Error("expected strike_done to strike done names in the todo view")
+	}
+	tm.cursor = 1
+	tm.openTaskEditor()
+	if tm.taskEditor == nil || !tm.taskEditor.StrikeDone {
+		t.Fatal("expected the editor to strike done names like the list")
+	}
+
+	config.Get().StrikeDone, config.Get().HideDone = false, true
+	tm = &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks(tasks)
+	if len(tm.displayTasks) != 1 || tm.displayTasks[0].Name != "pending" {
+		t.Errorf("hide_done: displayed %v, want only the pending task", tm.displayTasks)
+	}
+	tm.fileViewMode = FileViewDoneOnly
+	tm.WithTasks(append(tasks, data.Task{ID: "3", Name: "archived", Done: true, Tags: make(map[string]string), File: data.GetDoneFilePath()}))
+	if len(tm.displayTasks) != 1 || tm.displayTasks[0].Name != "archived" {
+		t.Errorf("hide_done in the done.txt view: displayed %v, want the archived task", tm.displayTasks)
+	}
+}
+
+func newFilteredTaskManager() *TaskManagerModel {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "alpha task", Projects: []string{"work"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+		{Name: "beta task", Projects: []string{"home"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+	})
+	tm.filterState.SearchQuery = "alpha"
+	tm.searchInput.SetValue("alpha")
+	tm.filterState.ProjectFilter = []string{"work"}
+	tm.sortState.Field = SortByPriority
+	tm.groupState.Field = GroupByProject
+	tm.refreshDisplayTasks()
+	return tm
+}
+
+func TestTaskManager_ClearSearchKeepsFilters(t *testing.T) {
+	tm := newFilteredTaskManager()
+
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	tm = model.(*TaskManagerModel)
+
+	if tm.filterState.SearchQuery != "" || tm.searchInput.Value() != "" {
+		t.Errorf("expected search cleared, got %q", tm.filterState.SearchQuery)
+	}
+	if len(tm.filterState.ProjectFilter) != 1 {
+		t.Errorf("expected project filter kept, got %v", tm.filterState.ProjectFilter)
+	}
+	if !tm.sortState.IsActive() || !tm.groupState.IsActive() {
+		t.Error("expected sort and grouping kept")
+	}
+	if tm.infoBar.Message != "Search cleared" {
+		t.Errorf("expected feedback, got %q", tm.infoBar.Message)
+	}
+
+	// Nothing left to clear
+	model, _ = tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	tm = model.(*TaskManagerModel)
+	if tm.infoBar.Message != "No search to clear" {
+		t.Errorf("expected no-op feedback, got %q", tm.infoBar.Message)
+	}
+}
+
+func TestTaskManager_ClearAllFilters(t *testing.T) {
+	tm := newFilteredTaskManager()
+
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'R'}})
+	tm = model.(*TaskManagerModel)
+
+	if !tm.filterState.IsEmpty() || tm.searchInput.Value() != "" {
+		t.Errorf("expected all filters cleared, got %+v", tm.filterState)
+	}
+	if tm.sortState.IsActive() || tm.groupState.IsActive() {
+		t.Error("expected sort and grouping reset")
+	}
+	if len(tm.displayTasks) != 2 {
+		t.Errorf("expected both tasks shown, got %d", len(tm.displayTasks))
+	}
+	if tm.infoBar.Message == "" {
+		t.Error("expected feedback in the info bar")
+	}
+}
+
+func TestTaskManager_AlignColumns(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "short", Projects: []string{"home"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+		{Name: "a much longer task name", Projects: []string{"work"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+	})
+	tm.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	metaColumns := func() (int, int) {
+		view := tm.View()
+		var home, work int
+		for _, line := range strings.Split(view, "\n") {
+			if i := strings.Index(line, "+home"); i >= 0 {
+				home = lipgloss.Width(line[:i])
+			}
+			if i := strings.Index(line, "+work"); i >= 0 {
+				work = lipgloss.Width(line[:i])
+			}
+		}
+		return home, work
+	}
+
+	if home, work := metaColumns(); home == work {
+		t.Fatalf("expected unaligned metadata by default, both at column %d", home)
+	}
+
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'='}})
+	tm = model.(*TaskManagerModel)
+	if home, work := metaColumns(); home != work {
+		t.Errorf("expected metadata at the same column when aligned, got %d and %d", home, work)
+	}
+}
+
+func TestTaskManager_QuitWithModifiedEditorConfirms(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{{ID: "t1", Name: "Pay rent", Tags: map[string]string{}, File: data.GetTodoFilePath()}})
+	press := func(key string) tea.Cmd {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		if key == "ctrl+c" {
+			msg = tea.KeyMsg{Type: tea.KeyCtrlC}
+		}
+		_, cmd := tm.Update(msg)
+		return cmd
+	}
+
+	// Unmodified: quits straight away
+	tm.openTaskEditor()
+	if cmd := press("q"); cmd == nil {
+		t.Fatal("expected a quit request from an unmodified editor")
+	} else if _, ok := cmd().(QuitRequestMsg); !ok {
+		t.Fatalf("expected QuitRequestMsg, got %T", cmd())
+	}
+
+	// Modified: asks first
+	tm.taskEditor = nil
+	tm.inputContext.Reset()
+	tm.openTaskEditor()
+	press("P")
+	if cmd := press("ctrl+c"); cmd != nil {
+		t.Fatalf("expected no quit with unsaved changes, got %T", cmd())
+	}
+	if tm.confirmationModal == nil || !tm.confirmationModal.AllowDiscard {
+		t.Fatal("expected a save/discard/cancel confirmation")
+	}
+
+	// Cancel goes back to the editor with the changes kept
+	tm.Update(tm.confirmationModal.Update(tea.KeyMsg{Type: tea.KeyEscape})())
+	if tm.confirmationModal != nil || tm.taskEditor == nil || tm.inputContext.Mode != ModeTaskEditor {
+		t.Fatalf("expected to be back in the editor, mode %v", tm.inputContext.Mode)
+	}
+	if tm.displayTasks[0].Priority != data.PriorityA {
+		t.Errorf("expected the edit kept after cancelling, got priority %q", tm.displayTasks[0].Priority)
+	}
+
+	// Discard restores the task and quits
+	press("q")
+	_, cmd := tm.Update(tm.confirmationModal.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})())
+	if cmd == nil {
+		t.Fatal("expected a quit request after discarding")
+	}
+	if _, ok := cmd().(QuitRequestMsg); !ok {
+		t.Errorf("expected QuitRequestMsg, got %T", cmd())
+	}
+	if tm.taskEditor != nil || tm.displayTasks[0].Priority != data.PriorityNone {
+		t.Errorf("expected the editor closed and the edit discarded, priority %q", tm.displayTasks[0].Priority)
+	}
+}
+
+func TestTaskManager_CommandPaletteFiltersByProject(t *testing.T) {
+	tm := &TaskManagerModel{}
+	tm.Init()
+	tm.WithTasks([]data.Task{
+		{Name: "alpha task", Projects: []string{"work"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+		{Name: "beta task", Projects: []string{"home"}, Tags: map[string]string{}, File: data.GetTodoFilePath()},
+	})
+
+	model, _ := tm.handleNormalMode(tea.KeyMsg{Type: tea.KeyCtrlP})
+	tm = model.(*TaskManagerModel)
+	if tm.inputContext.Mode != ModeFuzzyPicker || tm.pickerContext != "palette" {
+		t.Fatalf("expected the command palette, got mode %v (%q)", tm.inputContext.Mode, tm.pickerContext)
+	}
+	if !slices.Contains(tm.fuzzyPicker.Items, "Filter by project: work") || !slices.Contains(tm.fuzzyPicker.Items, "Sort by due") {
+		t.Errorf("expected project filters and sorts in the palette, got %v", tm.fuzzyPicker.Items)
+	}
+
+	model, _ = tm.Update(FuzzyPickerResultMsg{Selected: []string{"Filter by project: work"}})
+	tm = model.(*TaskManagerModel)
+
+	if !slicesEqual(tm.filterState.ProjectFilter, []string{"work"}) {
+		t.Errorf("expected project filter [work], got %v", tm.filterState.ProjectFilter)
+	}
+	if len(tm.displayTasks) != 1 || tm.displayTasks[0].Name != "alpha task" {
+		t.Errorf("expected only the work task shown, got %v", taskNames(tm.displayTasks))
+	}
+	if tm.inputContext.Mode != ModeNormal || tm.fuzzyPicker != nil {
+		t.Errorf("expected the palette closed, got mode %v", tm.inputContext.Mode)
+	}
+}
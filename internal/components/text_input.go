@@ -2,11 +2,11 @@ package components
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/data"
 )
 
 var (
@@ -20,6 +20,7 @@ type TextInputModel struct {
 	Input       textinput.Model
 	Prompt      string
 	Validator   func(string) error
+	Normalize   func(string) string // optional, applied to the value on confirm
 	Placeholder string
 	Error       string
 	Width       int
@@ -48,9 +49,12 @@ func NewTextInput(prompt string, placeholder string, validator func(string) erro
 	}
 }
 
-// NewDateInput creates a text input configured for date entry
+// NewDateInput creates a text input configured for date entry.
+// Several common formats are accepted and normalized to yyyy-MM-dd.
 func NewDateInput(prompt string) *TextInputModel {
-	return NewTextInput(prompt, "yyyy-MM-dd", ValidateDateFormat)
+	input := NewTextInput(prompt, "yyyy-MM-dd", ValidateDateFormat)
+	input.Normalize = NormalizeDate
+	return input
 }
 
 // NewSearchInput creates a text input configured for search
@@ -88,9 +92,13 @@ func (m *TextInputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil
 				}
 			}
+			value := m.Input.Value()
+			if m.Normalize != nil {
+				value = m.Normalize(value)
+			}
 			return m, func() tea.Msg {
 				return TextInputResultMsg{
-					Value:     m.Input.Value(),
+					Value:     value,
 					Cancelled: false,
 				}
 			}
@@ -147,14 +155,46 @@ func (m *TextInputModel) Focus() tea.Cmd {
 	return m.Input.Focus()
 }
 
-// ValidateDateFormat validates that the input is in yyyy-MM-dd format
+// ValidateDateFormat validates that the input is a recognizable date
 func ValidateDateFormat(s string) error {
 	if s == "" {
 		return nil // Allow empty
 	}
-	_, err := time.Parse("2006-01-02", s)
+	_, _, err := data.ParseFlexibleDate(s)
 	if err != nil {
 		return fmt.Errorf("invalid date format, use yyyy-MM-dd")
 	}
 	return nil
 }
+
+// ValidateRecurrence validates a rec: value such as 1w or 2m
+func ValidateRecurrence(s string) error {
+	if s == "" {
+		return nil // Allow empty, which clears it
+	}
+	if _, ok := data.ParseRecurrence(s); !ok {
+		return fmt.Errorf("invalid recurrence, use a number and d, w, m or y (e.g. 1w)")
+	}
+	return nil
+}
+
+// ValidateRawLine checks a todo.txt line typed into the task editor: it needs
+// a name, and must parse back to the same task once written out
+func ValidateRawLine(s string) error {
+	t := data.ParseTask(s, "", "")
+	if t.Name == "" {
+		return fmt.Errorf("the task needs a name")
+	}
+	if line := t.String(); data.ParseTask(line, "", "").String() != line {
+		return fmt.Errorf("the line doesn't round-trip, it would be saved as %q", line)
+	}
+	return nil
+}
+
+// NormalizeDate converts a recognizable date to yyyy-MM-dd, leaving other input unchanged
+func NormalizeDate(s string) string {
+	if _, iso, err := data.ParseFlexibleDate(s); err == nil {
+		return iso
+	}
+	return s
+}
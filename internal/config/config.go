@@ -2,8 +2,13 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for wydoCLI.
@@ -13,8 +18,155 @@ type Config struct {
 	TodoFile string `json:"todo_file,omitempty"`
 	DoneFile string `json:"done_file,omitempty"`
 	ProjDir  string `json:"proj_dir,omitempty"`
+
+	// DefaultFileView selects which file(s) the TUI shows on startup: "todo", "all", or "done"
+	DefaultFileView string `json:"default_file_view,omitempty"`
+
+	// PreservePriority keeps a completed task's priority as a pri: tag
+	PreservePriority bool `json:"preserve_priority,omitempty"`
+
+	// InlineCompleted shows done tasks struck-through at the bottom of the "All" file view
+	InlineCompleted bool `json:"inline_completed,omitempty"`
+
+	// FileHeaders puts each file's tasks under a header in the "All" file
+	// view, with any grouping nested inside
+	FileHeaders bool `json:"file_headers,omitempty"`
+
+	// PreserveWhitespace keeps runs of spaces and tabs inside task lines
+	// instead of collapsing them to single spaces when parsing
+	PreserveWhitespace bool `json:"preserve_whitespace,omitempty"`
+
+	// DisableXToggle stops x from toggling done in the TUI (space still
+	// does), freeing the key for another binding
+	DisableXToggle bool `json:"disable_x_toggle,omitempty"`
+
+	// SortDoneByCompletion writes done.txt most recently completed first
+	// (tasks without a completion date last) instead of in task order
+	SortDoneByCompletion bool `json:"sort_done_by_completion,omitempty"`
+
+	// CascadeProjects makes bulk project changes in the TUI reach subtasks
+	// too: tasks whose parent: tag names a changed task's id: tag
+	CascadeProjects bool `json:"cascade_projects,omitempty"`
+
+	// StrikeDone draws completed task names struck-through everywhere in the
+	// TUI, not just in the inline_completed view
+	StrikeDone bool `json:"strike_done,omitempty"`
+
+	// HideDone leaves completed tasks out of the TUI's list in the todo and
+	// all file views (the done.txt view still shows them)
+	HideDone bool `json:"hide_done,omitempty"`
+
+	// ArchiveOnQuit moves completed tasks to done.txt when the TUI quits
+	ArchiveOnQuit bool `json:"archive_on_quit,omitempty"`
+
+	// SplitCompletesOriginal makes the TUI's split action mark the original
+	// task done instead of deleting it, like wydo split --complete
+	SplitCompletesOriginal bool `json:"split_completes_original,omitempty"`
+
+	// DisplayDateFormat is how the TUI shows dates: "iso" (default), "short"
+	// (Jan 02), "long" (Jan 02, 2006), "us" (01/02), "eu" (02/01), or a Go
+	// time layout. Dates are always stored as yyyy-MM-dd.
+	DisplayDateFormat string `json:"display_date_format,omitempty"`
+
+	// ShowCreatedAge shows created dates as a relative age ("12d ago") in the TUI
+	ShowCreatedAge bool `json:"show_created_age,omitempty"`
+
+	// AlignColumns starts the TUI with task metadata lined up in columns
+	AlignColumns bool `json:"align_columns,omitempty"`
+
+	// PurgeDoneFile makes purge also delete every task in done.txt, not just
+	// the completed tasks in todo.txt
+	PurgeDoneFile bool `json:"purge_done_file,omitempty"`
+
+	// RestoreSession saves the TUI's filter/sort/group on quit and restores it on launch
+	RestoreSession bool `json:"restore_session,omitempty"`
+
+	// AddCreatedDate stamps tasks created with add or capture with today's
+	// date when they don't already have a creation date
+	AddCreatedDate bool `json:"add_created_date,omitempty"`
+
+	// WatchFiles reloads the TUI when todo.txt or done.txt change on disk
+	WatchFiles bool `json:"watch_files,omitempty"`
+
+	// LowestPriority is the lowest priority (A-F) that promoting and demoting
+	// step through; demoting it removes the priority
+	LowestPriority string `json:"lowest_priority,omitempty"`
+
+	// Backups is how many copies of todo.txt and done.txt to keep, each
+	// taken before the files are rewritten. 0 keeps none.
+	Backups int `json:"backups,omitempty"`
+
+	// WeekStart is the first day of the week for wydo agenda --week:
+	// "sunday" or "monday" (default)
+	WeekStart string `json:"week_start,omitempty"`
+
+	// PickerWidth and PickerMaxVisible fix the fuzzy picker's box width and
+	// number of visible items. Zero fits them to the terminal.
+	PickerWidth      int `json:"picker_width,omitempty"`
+	PickerMaxVisible int `json:"picker_max_visible,omitempty"`
+
+	// SortTiebreakers orders tasks the active sort considers equal, in turn
+	// (e.g. ["priority", "due", "name"]). Each is applied ascending.
+	SortTiebreakers []string `json:"sort_tiebreakers,omitempty"`
+
+	// GroupSortFallback orders tasks within each group, ascending, when the
+	// TUI groups and sorts by the same field (default ["due", "name"]).
+	// ["none"] keeps the sort as it is.
+	GroupSortFallback []string `json:"group_sort_fallback,omitempty"`
+
+	// AllowedTags turns on strict tags: when set, tasks may only use these
+	// tag keys (plus due, t, pri and rec, which wydo writes itself)
+	AllowedTags []string `json:"allowed_tags,omitempty"`
+
+	// Aliases maps extra CLI command names to a command and, optionally,
+	// leading arguments (e.g. {"addm": "add", "lsa": "list --all"})
+	Aliases map[string]string `json:"aliases,omitempty"`
+
+	// ProjectDefaults sets the context and priority a new task gets when it
+	// names the project and doesn't set its own, e.g.
+	// {"work": {"context": "office", "priority": "B"}}
+	ProjectDefaults map[string]ProjectDefault `json:"project_defaults,omitempty"`
+
+	// Actionable lists the rules a task must all meet to be actionable, for
+	// wydo actionable and the TUI's actionable filter (see ActionableRules)
+	Actionable []string `json:"actionable,omitempty"`
+
+	// OnAdd, OnComplete and OnDelete are commands run (without waiting) after
+	// a task is added, completed or deleted, e.g. "~/bin/sync-task --quiet".
+	// The task's ID and todo.txt line are appended as arguments and also set
+	// as WYDO_TASK_ID and WYDO_TASK_LINE.
+	OnAdd      string `json:"on_add,omitempty"`
+	OnComplete string `json:"on_complete,omitempty"`
+	OnDelete   string `json:"on_delete,omitempty"`
+}
+
+// ProjectDefault is what a new task in a project gets when it leaves it out
+type ProjectDefault struct {
+	Context  string `json:"context,omitempty"`
+	Priority string `json:"priority,omitempty"`
 }
 
+// displayDateLayouts maps the named display_date_format values to layouts
+var displayDateLayouts = map[string]string{
+	"iso":   "2006-01-02",
+	"short": "Jan 02",
+	"long":  "Jan 02, 2006",
+	"us":    "01/02",
+	"eu":    "02/01",
+}
+
+// ActionableRules are the valid actionable values
+var ActionableRules = []string{"pending", "not_future", "not_blocked", "has_context", "has_project", "has_due", "has_priority"}
+
+// defaultActionable is the actionable definition without an actionable config
+var defaultActionable = []string{"pending", "not_future", "not_blocked"}
+
+// SortKeys are the valid sort_tiebreakers values
+var SortKeys = []string{"due", "project", "priority", "context", "name"}
+
+// defaultGroupSortFallback is the group_sort_fallback without a config
+var defaultGroupSortFallback = []string{"due", "name"}
+
 // CLIFlags holds command-line flag values that override other config sources
 type CLIFlags struct {
 	TodoDir string
@@ -54,10 +206,152 @@ func Load() (*Config, error) {
 	// Resolve relative paths
 	cfg.resolvePaths()
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	globalConfig = cfg
 	return cfg, nil
 }
 
+// Validate checks that the resolved configuration is usable: the todo
+// directory must exist or be creatable and be writable, and the task files
+// must be distinct paths that aren't directories.
+func (c *Config) Validate() error {
+	if c.TodoDir == "" {
+		return fmt.Errorf("invalid config: todo_dir is empty")
+	}
+	if err := checkWritableDir(c.TodoDir); err != nil {
+		return fmt.Errorf("invalid config: todo_dir %q: %v", c.TodoDir, err)
+	}
+
+	files := []struct {
+		name string
+		path string
+	}{
+		{"todo_file", c.TodoFile},
+		{"done_file", c.DoneFile},
+	}
+	for _, f := range files {
+		if info, err := os.Stat(f.path); err == nil && info.IsDir() {
+			return fmt.Errorf("invalid config: %s %q is a directory, expected a file", f.name, f.path)
+		}
+	}
+	if filepath.Clean(c.TodoFile) == filepath.Clean(c.DoneFile) {
+		return fmt.Errorf("invalid config: todo_file and done_file both point to %q", c.TodoFile)
+	}
+
+	if info, err := os.Stat(c.ProjDir); err == nil && !info.IsDir() {
+		return fmt.Errorf("invalid config: proj_dir %q is not a directory", c.ProjDir)
+	}
+
+	switch c.DefaultFileView {
+	case "", "todo", "all", "done":
+	default:
+		return fmt.Errorf("invalid config: default_file_view %q must be one of: todo, all, done", c.DefaultFileView)
+	}
+
+	if c.LowestPriority != "" && (len(c.LowestPriority) != 1 || c.LowestPriority < "A" || c.LowestPriority > "F") {
+		return fmt.Errorf("invalid config: lowest_priority %q must be a letter from A to F", c.LowestPriority)
+	}
+
+	switch c.WeekStart {
+	case "", "sunday", "monday":
+	default:
+		return fmt.Errorf("invalid config: week_start %q must be sunday or monday", c.WeekStart)
+	}
+
+	if _, named := displayDateLayouts[c.DisplayDateFormat]; !named && c.DisplayDateFormat != "" {
+		// A layout without any date elements would print itself verbatim
+		ref := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+		if ref.Format(c.DisplayDateFormat) == c.DisplayDateFormat {
+			return fmt.Errorf("invalid config: display_date_format %q must be iso, short, long, us, eu, or a Go time layout", c.DisplayDateFormat)
+		}
+	}
+
+	for project, d := range c.ProjectDefaults {
+		if strings.ContainsAny(d.Context, " \t") {
+			return fmt.Errorf("invalid config: project_defaults %q context %q must be a single word", project, d.Context)
+		}
+		if d.Priority != "" && (len(d.Priority) != 1 || d.Priority < "A" || d.Priority > "F") {
+			return fmt.Errorf("invalid config: project_defaults %q priority %q must be a letter from A to F", project, d.Priority)
+		}
+	}
+	if c.Backups < 0 {
+		return fmt.Errorf("invalid config: backups %d must not be negative", c.Backups)
+	}
+	if c.PickerWidth < 0 {
+		return fmt.Errorf("invalid config: picker_width %d must not be negative", c.PickerWidth)
+	}
+	if c.PickerMaxVisible < 0 {
+		return fmt.Errorf("invalid config: picker_max_visible %d must not be negative", c.PickerMaxVisible)
+	}
+
+	for _, key := range c.SortTiebreakers {
+		if !slices.Contains(SortKeys, key) {
+			return fmt.Errorf("invalid config: sort_tiebreakers %q must be one of: %s", key, strings.Join(SortKeys, ", "))
+		}
+	}
+
+	for _, key := range c.GroupSortFallback {
+		if key != "none" && !slices.Contains(SortKeys, key) {
+			return fmt.Errorf("invalid config: group_sort_fallback %q must be none or one of: %s", key, strings.Join(SortKeys, ", "))
+		}
+	}
+
+	for _, rule := range c.Actionable {
+		if !slices.Contains(ActionableRules, rule) {
+			return fmt.Errorf("invalid config: actionable %q must be one of: %s", rule, strings.Join(ActionableRules, ", "))
+		}
+	}
+
+	// An empty list would reject every tag but the built-in ones
+	if c.AllowedTags != nil && len(c.AllowedTags) == 0 {
+		return fmt.Errorf("invalid config: allowed_tags is empty, remove it to allow every tag")
+	}
+	for _, key := range c.AllowedTags {
+		if !tagKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid config: allowed_tags %q must be letters and digits only", key)
+		}
+	}
+
+	return nil
+}
+
+// tagKeyPattern matches the tag keys todo.txt lines can hold
+var tagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// checkWritableDir verifies dir is a writable directory, or that it can be
+// created because its nearest existing ancestor is a writable directory.
+func checkWritableDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return fmt.Errorf("directory does not exist")
+		}
+		if err := checkWritableDir(parent); err != nil {
+			return fmt.Errorf("cannot be created: %v", err)
+		}
+		return nil
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("not a directory")
+	}
+
+	f, err := os.CreateTemp(dir, ".wydo-write-check-*")
+	if err != nil {
+		return fmt.Errorf("not writable")
+	}
+	name := f.Name()
+	f.Close()
+	os.Remove(name)
+	return nil
+}
+
 // Get returns the loaded config, loading it if necessary
 func Get() *Config {
 	if globalConfig == nil {
@@ -89,6 +383,8 @@ func (c *Config) applyDefaults() {
 	c.TodoFile = "todo.txt"
 	c.DoneFile = "done.txt"
 	c.ProjDir = "todo_projects"
+	c.DefaultFileView = "todo"
+	c.LowestPriority = "F"
 }
 
 func (c *Config) applyEnvVars() {
@@ -139,6 +435,106 @@ func (c *Config) applyConfigFile() error {
 	if fileCfg.ProjDir != "" {
 		c.ProjDir = fileCfg.ProjDir
 	}
+	if fileCfg.DefaultFileView != "" {
+		c.DefaultFileView = fileCfg.DefaultFileView
+	}
+	if fileCfg.PreservePriority {
+		c.PreservePriority = true
+	}
+	if fileCfg.InlineCompleted {
+		c.InlineCompleted = true
+	}
+	if fileCfg.FileHeaders {
+		c.FileHeaders = true
+	}
+	if fileCfg.PreserveWhitespace {
+		c.PreserveWhitespace = true
+	}
+	if fileCfg.DisableXToggle {
+		c.DisableXToggle = true
+	}
+	if fileCfg.SortDoneByCompletion {
+		c.SortDoneByCompletion = true
+	}
+	if fileCfg.CascadeProjects {
+		c.CascadeProjects = true
+	}
+	if fileCfg.StrikeDone {
+		c.StrikeDone = true
+	}
+	if fileCfg.HideDone {
+		c.HideDone = true
+	}
+	if fileCfg.ArchiveOnQuit {
+		c.ArchiveOnQuit = true
+	}
+	if fileCfg.SplitCompletesOriginal {
+		c.SplitCompletesOriginal = true
+	}
+	if fileCfg.DisplayDateFormat != "" {
+		c.DisplayDateFormat = fileCfg.DisplayDateFormat
+	}
+	if fileCfg.ShowCreatedAge {
+		c.ShowCreatedAge = true
+	}
+	if fileCfg.AlignColumns {
+		c.AlignColumns = true
+	}
+	if fileCfg.PurgeDoneFile {
+		c.PurgeDoneFile = true
+	}
+	if fileCfg.RestoreSession {
+		c.RestoreSession = true
+	}
+	if fileCfg.WatchFiles {
+		c.WatchFiles = true
+	}
+	if fileCfg.AddCreatedDate {
+		c.AddCreatedDate = true
+	}
+	if fileCfg.LowestPriority != "" {
+		c.LowestPriority = fileCfg.LowestPriority
+	}
+	if fileCfg.WeekStart != "" {
+		c.WeekStart = fileCfg.WeekStart
+	}
+	if fileCfg.Backups != 0 {
+		c.Backups = fileCfg.Backups
+	}
+	if fileCfg.PickerWidth != 0 {
+		c.PickerWidth = fileCfg.PickerWidth
+	}
+	if fileCfg.PickerMaxVisible != 0 {
+		c.PickerMaxVisible = fileCfg.PickerMaxVisible
+	}
+	if len(fileCfg.SortTiebreakers) > 0 {
+		c.SortTiebreakers = fileCfg.SortTiebreakers
+	}
+	if len(fileCfg.GroupSortFallback) > 0 {
+		c.GroupSortFallback = fileCfg.GroupSortFallback
+	}
+	if fileCfg.AllowedTags != nil {
+		// Kept even when empty, so Validate can reject it
+		c.AllowedTags = fileCfg.AllowedTags
+	}
+	if len(fileCfg.ProjectDefaults) > 0 {
+		c.ProjectDefaults = fileCfg.ProjectDefaults
+	}
+	if len(fileCfg.Aliases) > 0 {
+		c.Aliases = fileCfg.Aliases
+	}
+	if len(fileCfg.Actionable) > 0 {
+		c.Actionable = fileCfg.Actionable
+	}
+	if fileCfg.OnAdd != "" {
+		c.OnAdd = fileCfg.OnAdd
+	}
+	if fileCfg.OnComplete != "" {
+		c.OnComplete = fileCfg.OnComplete
+	}
+	if fileCfg.OnDelete != "" {
+		c.OnDelete = fileCfg.OnDelete
+	}
 
 	return nil
 }
@@ -189,6 +585,20 @@ func getConfigPath() string {
 	return ""
 }
 
+// GetSessionPath returns the path of the TUI session state file:
+// $XDG_STATE_HOME/wydo/session.json, falling back to ~/.local/state/wydo/session.json
+func GetSessionPath() string {
+	if xdgState := os.Getenv("XDG_STATE_HOME"); xdgState != "" {
+		return filepath.Join(xdgState, "wydo", "session.json")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".local", "state", "wydo", "session.json")
+}
+
 // expandPath expands ~ to the home directory
 func expandPath(path string) string {
 	if len(path) == 0 {
@@ -225,3 +635,197 @@ func (c *Config) GetDoneFile() string {
 func (c *Config) GetProjDir() string {
 	return c.ProjDir
 }
+
+// GetBackups returns how many backups of todo.txt and done.txt to keep
+func (c *Config) GetBackups() int {
+	return c.Backups
+}
+
+// GetBackupDir returns the directory backups are kept in, .backups in
+// the todo directory
+func (c *Config) GetBackupDir() string {
+	return filepath.Join(c.TodoDir, ".backups")
+}
+
+// GetDefaultFileView returns the file view the TUI starts in ("todo", "all", or "done")
+func (c *Config) GetDefaultFileView() string {
+	return c.DefaultFileView
+}
+
+// GetPreservePriority reports whether completing a task keeps its priority as a pri: tag
+func (c *Config) GetPreservePriority() bool {
+	return c.PreservePriority
+}
+
+// GetInlineCompleted reports whether done tasks are shown inline and struck-through
+func (c *Config) GetInlineCompleted() bool {
+	return c.InlineCompleted
+}
+
+// GetFileHeaders reports whether the "All" file view shows a header per file
+func (c *Config) GetFileHeaders() bool {
+	return c.FileHeaders
+}
+
+// GetPreserveWhitespace reports whether task parsing keeps internal whitespace
+func (c *Config) GetPreserveWhitespace() bool {
+	return c.PreserveWhitespace
+}
+
+// GetDisableXToggle reports whether x is kept from toggling done in the TUI
+func (c *Config) GetDisableXToggle() bool {
+	return c.DisableXToggle
+}
+
+// GetSortDoneByCompletion reports whether done.txt is written newest completion first
+func (c *Config) GetSortDoneByCompletion() bool {
+	return c.SortDoneByCompletion
+}
+
+// GetCascadeProjects reports whether bulk project changes reach subtasks
+func (c *Config) GetCascadeProjects() bool {
+	return c.CascadeProjects
+}
+
+// GetStrikeDone reports whether done task names are always struck-through
+func (c *Config) GetStrikeDone() bool {
+	return c.StrikeDone
+}
+
+// GetHideDone reports whether the TUI hides done tasks outside the done.txt view
+func (c *Config) GetHideDone() bool {
+	return c.HideDone
+}
+
+// GetArchiveOnQuit reports whether the TUI archives completed tasks on quit
+func (c *Config) GetArchiveOnQuit() bool {
+	return c.ArchiveOnQuit
+}
+
+// GetSplitCompletesOriginal reports whether splitting a task in the TUI
+// completes the original rather than deleting it
+func (c *Config) GetSplitCompletesOriginal() bool {
+	return c.SplitCompletesOriginal
+}
+
+// GetRestoreSession reports whether the TUI persists its filter/sort/group between runs
+func (c *Config) GetRestoreSession() bool {
+	return c.RestoreSession
+}
+
+// GetDisplayDateLayout returns the Go time layout for showing dates
+func (c *Config) GetDisplayDateLayout() string {
+	if c.DisplayDateFormat == "" {
+		return displayDateLayouts["iso"]
+	}
+	if layout, ok := displayDateLayouts[c.DisplayDateFormat]; ok {
+		return layout
+	}
+	return c.DisplayDateFormat
+}
+
+// GetShowCreatedAge reports whether the TUI shows created dates as relative ages
+func (c *Config) GetShowCreatedAge() bool {
+	return c.ShowCreatedAge
+}
+
+// GetAlignColumns reports whether the TUI starts with aligned task columns
+func (c *Config) GetAlignColumns() bool {
+	return c.AlignColumns
+}
+
+// GetPurgeDoneFile reports whether purging also clears done.txt
+func (c *Config) GetPurgeDoneFile() bool {
+	return c.PurgeDoneFile
+}
+
+// GetAddCreatedDate reports whether new tasks are stamped with a creation date
+func (c *Config) GetAddCreatedDate() bool {
+	return c.AddCreatedDate
+}
+
+// GetWatchFiles reports whether the TUI reloads when the task files change on disk
+func (c *Config) GetWatchFiles() bool {
+	return c.WatchFiles
+}
+
+// GetWeekStart returns the first day of the week (Monday unless week_start
+// is "sunday")
+func (c *Config) GetWeekStart() time.Weekday {
+	if c.WeekStart == "sunday" {
+		return time.Sunday
+	}
+	return time.Monday
+}
+
+// GetPickerWidth returns the configured picker width (0 to fit the terminal)
+func (c *Config) GetPickerWidth() int {
+	return c.PickerWidth
+}
+
+// GetPickerMaxVisible returns the configured number of visible picker items
+// (0 to fit the terminal)
+func (c *Config) GetPickerMaxVisible() int {
+	return c.PickerMaxVisible
+}
+
+// GetAllowedTags returns the tag keys strict mode allows (nil when off)
+func (c *Config) GetAllowedTags() []string {
+	return c.AllowedTags
+}
+
+// GetProjectDefaults returns the configured defaults for new tasks by project
+func (c *Config) GetProjectDefaults() map[string]ProjectDefault {
+	return c.ProjectDefaults
+}
+
+// GetAliases returns the configured CLI command aliases
+func (c *Config) GetAliases() map[string]string {
+	return c.Aliases
+}
+
+// GetActionable returns the rules a task must meet to be actionable,
+// defaulting to pending, not_future and not_blocked
+func (c *Config) GetActionable() []string {
+	if len(c.Actionable) == 0 {
+		return defaultActionable
+	}
+	return c.Actionable
+}
+
+// GetOnAdd returns the command run after a task is added ("" for none)
+func (c *Config) GetOnAdd() string {
+	return c.OnAdd
+}
+
+// GetOnComplete returns the command run after a task is completed ("" for none)
+func (c *Config) GetOnComplete() string {
+	return c.OnComplete
+}
+
+// GetOnDelete returns the command run after a task is deleted ("" for none)
+func (c *Config) GetOnDelete() string {
+	return c.OnDelete
+}
+
+// GetSortTiebreakers returns the sort keys used to order otherwise-equal tasks
+func (c *Config) GetSortTiebreakers() []string {
+	return c.SortTiebreakers
+}
+
+// GetGroupSortFallback returns the sort keys used within groups when the
+// group and sort fields are the same
+func (c *Config) GetGroupSortFallback() []string {
+	if len(c.GroupSortFallback) == 0 {
+		return defaultGroupSortFallback
+	}
+	return c.GroupSortFallback
+}
+
+// GetLowestPriority returns the lowest priority letter promote/demote use
+func (c *Config) GetLowestPriority() string {
+	if c.LowestPriority == "" {
+		return "F"
+	}
+	return c.LowestPriority
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -207,3 +208,207 @@ func TestConfig_Getters(t *testing.T) {
 		t.Errorf("GetProjDir() = %q, want %q", cfg.GetProjDir(), filepath.Join(tmpDir, "todo_projects"))
 	}
 }
+
+func TestLoad_DefaultFileView(t *testing.T) {
+	Reset()
+
+	os.Unsetenv("TODO_DIR")
+	tmpDir := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "no-config"))
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+	SetCLIFlags(CLIFlags{TodoDir: tmpDir})
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GetDefaultFileView() != "todo" {
+		t.Errorf("GetDefaultFileView() = %q, want %q", cfg.GetDefaultFileView(), "todo")
+	}
+
+	// Config file overrides the default
+	Reset()
+	configDir := filepath.Join(tmpDir, ".config", "wydo")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	configContent := `{"default_file_view": "all"}`
+	if err := os.WriteFile(filepath.Join(configDir, "config.json"), []byte(configContent), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+	SetCLIFlags(CLIFlags{TodoDir: tmpDir})
+
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.GetDefaultFileView() != "all" {
+		t.Errorf("GetDefaultFileView() = %q, want %q", cfg.GetDefaultFileView(), "all")
+	}
+}
+
+func TestValidate_ValidConfig(t *testing.T) {
+	Reset()
+
+	tmpDir := t.TempDir()
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "no-config"))
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	// A todo dir that doesn't exist yet but can be created is fine
+	SetCLIFlags(CLIFlags{TodoDir: filepath.Join(tmpDir, "new", "todos")})
+
+	if _, err := Load(); err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	notADir := filepath.Join(tmpDir, "file.txt")
+	if err := os.WriteFile(notADir, []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{
+			name:    "todo dir is a file",
+			cfg:     Config{TodoDir: notADir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p"},
+			wantErr: "not a directory",
+		},
+		{
+			name:    "todo dir beneath a file cannot be created",
+			cfg:     Config{TodoDir: filepath.Join(notADir, "todos"), TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p"},
+			wantErr: "not a directory",
+		},
+		{
+			name:    "todo file is a directory",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: tmpDir, DoneFile: "done.txt", ProjDir: "p"},
+			wantErr: "todo_file",
+		},
+		{
+			name:    "todo and done file are the same",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "todo.txt", ProjDir: "p"},
+			wantErr: "both point to",
+		},
+		{
+			name:    "unknown default file view",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", DefaultFileView: "everything"},
+			wantErr: "default_file_view",
+		},
+		{
+			name:    "unknown sort tiebreaker",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", SortTiebreakers: []string{"due", "size"}},
+			wantErr: "sort_tiebreakers",
+		},
+		{
+			name:    "unknown group sort fallback",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", GroupSortFallback: []string{"size"}},
+			wantErr: "group_sort_fallback",
+		},
+		{
+			name:    "unknown actionable rule",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", Actionable: []string{"pending", "urgent"}},
+			wantErr: "actionable",
+		},
+		{
+			name:    "empty allowed tags",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", AllowedTags: []string{}},
+			wantErr: "allowed_tags is empty",
+		},
+		{
+			name:    "invalid allowed tag key",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", AllowedTags: []string{"area", ""}},
+			wantErr: "allowed_tags",
+		},
+		{
+			name:    "lowest priority out of range",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", LowestPriority: "G"},
+			wantErr: "lowest_priority",
+		},
+		{
+			name:    "unknown week start",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", WeekStart: "friday"},
+			wantErr: "week_start",
+		},
+		{
+			name:    "project default priority out of range",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", ProjectDefaults: map[string]ProjectDefault{"work": {Priority: "Z"}}},
+			wantErr: "project_defaults",
+		},
+		{
+			name:    "negative backups",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", Backups: -1},
+			wantErr: "backups",
+		},
+		{
+			name:    "negative picker width",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", PickerWidth: -1},
+			wantErr: "picker_width",
+		},
+		{
+			name:    "display date format without date elements",
+			cfg:     Config{TodoDir: tmpDir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p", DisplayDateFormat: "fancy"},
+			wantErr: "display_date_format",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := tc.cfg
+			cfg.resolvePaths()
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatalf("Validate() = nil, want error containing %q", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("Validate() = %q, want error containing %q", err.Error(), tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_UnwritableTodoDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	readOnly := filepath.Join(t.TempDir(), "readonly")
+	if err := os.Mkdir(readOnly, 0555); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	tests := map[string]string{
+		readOnly:                         "not writable",
+		filepath.Join(readOnly, "todos"): "cannot be created: not writable",
+	}
+	for dir, wantErr := range tests {
+		cfg := Config{TodoDir: dir, TodoFile: "todo.txt", DoneFile: "done.txt", ProjDir: "p"}
+		cfg.resolvePaths()
+		err := cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("Validate() with todo_dir %s = %v, want error containing %q", dir, err, wantErr)
+		}
+	}
+}
+
+func TestGetSessionPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Setenv("XDG_STATE_HOME", tmpDir)
+	defer os.Unsetenv("XDG_STATE_HOME")
+
+	expected := filepath.Join(tmpDir, "wydo", "session.json")
+	if got := GetSessionPath(); got != expected {
+		t.Errorf("GetSessionPath() = %q, want %q", got, expected)
+	}
+
+	os.Unsetenv("XDG_STATE_HOME")
+	home, _ := os.UserHomeDir()
+	expected = filepath.Join(home, ".local", "state", "wydo", "session.json")
+	if got := GetSessionPath(); got != expected {
+		t.Errorf("GetSessionPath() = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,124 @@
+package data
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/logs"
+)
+
+// backupNameFormat names each backup's directory. It sorts by time and is
+// precise enough that writes in quick succession don't collide.
+const backupNameFormat = "20060102-150405.000000000"
+
+// Backup is one saved copy of todo.txt and done.txt
+type Backup struct {
+	Dir  string
+	Time time.Time
+}
+
+// backupFiles copies todo.txt and done.txt into a new backup before they're
+// rewritten, keeping the configured number of backups. It does nothing when
+// backups are off. Callers must hold mu.
+func backupFiles() {
+	cfg := config.Get()
+	keep := cfg.GetBackups()
+	if keep <= 0 {
+		return
+	}
+
+	dir := filepath.Join(cfg.GetBackupDir(), time.Now().Format(backupNameFormat))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		logs.Logger.Printf("Error creating backup %s: %v", dir, err)
+		return
+	}
+	for _, path := range []string{getTodoFilePath(), getDoneFilePath()} {
+		if err := copyFile(path, filepath.Join(dir, filepath.Base(path))); err != nil && !os.IsNotExist(err) {
+			logs.Logger.Printf("Error backing up %s: %v", path, err)
+		}
+	}
+
+	backups, err := listBackups()
+	if err != nil {
+		logs.Logger.Printf("Error listing backups: %v", err)
+		return
+	}
+	for _, b := range backups[min(keep, len(backups)):] {
+		if err := os.RemoveAll(b.Dir); err != nil {
+			logs.Logger.Printf("Error removing old backup %s: %v", b.Dir, err)
+		}
+	}
+}
+
+// ListBackups returns the saved backups, most recent first
+func ListBackups() ([]Backup, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return listBackups()
+}
+
+func listBackups() ([]Backup, error) {
+	entries, err := os.ReadDir(config.Get().GetBackupDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		t, err := time.ParseInLocation(backupNameFormat, e.Name(), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, Backup{Dir: filepath.Join(config.Get().GetBackupDir(), e.Name()), Time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Time.After(backups[j].Time)
+	})
+	return backups, nil
+}
+
+// RestoreBackup copies a backup's todo.txt and done.txt over the current
+// files. A file missing from the backup (it didn't exist yet) is emptied.
+// The current files are backed up first, so a restore can be undone.
+func RestoreBackup(b Backup) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Read the backup before taking a new one, which may prune it
+	paths := []string{getTodoFilePath(), getDoneFilePath()}
+	contents := make([][]byte, len(paths))
+	for i, path := range paths {
+		content, err := os.ReadFile(filepath.Join(b.Dir, filepath.Base(path)))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("reading backup of %s: %v", path, err)
+		}
+		contents[i] = content
+	}
+
+	backupFiles()
+	for i, path := range paths {
+		if err := os.WriteFile(path, contents[i], 0644); err != nil {
+			return fmt.Errorf("%w %s from the backup: %v", ErrWriteFailed, path, err)
+		}
+	}
+	return nil
+}
+
+// copyFile replaces dst with the contents of src
+func copyFile(src, dst string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, content, 0644)
+}
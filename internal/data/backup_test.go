@@ -0,0 +1,100 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+)
+
+func TestWriteData_RotatesBackups(t *testing.T) {
+	dir := t.TempDir()
+	config.Reset()
+	t.Cleanup(config.Reset)
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	config.Get().Backups = 2
+
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		task := ParseTask(name, name, GetTodoFilePath())
+		if err := WriteData([]Task{task}); err != nil {
+			t.Fatalf("WriteData: %v", err)
+		}
+	}
+
+	backups, err := ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("kept %d backups, want 2", len(backups))
+	}
+	// Each backup is taken before a write, so the latest holds the third
+	for i, want := range []string{"third\n", "second\n"} {
+		got, err := os.ReadFile(filepath.Join(backups[i].Dir, "todo.txt"))
+		if err != nil {
+			t.Fatalf("reading backup %d: %v", i+1, err)
+		}
+		if string(got) != want {
+			t.Errorf("backup %d todo.txt = %q, want %q", i+1, got, want)
+		}
+	}
+
+	if err := RestoreBackup(backups[1]); err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if got, _ := os.ReadFile(GetTodoFilePath()); string(got) != "second\n" {
+		t.Errorf("restored todo.txt = %q, want %q", got, "second\n")
+	}
+
+	// The files the restore replaced were backed up first
+	backups, _ = ListBackups()
+	if got, _ := os.ReadFile(filepath.Join(backups[0].Dir, "todo.txt")); string(got) != "fourth\n" {
+		t.Errorf("latest backup todo.txt = %q, want the pre-restore %q", got, "fourth\n")
+	}
+}
+
+func TestWriteNormalized_BacksUp(t *testing.T) {
+	dir := t.TempDir()
+	config.Reset()
+	t.Cleanup(config.Reset)
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	config.Get().Backups = 1
+	if err := os.WriteFile(GetTodoFilePath(), []byte("(a) messy  line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteNormalized(GetTodoFilePath(), []NormalizedLine{{Normalized: "(A) messy line"}}); err != nil {
+		t.Fatalf("WriteNormalized: %v", err)
+	}
+	backups, err := ListBackups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("ListBackups = %v, %v, want one backup", backups, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(backups[0].Dir, "todo.txt")); string(got) != "(a) messy  line\n" {
+		t.Errorf("backup todo.txt = %q, want the file before normalizing", got)
+	}
+}
+
+func TestWriteData_NoBackupsByDefault(t *testing.T) {
+	dir := t.TempDir()
+	config.Reset()
+	t.Cleanup(config.Reset)
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if err := WriteData([]Task{ParseTask("one", "1", GetTodoFilePath())}); err != nil {
+		t.Fatalf("WriteData: %v", err)
+	}
+	if _, err := os.Stat(config.Get().GetBackupDir()); !os.IsNotExist(err) {
+		t.Errorf("expected no backup dir without backups configured, got %v", err)
+	}
+}
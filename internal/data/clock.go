@@ -0,0 +1,12 @@
+package data
+
+import "time"
+
+// Now returns the current time. Everything that stamps or compares dates
+// calls it instead of time.Now so tests can pin the clock.
+var Now = time.Now
+
+// Today returns the current date as yyyy-MM-dd
+func Today() string {
+	return Now().Format(DateFormat)
+}
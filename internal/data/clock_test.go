@@ -0,0 +1,15 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToday_UsesClock(t *testing.T) {
+	defer func(orig func() time.Time) { Now = orig }(Now)
+	Now = func() time.Time { return time.Date(2024, 2, 29, 23, 59, 0, 0, time.UTC) }
+
+	if got := Today(); got != "2024-02-29" {
+		t.Errorf("Today() = %q, want %q", got, "2024-02-29")
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 
@@ -33,6 +35,8 @@ func getProjDir() string {
 	return config.Get().GetProjDir()
 }
 
+// HashTaskLine derives a task ID. Callers hash "lineNum:filePath" rather
+// than the task text, so identical lines get distinct IDs.
 func HashTaskLine(line string) string {
 	h := sha1.New()
 	h.Write([]byte(line))
@@ -74,12 +78,8 @@ func LoadData(allowMismatch bool) ([]Task, map[string]Project, error) {
 
 	// Projects
 	projectMap = make(map[string]Project)
-	err = scanProjectFiles(projectMap)
-	if err != nil {
-		// Don't fail if project dir doesn't exist
-		if !os.IsNotExist(err) {
-			return nil, nil, err
-		}
+	if err := scanProjectFiles(projectMap); err != nil {
+		return nil, nil, err
 	}
 
 	// Tasks
@@ -108,10 +108,45 @@ func LoadData(allowMismatch bool) ([]Task, map[string]Project, error) {
 		doneTasks = []Task{}
 	}
 
+	reconcileDoneState(todoTasks, doneTasks)
+
 	allTasks := append(todoTasks, doneTasks...)
 	return allTasks, projectMap, nil
 }
 
+// reconcileDoneState makes the Done flag agree with the file a task was
+// loaded from. Everything in done.txt is done, even without a leading "x".
+// Completed tasks in todo.txt are valid (they wait there until archived) but
+// are logged as a warning (see StrayDoneWarning).
+func reconcileDoneState(todoTasks, doneTasks []Task) {
+	for i := range doneTasks {
+		if !doneTasks[i].Done {
+			logs.Logger.Printf("Warning: task in done.txt is not marked done, treating as done: %s\n", doneTasks[i].String())
+			doneTasks[i].Done = true
+		}
+	}
+
+	if warning := StrayDoneWarning(todoTasks); warning != "" {
+		logs.Logger.Printf("Warning: %s\n", warning)
+	}
+}
+
+// StrayDoneWarning describes the completed tasks in todo.txt, which are easy
+// to mistake for pending ones, or returns "" if there are none
+func StrayDoneWarning(tasks []Task) string {
+	todoFilePath := getTodoFilePath()
+	stray := 0
+	for _, t := range tasks {
+		if t.Done && t.File == todoFilePath {
+			stray++
+		}
+	}
+	if stray == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d completed task(s) in todo.txt, archive to move them to done.txt", stray)
+}
+
 func WriteData(tasks []Task) error {
 	todoFilePath := getTodoFilePath()
 	doneFilePath := getDoneFilePath()
@@ -122,13 +157,14 @@ func WriteData(tasks []Task) error {
 
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(todoFilePath), 0755); err != nil {
-		return fmt.Errorf("Error creating directory: %v", err)
+		return fmt.Errorf("%w: creating directory: %v", ErrWriteFailed, err)
 	}
+	backupFiles()
 
 	// Write todo tasks
 	todoFile, err := os.Create(todoFilePath)
 	if err != nil {
-		return fmt.Errorf("Error writing %s: %v", todoFilePath, err)
+		return fmt.Errorf("%w %s: %v", ErrWriteFailed, todoFilePath, err)
 	}
 	defer todoFile.Close()
 	for _, task := range tasks {
@@ -137,30 +173,48 @@ func WriteData(tasks []Task) error {
 		}
 		_, err := fmt.Fprintln(todoFile, task.String())
 		if err != nil {
-			return fmt.Errorf("Error writing to %s: %v", todoFilePath, err)
+			return fmt.Errorf("%w to %s: %v", ErrWriteFailed, todoFilePath, err)
 		}
 	}
 
 	// Write done tasks
 	doneFile, err := os.Create(doneFilePath)
 	if err != nil {
-		return fmt.Errorf("Error writing %s: %v", doneFilePath, err)
+		return fmt.Errorf("%w %s: %v", ErrWriteFailed, doneFilePath, err)
 	}
 	defer doneFile.Close()
+	var doneTasks []Task
 	for _, task := range tasks {
-		if task.File != doneFilePath {
-			continue
+		if task.File == doneFilePath {
+			doneTasks = append(doneTasks, task)
 		}
+	}
+	if config.Get().GetSortDoneByCompletion() {
+		sortByCompletionDesc(doneTasks)
+	}
+	for _, task := range doneTasks {
 		task.Done = true
 		_, err := fmt.Fprintln(doneFile, task.String())
 		if err != nil {
-			return fmt.Errorf("Error writing to %s: %v", doneFilePath, err)
+			return fmt.Errorf("%w to %s: %v", ErrWriteFailed, doneFilePath, err)
 		}
 	}
 
 	return nil
 }
 
+// sortByCompletionDesc orders tasks most recently completed first, keeping
+// the current order for equal dates and putting undated tasks last
+func sortByCompletionDesc(tasks []Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i].CompletionDate, tasks[j].CompletionDate
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		return a > b
+	})
+}
+
 func PrintTasks(tasks []Task) {
 	fmt.Println("---------------")
 	fmt.Printf("Tasks: %d\n", len(tasks))
@@ -175,8 +229,8 @@ func PrintProjects(tasks []Task) {
 	fmt.Println("---------------")
 	fmt.Printf("Projects: %d\n", len(projectMap))
 	fmt.Println("---------------")
-	for name, project := range projectMap {
-		fmt.Printf("\nProject: %s\n", name)
+	for _, project := range SortProjects(projectMap) {
+		fmt.Printf("\nProject: %s\n", project.Name)
 		if project.NotePath != nil {
 			fmt.Printf("NotePath: %s\n", *project.NotePath)
 		} else {
@@ -204,9 +258,15 @@ func TaskCount(tasks []Task, project string) (int, int) {
 }
 
 func ArchiveDone(tasks []Task) error {
+	return ArchiveDoneInProject(tasks, "")
+}
+
+// ArchiveDoneInProject is ArchiveDone limited to tasks in project. An empty
+// project matches every task.
+func ArchiveDoneInProject(tasks []Task, project string) error {
 	doneFilePath := getDoneFilePath()
 	for i := range tasks {
-		if tasks[i].Done {
+		if tasks[i].Done && inProject(tasks[i], project) {
 			tasks[i].File = doneFilePath
 		}
 	}
@@ -214,8 +274,46 @@ func ArchiveDone(tasks []Task) error {
 	return err
 }
 
+// PurgeDone returns the tasks left after deleting completed tasks from
+// todo.txt, and every task in done.txt when includeDoneFile is set
+func PurgeDone(tasks []Task, includeDoneFile bool) []Task {
+	return PurgeDoneInProject(tasks, "", includeDoneFile)
+}
+
+// PurgeDoneInProject is PurgeDone limited to tasks in project. An empty
+// project matches every task.
+func PurgeDoneInProject(tasks []Task, project string, includeDoneFile bool) []Task {
+	doneFilePath := getDoneFilePath()
+	var kept []Task
+	for _, t := range tasks {
+		if !inProject(t, project) {
+			kept = append(kept, t)
+			continue
+		}
+		if t.File == doneFilePath {
+			if !includeDoneFile {
+				kept = append(kept, t)
+			}
+			continue
+		}
+		if !t.Done {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+func inProject(t Task, project string) bool {
+	return project == "" || t.HasProject(project)
+}
+
+// scanProjectFiles adds a project with a note for each file under the
+// projects directory. A missing directory just means there are no notes.
 func scanProjectFiles(projectMap map[string]Project) error {
 	projDir := getProjDir()
+	if _, err := os.Stat(projDir); os.IsNotExist(err) {
+		return nil
+	}
 	return filepath.Walk(projDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -231,14 +329,17 @@ func scanProjectFiles(projectMap map[string]Project) error {
 		if relErr != nil {
 			return relErr
 		}
+		description := readNoteDescription(path)
 		if _, exists := projectMap[name]; !exists {
 			projectMap[name] = Project{
-				Name:     name,
-				NotePath: &relPath,
+				Name:        name,
+				NotePath:    &relPath,
+				Description: description,
 			}
 		} else {
 			proj := projectMap[name]
 			proj.NotePath = &relPath
+			proj.Description = description
 			projectMap[name] = proj
 		}
 		return nil
@@ -287,6 +388,60 @@ func loadTaskFile(filePath string, allowMismatch bool, projects map[string]Proje
 	return taskList, nil
 }
 
+// LoadDonePage reads up to limit tasks from done.txt, skipping the first
+// offset tasks, without loading the rest of the file. Tasks get the same IDs
+// as a full load and are always marked done.
+func LoadDonePage(offset, limit int) ([]Task, error) {
+	tasks, err := loadTaskFilePage(getDoneFilePath(), offset, limit)
+	if os.IsNotExist(err) {
+		return []Task{}, nil
+	}
+	for i := range tasks {
+		tasks[i].Done = true
+	}
+	return tasks, err
+}
+
+// loadTaskFilePage streams a task file and parses only the tasks in the
+// window [offset, offset+limit). Blank lines are skipped and not counted,
+// matching loadTaskFile.
+func loadTaskFilePage(filePath string, offset, limit int) ([]Task, error) {
+	if offset < 0 || limit < 0 {
+		return nil, fmt.Errorf("invalid page: offset %d, limit %d", offset, limit)
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	taskList := []Task{}
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	index := 0
+	for len(taskList) < limit && scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if index >= offset {
+			hashId := HashTaskLine(fmt.Sprintf("%d:%s", lineNum, filePath))
+			taskList = append(taskList, ParseTask(line, hashId, filePath))
+		}
+		index++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return taskList, nil
+}
+
 // DeleteTask removes a task by ID from the task slice and returns the updated slice.
 func DeleteTask(tasks []Task, id string) []Task {
 	for i, t := range tasks {
@@ -297,6 +452,26 @@ func DeleteTask(tasks []Task, id string) []Task {
 	return tasks
 }
 
+// FindByPartialID returns the task whose ID is partialID or, for prefixes
+// of at least 4 characters, starts with it. More than one match is an error.
+func FindByPartialID(tasks []Task, partialID string) (*Task, error) {
+	var matches []Task
+	for _, t := range tasks {
+		if t.ID == partialID || (len(partialID) >= 4 && strings.HasPrefix(t.ID, partialID)) {
+			matches = append(matches, t)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w with ID: %s", ErrTaskNotFound, partialID)
+	}
+	if len(matches) > 1 {
+		return nil, fmt.Errorf("%w '%s', please be more specific", ErrAmbiguousID, partialID)
+	}
+
+	return &matches[0], nil
+}
+
 // AppendTask appends a single task line to the todo.txt file efficiently.
 // It parses the line, assigns an ID, and returns the created Task.
 func AppendTask(rawLine string) (*Task, error) {
@@ -312,10 +487,12 @@ func AppendTask(rawLine string) (*Task, error) {
 
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(todoFilePath), 0755); err != nil {
-		return nil, fmt.Errorf("error creating directory: %v", err)
+		return nil, fmt.Errorf("%w: creating directory: %v", ErrWriteFailed, err)
 	}
 
-	// Count existing lines to generate a unique ID
+	// Count existing lines, blank ones included: IDs come from the file line
+	// number, as in loadTaskFile, so the new task gets the ID a reload gives
+	// it rather than one an existing task already has
 	lineCount := 0
 	file, err := os.Open(todoFilePath)
 	if err != nil && !os.IsNotExist(err) {
@@ -324,9 +501,7 @@ func AppendTask(rawLine string) (*Task, error) {
 	if file != nil {
 		scanner := bufio.NewScanner(file)
 		for scanner.Scan() {
-			if strings.TrimSpace(scanner.Text()) != "" {
-				lineCount++
-			}
+			lineCount++
 		}
 		file.Close()
 	}
@@ -338,18 +513,123 @@ func AppendTask(rawLine string) (*Task, error) {
 	// Append to file
 	f, err := os.OpenFile(todoFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
-		return nil, fmt.Errorf("error opening %s for append: %v", todoFilePath, err)
+		return nil, fmt.Errorf("%w %s: %v", ErrWriteFailed, todoFilePath, err)
 	}
 	defer f.Close()
 
 	_, err = fmt.Fprintln(f, task.String())
 	if err != nil {
-		return nil, fmt.Errorf("error writing to %s: %v", todoFilePath, err)
+		return nil, fmt.Errorf("%w to %s: %v", ErrWriteFailed, todoFilePath, err)
+	}
+
+	return &task, nil
+}
+
+// InsertTask inserts a single task line into todo.txt before the task at
+// index (0 is the top), shifting the tasks after it down. Blank lines are
+// kept and don't count towards index; an index past the last task appends.
+func InsertTask(rawLine string, index int) (*Task, error) {
+	todoFilePath := getTodoFilePath()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	rawLine = strings.TrimSpace(rawLine)
+	if rawLine == "" {
+		return nil, fmt.Errorf("empty task line")
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("invalid index %d", index)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(todoFilePath), 0755); err != nil {
+		return nil, fmt.Errorf("%w: creating directory: %v", ErrWriteFailed, err)
+	}
+
+	content, err := os.ReadFile(todoFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading %s: %v", todoFilePath, err)
+	}
+	var lines []string
+	if len(content) > 0 {
+		lines = strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	}
+
+	// Find the file line the index-th task is on
+	pos := len(lines)
+	seen := 0
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if seen == index {
+			pos = i
+			break
+		}
+		seen++
+	}
+
+	// IDs come from the file line number, as in loadTaskFile
+	hashId := HashTaskLine(fmt.Sprintf("%d:%s", pos+1, todoFilePath))
+	task := ParseTask(rawLine, hashId, todoFilePath)
+
+	backupFiles()
+	lines = slices.Insert(lines, pos, task.String())
+	if err := os.WriteFile(todoFilePath, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("%w to %s: %v", ErrWriteFailed, todoFilePath, err)
 	}
 
 	return &task, nil
 }
 
+// CaptureLine appends rawLine to todo.txt exactly as given, without parsing
+// or normalizing it, for an inbox that gets triaged later
+func CaptureLine(rawLine string) error {
+	todoFilePath := getTodoFilePath()
+
+	rawLine = strings.TrimSpace(rawLine)
+	if rawLine == "" {
+		return fmt.Errorf("empty task line")
+	}
+	if strings.ContainsAny(rawLine, "\r\n") {
+		return fmt.Errorf("task line must be a single line")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(todoFilePath), 0755); err != nil {
+		return fmt.Errorf("%w: creating directory: %v", ErrWriteFailed, err)
+	}
+	f, err := os.OpenFile(todoFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("%w %s: %v", ErrWriteFailed, todoFilePath, err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, rawLine); err != nil {
+		return fmt.Errorf("%w to %s: %v", ErrWriteFailed, todoFilePath, err)
+	}
+	return nil
+}
+
+// StampCreatedDate returns line with date inserted as its creation date
+// (after any priority). Lines that already have a date or are completed are
+// returned unchanged.
+func StampCreatedDate(line, date string) string {
+	if strings.HasPrefix(line, "x ") {
+		return line
+	}
+	prefix, rest := "", line
+	if ParsePriority(rest) != PriorityNone {
+		prefix, rest = rest[:3]+" ", strings.TrimLeft(rest[3:], " ")
+	}
+	if len(rest) >= 10 && ParseDate(rest[:10]) != "" {
+		return line
+	}
+	return prefix + date + " " + rest
+}
+
 // GetTodoFilePath returns the configured path to todo.txt
 func GetTodoFilePath() string {
 	return getTodoFilePath()
@@ -0,0 +1,71 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+)
+
+// DateFormat is the canonical todo.txt date layout (ISO 8601)
+const DateFormat = "2006-01-02"
+
+// flexibleDateLayouts are the input layouts accepted by ParseFlexibleDate,
+// tried in order. Numeric month/day layouts assume US ordering (month first).
+var flexibleDateLayouts = []string{
+	DateFormat,
+	"2006/01/02",
+	"2006.01.02",
+	"20060102",
+	"2006-1-2",
+	"2006/1/2",
+	"01-02-2006",
+	"01/02/2006",
+	"1/2/2006",
+	"1-2-2006",
+	"Jan 2 2006",
+	"Jan 2, 2006",
+	"January 2 2006",
+	"January 2, 2006",
+	"2 Jan 2006",
+	"2 January 2006",
+}
+
+// ParseFlexibleDate parses a date in any of several common formats and
+// returns the parsed time along with its canonical ISO (yyyy-MM-dd) form.
+func ParseFlexibleDate(s string) (time.Time, string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, "", fmt.Errorf("empty date")
+	}
+	for _, layout := range flexibleDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, t.Format(DateFormat), nil
+		}
+	}
+	return time.Time{}, "", fmt.Errorf("unrecognized date %q, use yyyy-MM-dd", s)
+}
+
+// FormatDisplayDate renders a yyyy-MM-dd date in the configured
+// display_date_format. Anything that isn't a valid date is returned as is.
+func FormatDisplayDate(date string) string {
+	layout := config.Get().GetDisplayDateLayout()
+	if layout == DateFormat {
+		return date
+	}
+	t, err := time.Parse(DateFormat, date)
+	if err != nil {
+		return date
+	}
+	return t.Format(layout)
+}
+
+// WeekBounds returns the first and last day (midnight) of the week that
+// contains day, starting the week on the configured week_start
+func WeekBounds(day time.Time) (time.Time, time.Time) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	offset := (int(day.Weekday()) - int(config.Get().GetWeekStart()) + 7) % 7
+	start := day.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 6)
+}
@@ -0,0 +1,91 @@
+package data
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+)
+
+func TestParseFlexibleDate(t *testing.T) {
+	valid := []string{
+		"2024-01-02",
+		"2024/01/02",
+		"2024.01.02",
+		"20240102",
+		"2024-1-2",
+		"01-02-2024",
+		"01/02/2024",
+		"1/2/2024",
+		"Jan 2 2024",
+		"Jan 2, 2024",
+		"January 2, 2024",
+		"2 Jan 2024",
+		"  2024-01-02  ",
+	}
+
+	for _, input := range valid {
+		t.Run(input, func(t *testing.T) {
+			parsed, iso, err := ParseFlexibleDate(input)
+			if err != nil {
+				t.Fatalf("ParseFlexibleDate(%q) error: %v", input, err)
+			}
+			if iso != "2024-01-02" {
+				t.Errorf("ParseFlexibleDate(%q) = %q, want %q", input, iso, "2024-01-02")
+			}
+			if parsed.Format(DateFormat) != iso {
+				t.Errorf("parsed time %v does not match ISO %q", parsed, iso)
+			}
+		})
+	}
+}
+
+func TestParseFlexibleDate_RejectsGarbage(t *testing.T) {
+	invalid := []string{
+		"",
+		"tomorrow",
+		"2024-13-40",
+		"02/30/2024",
+		"2024-01",
+		"not a date",
+	}
+
+	for _, input := range invalid {
+		if _, iso, err := ParseFlexibleDate(input); err == nil {
+			t.Errorf("ParseFlexibleDate(%q) = %q, want error", input, iso)
+		}
+	}
+}
+
+func TestWeekBounds(t *testing.T) {
+	defer func(orig string) { config.Get().WeekStart = orig }(config.Get().WeekStart)
+	defer func(orig func() time.Time) { Now = orig }(Now)
+	// A Wednesday afternoon
+	Now = func() time.Time { return time.Date(2024, 3, 13, 15, 30, 0, 0, time.UTC) }
+
+	tests := []struct {
+		weekStart  string
+		start, end string
+	}{
+		{"", "2024-03-11", "2024-03-17"},
+		{"monday", "2024-03-11", "2024-03-17"},
+		{"sunday", "2024-03-10", "2024-03-16"},
+	}
+	for _, tc := range tests {
+		config.Get().WeekStart = tc.weekStart
+		start, end := WeekBounds(Now())
+		if got := start.Format(DateFormat); got != tc.start {
+			t.Errorf("week_start %q: start = %s, want %s", tc.weekStart, got, tc.start)
+		}
+		if got := end.Format(DateFormat); got != tc.end {
+			t.Errorf("week_start %q: end = %s, want %s", tc.weekStart, got, tc.end)
+		}
+	}
+
+	// The first day of the week starts its own week
+	config.Get().WeekStart = "sunday"
+	start, _ := WeekBounds(time.Date(2024, 3, 10, 23, 0, 0, 0, time.UTC))
+	if got := start.Format(DateFormat); got != "2024-03-10" {
+		t.Errorf("start for a Sunday = %s, want the same day", got)
+	}
+}
@@ -0,0 +1,86 @@
+package data
+
+import (
+	"sort"
+	"strings"
+)
+
+// minFuzzyLength is the shortest name compared by edit distance; shorter
+// names (e.g. "pc" and "tv") are too easy to confuse, so they only match
+// when they differ by case
+const minFuzzyLength = 4
+
+// SimilarNames groups names that differ only by case or are within
+// maxDistance edits of each other (case-insensitively). Grouping is
+// transitive. Only groups of two or more are returned, each sorted, and the
+// groups are sorted by their first name.
+func SimilarNames(names []string, maxDistance int) [][]string {
+	parent := make([]int, len(names))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			if similarName(names[i], names[j], maxDistance) {
+				parent[find(i)] = find(j)
+			}
+		}
+	}
+
+	members := make(map[int][]string)
+	for i, name := range names {
+		root := find(i)
+		members[root] = append(members[root], name)
+	}
+	var groups [][]string
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		sort.Strings(group)
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
+
+func similarName(a, b string, maxDistance int) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	if a == b {
+		return true
+	}
+	if maxDistance <= 0 || len(a) < minFuzzyLength || len(b) < minFuzzyLength {
+		return false
+	}
+	return editDistance(a, b) <= maxDistance
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,39 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimilarNames(t *testing.T) {
+	names := []string{"errand", "Work", "errands", "work", "WORK", "home", "pc", "PC", "tv", "garden"}
+
+	got := SimilarNames(names, 1)
+	want := [][]string{{"PC", "pc"}, {"WORK", "Work", "work"}, {"errand", "errands"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SimilarNames(distance 1) = %v, want %v", got, want)
+	}
+
+	got = SimilarNames(names, 0)
+	want = [][]string{{"PC", "pc"}, {"WORK", "Work", "work"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SimilarNames(distance 0) = %v, want %v", got, want)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"errand", "errands", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+		{"same", "same", 0},
+	}
+	for _, tc := range tests {
+		if got := editDistance(tc.a, tc.b); got != tc.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// durationPattern matches tag durations such as 90, 45m, 2h or 1h30m.
+// Bare numbers are minutes.
+var durationPattern = regexp.MustCompile(`^(?:(\d+)h)?(?:(\d+)m?)?$`)
+
+// ParseMinutes parses a duration tag value into minutes
+func ParseMinutes(s string) (int, bool) {
+	m := durationPattern.FindStringSubmatch(s)
+	if m == nil || (m[1] == "" && m[2] == "") {
+		return 0, false
+	}
+	total := 0
+	if m[1] != "" {
+		hours, _ := strconv.Atoi(m[1])
+		total += hours * 60
+	}
+	if m[2] != "" {
+		minutes, _ := strconv.Atoi(m[2])
+		total += minutes
+	}
+	return total, true
+}
+
+// FormatMinutes renders minutes compactly: 45m, 2h, 1h30m
+func FormatMinutes(minutes int) string {
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
+
+// GetEstimate returns the est: tag in minutes
+func (t *Task) GetEstimate() (int, bool) {
+	return ParseMinutes(t.Tags["est"])
+}
+
+// GetSpent returns the spent: tag in minutes
+func (t *Task) GetSpent() (int, bool) {
+	return ParseMinutes(t.Tags["spent"])
+}
@@ -0,0 +1,56 @@
+package data
+
+import "testing"
+
+func TestParseMinutes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+		ok       bool
+	}{
+		{"90", 90, true},
+		{"45m", 45, true},
+		{"2h", 120, true},
+		{"1h30m", 90, true},
+		{"1h30", 90, true},
+		{"", 0, false},
+		{"h", 0, false},
+		{"soon", 0, false},
+		{"2d", 0, false},
+	}
+
+	for _, tc := range tests {
+		got, ok := ParseMinutes(tc.input)
+		if got != tc.expected || ok != tc.ok {
+			t.Errorf("ParseMinutes(%q) = %d, %v, want %d, %v", tc.input, got, ok, tc.expected, tc.ok)
+		}
+	}
+}
+
+func TestFormatMinutes(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{0, "0m"},
+		{45, "45m"},
+		{120, "2h"},
+		{90, "1h30m"},
+	}
+
+	for _, tc := range tests {
+		if got := FormatMinutes(tc.input); got != tc.expected {
+			t.Errorf("FormatMinutes(%d) = %q, want %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestTask_EstimateAndSpent(t *testing.T) {
+	task := ParseTask("Write report est:5h spent:90m", "1", "")
+	if est, ok := task.GetEstimate(); !ok || est != 300 {
+		t.Errorf("GetEstimate() = %d, %v, want 300, true", est, ok)
+	}
+	if spent, ok := task.GetSpent(); !ok || spent != 90 {
+		t.Errorf("GetSpent() = %d, %v, want 90, true", spent, ok)
+	}
+}
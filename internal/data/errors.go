@@ -0,0 +1,13 @@
+package data
+
+import "errors"
+
+// Errors the data layer wraps with context. Check for them with errors.Is.
+var (
+	// ErrTaskNotFound means no task has the given ID
+	ErrTaskNotFound = errors.New("no task found")
+	// ErrAmbiguousID means an ID prefix matches more than one task
+	ErrAmbiguousID = errors.New("multiple tasks match ID")
+	// ErrWriteFailed means todo.txt or done.txt couldn't be written
+	ErrWriteFailed = errors.New("error writing")
+)
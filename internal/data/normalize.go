@@ -0,0 +1,83 @@
+package data
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NormalizedLine pairs a line from a task file with its canonical form.
+type NormalizedLine struct {
+	LineNum    int
+	Original   string
+	Normalized string // empty for blank lines, which are dropped
+}
+
+// Changed reports whether normalizing alters or drops the line.
+func (l NormalizedLine) Changed() bool {
+	return l.Normalized == "" || l.Original != l.Normalized
+}
+
+// NormalizeLine re-emits a raw task line through ParseTask and String,
+// collapsing whitespace and putting metadata in canonical order.
+func NormalizeLine(line string) string {
+	if strings.TrimSpace(line) == "" {
+		return ""
+	}
+	return ParseTask(line, "", "").String()
+}
+
+// NormalizeFile reads a task file leniently and returns every line
+// alongside its normalized form. The file is not modified.
+func NormalizeFile(filePath string) ([]NormalizedLine, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []NormalizedLine
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		lines = append(lines, NormalizedLine{
+			LineNum:    lineNum,
+			Original:   line,
+			Normalized: NormalizeLine(line),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// WriteNormalized rewrites a task file with the normalized lines,
+// dropping blank lines. The task files are backed up first.
+func WriteNormalized(filePath string, lines []NormalizedLine) error {
+	mu.Lock()
+	defer mu.Unlock()
+	backupFiles()
+
+	f, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("%w %s: %v", ErrWriteFailed, filePath, err)
+	}
+	defer f.Close()
+
+	for _, l := range lines {
+		if l.Normalized == "" {
+			continue
+		}
+		if _, err := fmt.Fprintln(f, l.Normalized); err != nil {
+			return fmt.Errorf("%w to %s: %v", ErrWriteFailed, filePath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"already canonical", "(A) Buy milk +home @store", "(A) Buy milk +home @store"},
+		{"collapses whitespace", "  (A)   Buy   milk  +home\t@store  ", "(A) Buy milk +home @store"},
+		{"reorders metadata", "Buy milk due:2024-01-01 @store +home cost:5", "Buy milk +home @store cost:5 due:2024-01-01"},
+		{"sorts projects and contexts", "Plan +zeta @work +alpha @home", "Plan +alpha +zeta @home @work"},
+		{"lowercase priority", "(b) Call mom", "(B) Call mom"},
+		{"blank line", "   ", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := NormalizeLine(tc.input); got != tc.expected {
+				t.Errorf("NormalizeLine(%q) = %q, want %q", tc.input, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeFile_WriteNormalized(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	original := "Buy  milk @store +home\n\n(A) Call mom\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	lines, err := NormalizeFile(path)
+	if err != nil {
+		t.Fatalf("NormalizeFile: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	if !lines[0].Changed() || !lines[1].Changed() || lines[2].Changed() {
+		t.Errorf("unexpected Changed() results: %+v", lines)
+	}
+
+	// Reading must not touch the file
+	content, _ := os.ReadFile(path)
+	if string(content) != original {
+		t.Errorf("file modified by NormalizeFile: %q", content)
+	}
+
+	if err := WriteNormalized(path, lines); err != nil {
+		t.Fatalf("WriteNormalized: %v", err)
+	}
+	content, _ = os.ReadFile(path)
+	expected := "Buy milk +home @store\n(A) Call mom\n"
+	if string(content) != expected {
+		t.Errorf("file = %q, want %q", content, expected)
+	}
+}
@@ -1,6 +1,47 @@
 package data
 
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+)
+
 type Project struct {
-	Name      string
-	NotePath  *string
+	Name        string
+	NotePath    *string
+	Description string // first non-empty line of the note, if any
+}
+
+// readNoteDescription returns the first non-empty line of the note at path,
+// without any leading markdown heading marks. A missing or unreadable note
+// has no description.
+func readNoteDescription(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimLeft(scanner.Text(), "#"))
+		if line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// SortProjects returns the projects in the map sorted by name, so anything
+// printed from them comes out in a stable order
+func SortProjects(projects map[string]Project) []Project {
+	sorted := make([]Project, 0, len(projects))
+	for _, p := range projects {
+		sorted = append(sorted, p)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
 }
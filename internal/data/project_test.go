@@ -0,0 +1,59 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+)
+
+func TestReadNoteDescription(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name, content, want string
+	}{
+		{"first line", "Client work\nsecond line\n", "Client work"},
+		{"skips blank lines", "\n  \nClient work\n", "Client work"},
+		{"strips heading marks", "## Client work\n", "Client work"},
+		{"empty note", "\n\n", ""},
+	}
+	for _, tc := range tests {
+		path := filepath.Join(dir, tc.name+".md")
+		if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := readNoteDescription(path); got != tc.want {
+			t.Errorf("%s: description = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	if got := readNoteDescription(filepath.Join(dir, "missing.md")); got != "" {
+		t.Errorf("missing note: description = %q, want none", got)
+	}
+}
+
+func TestLoadData_MissingProjDir(t *testing.T) {
+	dir := t.TempDir()
+	config.Reset()
+	t.Cleanup(config.Reset)
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	config.Get().ProjDir = filepath.Join(dir, "not", "created")
+	if err := os.WriteFile(GetTodoFilePath(), []byte("Call mom +family\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, projects, err := LoadData(false)
+	if err != nil {
+		t.Fatalf("LoadData with a missing proj_dir: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Errorf("loaded %d tasks, want 1", len(tasks))
+	}
+	if len(projects) != 1 || projects["family"].NotePath != nil {
+		t.Errorf("projects = %v, want just family without a note", projects)
+	}
+}
@@ -0,0 +1,76 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// recurrencePattern matches rec: values such as 1w, 3d or 2m
+var recurrencePattern = regexp.MustCompile(`^(\d+)([dwmy])$`)
+
+// Recurrence is the interval of a rec: tag
+type Recurrence struct {
+	N    int
+	Unit byte // d, w, m or y
+}
+
+// ParseRecurrence parses a rec: tag value
+func ParseRecurrence(s string) (Recurrence, bool) {
+	m := recurrencePattern.FindStringSubmatch(s)
+	if m == nil {
+		return Recurrence{}, false
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n == 0 {
+		return Recurrence{}, false
+	}
+	return Recurrence{N: n, Unit: m[2][0]}, true
+}
+
+// String renders the recurrence as a rec: value
+func (r Recurrence) String() string {
+	return fmt.Sprintf("%d%c", r.N, r.Unit)
+}
+
+// After returns the date one interval after from
+func (r Recurrence) After(from time.Time) time.Time {
+	switch r.Unit {
+	case 'w':
+		return from.AddDate(0, 0, 7*r.N)
+	case 'm':
+		return from.AddDate(0, r.N, 0)
+	case 'y':
+		return from.AddDate(r.N, 0, 0)
+	}
+	return from.AddDate(0, 0, r.N)
+}
+
+// GetRecurrence returns the task's rec: tag
+func (t *Task) GetRecurrence() (Recurrence, bool) {
+	return ParseRecurrence(t.Tags["rec"])
+}
+
+// SetRecurrence sets the rec: tag, or removes it when rec is empty
+func (t *Task) SetRecurrence(rec string) {
+	if rec == "" {
+		delete(t.Tags, "rec")
+		return
+	}
+	if t.Tags == nil {
+		t.Tags = make(map[string]string)
+	}
+	t.Tags["rec"] = rec
+}
+
+// NextOccurrence returns the due date (yyyy-MM-dd) the next instance of a
+// recurring task gets when this one is done on done: one interval later.
+// Returns "" if the task doesn't recur.
+func (t *Task) NextOccurrence(done time.Time) string {
+	rec, ok := t.GetRecurrence()
+	if !ok {
+		return ""
+	}
+	return rec.After(done).Format(DateFormat)
+}
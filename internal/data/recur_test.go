@@ -0,0 +1,55 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Recurrence
+		ok    bool
+	}{
+		{"1w", Recurrence{N: 1, Unit: 'w'}, true},
+		{"3d", Recurrence{N: 3, Unit: 'd'}, true},
+		{"2m", Recurrence{N: 2, Unit: 'm'}, true},
+		{"1y", Recurrence{N: 1, Unit: 'y'}, true},
+		{"0w", Recurrence{}, false},
+		{"w", Recurrence{}, false},
+		{"2h", Recurrence{}, false},
+		{"2024-12-31", Recurrence{}, false},
+	}
+
+	for _, tc := range tests {
+		got, ok := ParseRecurrence(tc.input)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ParseRecurrence(%q) = %+v, %v, want %+v, %v", tc.input, got, ok, tc.want, tc.ok)
+		}
+		if ok && got.String() != tc.input {
+			t.Errorf("String() = %q, want %q", got.String(), tc.input)
+		}
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	done := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"Water plants rec:1w", "2024-03-22"},
+		{"Water plants due:2024-03-10 rec:1w", "2024-03-22"},
+		{"Pay rent rec:1m", "2024-04-15"},
+		{"Renew passport rec:10y", "2034-03-15"},
+		{"Not recurring", ""},
+		{"Bad interval rec:soon", ""},
+	}
+
+	for _, tc := range tests {
+		task := ParseTask(tc.line, "1", "")
+		if got := task.NextOccurrence(done); got != tc.want {
+			t.Errorf("NextOccurrence(%q) = %q, want %q", tc.line, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package data
+
+// Subtasks link to their parent with a parent: tag naming the parent's id:
+// tag, e.g. "Plan trip id:trip" and "Book flights parent:trip".
+
+// SubtaskIndexes returns the indexes in tasks of parent's subtasks, then of
+// their subtasks in turn. A parent without an id: tag has none.
+func SubtaskIndexes(tasks []Task, parent Task) []int {
+	var indexes []int
+	seen := make(map[int]bool)
+	queue := []string{parent.Tags["id"]}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == "" {
+			continue
+		}
+		for i, t := range tasks {
+			if seen[i] || t.ID == parent.ID || t.Tags["parent"] != id {
+				continue
+			}
+			seen[i] = true
+			indexes = append(indexes, i)
+			queue = append(queue, t.Tags["id"])
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,26 @@
+package data
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSubtaskIndexes(t *testing.T) {
+	tasks := []Task{
+		ParseTask("Plan trip id:trip", "a", ""),
+		ParseTask("Book flights parent:trip id:flights", "b", ""),
+		ParseTask("Unrelated", "c", ""),
+		ParseTask("Pick seats parent:flights", "d", ""),
+		ParseTask("Loop parent:loop id:loop", "e", ""),
+	}
+
+	if got := SubtaskIndexes(tasks, tasks[0]); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("SubtaskIndexes(trip) = %v, want [1 3] (nested subtasks included)", got)
+	}
+	if got := SubtaskIndexes(tasks, tasks[2]); got != nil {
+		t.Errorf("SubtaskIndexes(no id) = %v, want none", got)
+	}
+	if got := SubtaskIndexes(tasks, tasks[4]); got != nil {
+		t.Errorf("SubtaskIndexes(self-parent) = %v, want none", got)
+	}
+}
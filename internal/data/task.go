@@ -6,6 +6,10 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/logs"
 )
 
 type Priority rune
@@ -26,7 +30,7 @@ type Task struct {
 	Projects       []string
 	Contexts       []string
 	Done           bool
-	Tags           map[string]string
+	Tags           map[string]string // one value per key (see ParseTags)
 	CreatedDate    string
 	CompletionDate string
 	Priority       Priority
@@ -52,6 +56,23 @@ func (t *Task) RemoveProject(project string) {
 	}
 }
 
+// SetProjects replaces the task's projects with a trimmed, deduplicated and
+// sorted copy of projects, matching what ParseTask produces.
+func (t *Task) SetProjects(projects []string) {
+	t.Projects = normalizeNames(projects)
+}
+
+// RenameProject replaces project from with to, dropping from if the task
+// already has to. Reports whether the task changed.
+func (t *Task) RenameProject(from, to string) bool {
+	if from == to || !t.HasProject(from) {
+		return false
+	}
+	t.RemoveProject(from)
+	t.AddProject(to)
+	return true
+}
+
 func (t *Task) HasContext(context string) bool {
 	return slices.Contains(t.Contexts, context)
 }
@@ -71,14 +92,228 @@ func (t *Task) RemoveContext(context string) {
 	}
 }
 
+// SetContexts replaces the task's contexts with a trimmed, deduplicated and
+// sorted copy of contexts, matching what ParseTask produces.
+func (t *Task) SetContexts(contexts []string) {
+	t.Contexts = normalizeNames(contexts)
+}
+
+// RenameContext replaces context from with to, dropping from if the task
+// already has to. Reports whether the task changed.
+func (t *Task) RenameContext(from, to string) bool {
+	if from == to || !t.HasContext(from) {
+		return false
+	}
+	t.RemoveContext(from)
+	t.AddContext(to)
+	return true
+}
+
+// RenameTag moves tag key from to key to, keeping the value. If the task
+// already has a to tag, that value wins and from is dropped. Reports whether
+// the task changed.
+func (t *Task) RenameTag(from, to string) bool {
+	value, ok := t.Tags[from]
+	if from == to || !ok {
+		return false
+	}
+	delete(t.Tags, from)
+	if _, exists := t.Tags[to]; !exists {
+		t.Tags[to] = value
+	}
+	return true
+}
+
+// GetDueDate returns the due: tag. The tag is the only place a due date is
+// kept, so read and write it through GetDueDate and SetDueDate.
 func (t *Task) GetDueDate() string {
 	return t.Tags["due"]
 }
 
+// HasInvalidDueDate reports whether the task has a due: tag that isn't a
+// date ParseFlexibleDate understands (e.g. due:2024-13-40)
+func (t *Task) HasInvalidDueDate() bool {
+	return t.GetDueDate() != "" && t.ValidDueDate() == ""
+}
+
+// ValidDueDate returns the due: tag as a yyyy-MM-dd date, whichever format
+// ParseFlexibleDate read it in, or "" if it's missing or invalid. ISO dates
+// order as strings, so compare these rather than raw tags.
+func (t *Task) ValidDueDate() string {
+	_, iso, err := ParseFlexibleDate(t.GetDueDate())
+	if err != nil {
+		return ""
+	}
+	return iso
+}
+
+// GetThresholdDate returns the t: tag, the date before which the task
+// shouldn't be worked on
+func (t *Task) GetThresholdDate() string {
+	return t.Tags["t"]
+}
+
+// IsBlocked reports whether the task is waiting on something else: it has
+// a blocked: tag (blocked:<reason>) or the @waiting context
+func (t *Task) IsBlocked() bool {
+	return t.Tags["blocked"] != "" || t.HasContext("waiting")
+}
+
+// IsFuture reports whether the task has a threshold date after today
+// (yyyy-MM-dd), in any format ParseFlexibleDate reads. Invalid threshold
+// dates are ignored.
+func (t *Task) IsFuture(today string) bool {
+	_, threshold, err := ParseFlexibleDate(t.GetThresholdDate())
+	if err != nil {
+		return false
+	}
+	return threshold > today
+}
+
+// IsActionable reports whether the task meets every rule (one of
+// config.ActionableRules) as of today (yyyy-MM-dd)
+func (t *Task) IsActionable(rules []string, today string) bool {
+	for _, rule := range rules {
+		var ok bool
+		switch rule {
+		case "pending":
+			ok = !t.Done
+		case "not_future":
+			ok = !t.IsFuture(today)
+		case "not_blocked":
+			ok = !t.IsBlocked()
+		case "has_context":
+			ok = len(t.Contexts) > 0
+		case "has_project":
+			ok = len(t.Projects) > 0
+		case "has_due":
+			ok = t.GetDueDate() != ""
+		case "has_priority":
+			ok = t.Priority != PriorityNone
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SetDueDate sets the due: tag, replacing any existing due date. An empty
+// date removes it.
 func (t *Task) SetDueDate(date string) {
+	if date == "" {
+		delete(t.Tags, "due")
+		return
+	}
+	if t.Tags == nil {
+		t.Tags = make(map[string]string)
+	}
 	t.Tags["due"] = date
 }
 
+// Defer moves the due date days later, or sets it to today+days when the
+// task has no valid due date
+func (t *Task) Defer(days int, today time.Time) {
+	base := today
+	if due, _, err := ParseFlexibleDate(t.GetDueDate()); err == nil {
+		base = due
+	}
+	t.SetDueDate(base.AddDate(0, 0, days).Format(DateFormat))
+}
+
+// PromotePriority raises the priority one step (B→A), stopping at A. A task
+// without a priority becomes A, and one below lowest is raised to lowest.
+func (t *Task) PromotePriority(lowest Priority) {
+	switch {
+	case t.Priority == PriorityNone:
+		t.Priority = PriorityA
+	case t.Priority > lowest:
+		t.Priority = lowest
+	case t.Priority > PriorityA:
+		t.Priority--
+	}
+}
+
+// DemotePriority lowers the priority one step (A→B). Demoting the lowest
+// priority (or anything below it) removes the priority; no priority stays none.
+func (t *Task) DemotePriority(lowest Priority) {
+	switch {
+	case t.Priority == PriorityNone:
+	case t.Priority >= lowest:
+		t.Priority = PriorityNone
+	default:
+		t.Priority++
+	}
+}
+
+// Complete marks the task as done on the given date (yyyy-MM-dd).
+// todo.txt drops the priority of completed tasks; when preservePriority is
+// set, the priority is kept as a pri: tag so Uncomplete can restore it.
+func (t *Task) Complete(date string, preservePriority bool) {
+	t.Done = true
+	t.CompletionDate = date
+	if preservePriority && t.Priority != PriorityNone {
+		if t.Tags == nil {
+			t.Tags = make(map[string]string)
+		}
+		t.Tags["pri"] = string(t.Priority)
+		t.Priority = PriorityNone
+	}
+}
+
+// Uncomplete marks the task as pending again, restoring a priority that
+// was preserved in a pri: tag.
+func (t *Task) Uncomplete() {
+	t.Done = false
+	t.CompletionDate = ""
+	if pri, ok := t.Tags["pri"]; ok {
+		if p := ParsePriority("(" + pri + ")"); p != PriorityNone {
+			t.Priority = p
+		}
+		delete(t.Tags, "pri")
+	}
+}
+
+// Duplicate returns a pending copy of the task created on the given date.
+// The copy has no ID or file; callers assign those when persisting it.
+func (t Task) Duplicate(createdDate string) Task {
+	dup := t
+	dup.ID = ""
+	dup.File = ""
+	dup.Projects = slices.Clone(t.Projects)
+	dup.Contexts = slices.Clone(t.Contexts)
+	dup.Tags = make(map[string]string, len(t.Tags))
+	for k, v := range t.Tags {
+		dup.Tags[k] = v
+	}
+	dup.Uncomplete()
+	dup.CreatedDate = createdDate
+	return dup
+}
+
+// Split breaks the task name on sep into one pending task per non-empty
+// part, each created on createdDate and inheriting the task's priority,
+// projects, contexts and tags. Returns nil if there is nothing to split.
+func (t Task) Split(sep string, createdDate string) []Task {
+	if sep == "" {
+		return nil
+	}
+	var parts []Task
+	for _, name := range strings.Split(t.Name, sep) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		part := t.Duplicate(createdDate)
+		part.Name = name
+		parts = append(parts, part)
+	}
+	if len(parts) < 2 {
+		return nil
+	}
+	return parts
+}
+
 func (t Task) String() string {
 	var parts []string
 
@@ -121,9 +356,14 @@ func (t Task) String() string {
 		parts = append(parts, "@"+c)
 	}
 
-	// Tags
-	for k, v := range t.Tags {
-		parts = append(parts, k+":"+v)
+	// Tags (sorted by key so output is deterministic)
+	keys := make([]string, 0, len(t.Tags))
+	for k := range t.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+":"+t.Tags[k])
 	}
 
 	return strings.Join(parts, " ")
@@ -143,7 +383,9 @@ func (t Task) Print() {
 
 func ParseTask(input string, id string, file string) Task {
 	input = strings.TrimSpace(input)
-	input = CollapseWhitespace(input)
+	if !config.Get().GetPreserveWhitespace() {
+		input = CollapseWhitespace(input)
+	}
 
 	var t Task
 	t.ID = id
@@ -165,11 +407,12 @@ func ParseTask(input string, id string, file string) Task {
 		input = input[3:]
 	}
 
-	if input[0] == ' ' {
+	// Nothing may follow the priority, as in "(A)"
+	if len(input) > 0 && (input[0] == ' ' || input[0] == '\t') {
 		input = input[1:]
 	}
 
-	input = strings.TrimLeft(input, " ")
+	input = strings.TrimLeft(input, " \t")
 
 	// Parse first date
 	firstDate := ""
@@ -177,7 +420,7 @@ func ParseTask(input string, id string, file string) Task {
 		firstDate = ParseDate(input[:10])
 		if firstDate != "" {
 			input = input[10:]
-			input = strings.TrimLeft(input, " ")
+			input = strings.TrimLeft(input, " \t")
 		}
 	}
 
@@ -187,7 +430,7 @@ func ParseTask(input string, id string, file string) Task {
 		secondDate = ParseDate(input[:10])
 		if secondDate != "" {
 			input = input[10:]
-			input = strings.TrimLeft(input, " ")
+			input = strings.TrimLeft(input, " \t")
 		}
 	}
 
@@ -195,7 +438,7 @@ func ParseTask(input string, id string, file string) Task {
 	if t.Done && t.Priority == PriorityNone {
 		t.Priority = ParsePriority(input)
 		if t.Priority != PriorityNone {
-			input = input[4:] // "(A) " = 4 chars
+			input = input[3:] // "(A)"; the space after it is trimmed below
 		}
 	}
 
@@ -212,7 +455,7 @@ func ParseTask(input string, id string, file string) Task {
 		}
 	}
 
-	input = strings.TrimLeft(input, " ")
+	input = strings.TrimLeft(input, " \t")
 
 	if len(input) == 0 {
 		return t
@@ -232,11 +475,9 @@ func ParseTask(input string, id string, file string) Task {
 
 	t.Name = strings.TrimSpace(input[:firstMetaIdx])
 
-	t.Projects = ParseProjects(input)
-	sort.Strings(t.Projects)
+	t.SetProjects(ParseProjects(input))
 
-	t.Contexts = ParseContexts(input)
-	sort.Strings(t.Contexts)
+	t.SetContexts(ParseContexts(input))
 
 	t.Tags = ParseTags(input)
 
@@ -277,8 +518,23 @@ func FirstTagIndex(s string) int {
 	return -1
 }
 
+// normalizeNames trims each name, drops empty and duplicate entries and
+// returns the rest sorted. It returns nil when nothing is left.
+func normalizeNames(names []string) []string {
+	var out []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n != "" && !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 func ParseProjects(s string) []string {
-	re := regexp.MustCompile(`[ \t]\+[A-Za-z0-9]+`)
+	// Dots separate levels of a project hierarchy (+work.clientA)
+	re := regexp.MustCompile(`[ \t]\+[A-Za-z0-9]+(?:\.[A-Za-z0-9]+)*`)
 	matches := re.FindAllString(s, -1)
 	for i, m := range matches {
 		matches[i] = m[2:]
@@ -295,14 +551,22 @@ func ParseContexts(s string) []string {
 	return matches
 }
 
+// ParseTags returns the key:value tags in s. Tags hold one value per key: a
+// key that appears more than once keeps its last value, as due: always has,
+// so "ref:a ref:b" parses to ref:b and String writes a single tag back. The
+// dropped values are logged (and show up in wydo normalize).
 func ParseTags(s string) map[string]string {
-	re := regexp.MustCompile(`[ \t]([A-Za-z0-9]+)\:([A-Za-z0-9-]+)`)
+	// Values are plain tokens, or URLs such as url:https://example.com/a?b=c
+	re := regexp.MustCompile(`[ \t]([A-Za-z0-9]+)\:([A-Za-z0-9-]+(?:://[^\s]+)?)`)
 	matches := re.FindAllStringSubmatch(s, -1)
 	tags := make(map[string]string)
 	for _, m := range matches {
 		if len(m) == 3 {
 			key := m[1]
 			value := m[2]
+			if prev, ok := tags[key]; ok && prev != value {
+				logs.Logger.Printf("tag %q repeated: keeping %q, dropping %q", key, value, prev)
+			}
 			tags[key] = value
 		}
 	}
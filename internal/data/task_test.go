@@ -2,8 +2,13 @@ package data
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
 )
 
 func TestParseTask_TableDriven(t *testing.T) {
@@ -643,3 +648,402 @@ func TestFirstMetaIndex_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestTask_CompletePreservesPriority(t *testing.T) {
+	task := ParseTask("(A) 2023-01-01 Finish report +work", "abc", "todo.txt")
+
+	task.Complete("2023-02-01", true)
+	if !task.Done {
+		t.Fatal("expected task to be done")
+	}
+	if task.Priority != PriorityNone {
+		t.Errorf("expected bracket priority to be removed, got %q", task.Priority)
+	}
+	if task.Tags["pri"] != "A" {
+		t.Errorf("expected pri:A tag, got %q", task.Tags["pri"])
+	}
+	expected := "x 2023-02-01 2023-01-01 Finish report +work pri:A"
+	if task.String() != expected {
+		t.Errorf("String() = %q, want %q", task.String(), expected)
+	}
+
+	task.Uncomplete()
+	if task.Done || task.CompletionDate != "" {
+		t.Errorf("expected task to be pending with no completion date, got done=%v date=%q", task.Done, task.CompletionDate)
+	}
+	if task.Priority != PriorityA {
+		t.Errorf("expected priority A to be restored, got %q", task.Priority)
+	}
+	if _, ok := task.Tags["pri"]; ok {
+		t.Error("expected pri tag to be removed")
+	}
+	expected = "(A) 2023-01-01 Finish report +work"
+	if task.String() != expected {
+		t.Errorf("String() = %q, want %q", task.String(), expected)
+	}
+}
+
+func TestTask_CompleteWithoutPreservingPriority(t *testing.T) {
+	task := ParseTask("(B) Finish report", "abc", "todo.txt")
+
+	task.Complete("2023-02-01", false)
+	if task.Priority != PriorityB {
+		t.Errorf("expected priority to be untouched, got %q", task.Priority)
+	}
+	if _, ok := task.Tags["pri"]; ok {
+		t.Error("expected no pri tag")
+	}
+}
+
+func TestTask_Duplicate(t *testing.T) {
+	original := ParseTask("x 2023-02-01 2023-01-01 Finish report +work @office pri:A", "abc", "done.txt")
+
+	dup := original.Duplicate("2023-03-01")
+	if dup.ID != "" || dup.File != "" {
+		t.Errorf("expected empty ID and file, got %q and %q", dup.ID, dup.File)
+	}
+	if dup.Done || dup.CompletionDate != "" {
+		t.Errorf("expected pending copy, got done=%v completion=%q", dup.Done, dup.CompletionDate)
+	}
+	if dup.CreatedDate != "2023-03-01" {
+		t.Errorf("CreatedDate = %q, want %q", dup.CreatedDate, "2023-03-01")
+	}
+	if dup.Priority != PriorityA {
+		t.Errorf("expected preserved priority A to be restored, got %q", dup.Priority)
+	}
+	expected := "(A) 2023-03-01 Finish report +work @office"
+	if dup.String() != expected {
+		t.Errorf("String() = %q, want %q", dup.String(), expected)
+	}
+
+	// The copy must not share slices or maps with the original
+	dup.Projects[0] = "changed"
+	dup.Tags["new"] = "tag"
+	if original.Projects[0] != "work" {
+		t.Error("modifying duplicate's projects changed the original")
+	}
+	if _, ok := original.Tags["new"]; ok {
+		t.Error("modifying duplicate's tags changed the original")
+	}
+}
+
+func TestHasInvalidDueDate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"Pay rent due:2024-01-31", false},
+		{"Pay rent due:2024-13-40", true},
+		{"Pay rent due:soon", true},
+		{"Pay rent due:2024-1-31", false}, // the date filter reads it too
+		{"Pay rent", false},
+	}
+
+	for _, tc := range tests {
+		task := ParseTask(tc.input, "abc", "todo.txt")
+		if got := task.HasInvalidDueDate(); got != tc.expected {
+			t.Errorf("HasInvalidDueDate(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestValidDueDate(t *testing.T) {
+	tests := map[string]string{
+		"Pay rent due:2024-01-31": "2024-01-31",
+		"Pay rent due:01-31-2024": "2024-01-31",
+		"Pay rent due:2024-13-40": "",
+		"Pay rent":                "",
+	}
+	for input, want := range tests {
+		task := ParseTask(input, "abc", "todo.txt")
+		if got := task.ValidDueDate(); got != want {
+			t.Errorf("ValidDueDate(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestString_SortsTags(t *testing.T) {
+	task := ParseTask("Plan trip due:2024-01-01 cost:1000 area:home", "1", "")
+	expected := "Plan trip area:home cost:1000 due:2024-01-01"
+	for i := 0; i < 10; i++ {
+		if got := task.String(); got != expected {
+			t.Fatalf("String() = %q, want %q", got, expected)
+		}
+	}
+}
+
+func TestParseTask_HierarchicalProjects(t *testing.T) {
+	task := ParseTask("Send invoice +work.clientA +home. @office", "1", "")
+	expected := []string{"home", "work.clientA"}
+	if !equalStringSlices(task.Projects, expected) {
+		t.Errorf("Projects = %v, want %v", task.Projects, expected)
+	}
+}
+
+func TestParseTask_URLTag(t *testing.T) {
+	input := "Read docs due:2024-01-01 url:https://example.com/a?b=c"
+	task := ParseTask(input, "1", "")
+	if task.Tags["url"] != "https://example.com/a?b=c" {
+		t.Errorf("url tag = %q, want %q", task.Tags["url"], "https://example.com/a?b=c")
+	}
+	if task.String() != input {
+		t.Errorf("String() = %q, want %q", task.String(), input)
+	}
+}
+
+func TestIsFuture(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"Plan trip t:2024-06-01", true},
+		{"Plan trip t:2024-01-01", false},
+		{"Plan trip t:2023-12-31", false},
+		{"Plan trip", false},
+		{"Plan trip t:someday", false},
+		// Other formats compare by their ISO form
+		{"Plan trip t:2024-6-1", true},
+		{"Plan trip t:06-01-2024", true},
+		{"Plan trip t:12-31-2023", false},
+	}
+
+	for _, tc := range tests {
+		task := ParseTask(tc.input, "1", "")
+		if got := task.IsFuture("2024-01-01"); got != tc.expected {
+			t.Errorf("IsFuture(%q) = %v, want %v", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestTask_Split(t *testing.T) {
+	original := ParseTask("(B) Buy milk; call bank ;pay rent +home @errands due:2024-02-01", "abc", "todo.txt")
+
+	parts := original.Split(";", "2024-01-15")
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	names := []string{"Buy milk", "call bank", "pay rent"}
+	for i, p := range parts {
+		if p.Name != names[i] {
+			t.Errorf("part %d Name = %q, want %q", i, p.Name, names[i])
+		}
+		if p.Priority != PriorityB || !p.HasProject("home") || !p.HasContext("errands") {
+			t.Errorf("part %d did not inherit metadata: %q", i, p.String())
+		}
+		if p.GetDueDate() != "2024-02-01" || p.CreatedDate != "2024-01-15" {
+			t.Errorf("part %d dates = %q/%q", i, p.GetDueDate(), p.CreatedDate)
+		}
+	}
+
+	if got := original.Split(",", "2024-01-15"); got != nil {
+		t.Errorf("expected nil when the delimiter is absent, got %v", got)
+	}
+}
+
+func TestTask_Defer(t *testing.T) {
+	today := time.Date(2024, 2, 28, 9, 0, 0, 0, time.UTC)
+
+	withDue := ParseTask("Pay rent due:2024-03-01", "1", "todo.txt")
+	withDue.Defer(1, today)
+	if got := withDue.GetDueDate(); got != "2024-03-02" {
+		t.Errorf("deferred due = %q, want %q", got, "2024-03-02")
+	}
+
+	noDue := ParseTask("Call mom", "2", "todo.txt")
+	noDue.Defer(2, today)
+	if got := noDue.GetDueDate(); got != "2024-03-01" {
+		t.Errorf("due without prior date = %q, want today+2 %q", got, "2024-03-01")
+	}
+
+	invalid := ParseTask("Fix bike due:soon", "3", "todo.txt")
+	invalid.Defer(1, today)
+	if got := invalid.GetDueDate(); got != "2024-02-29" {
+		t.Errorf("due from invalid date = %q, want today+1 %q", got, "2024-02-29")
+	}
+}
+
+func TestTask_PromoteDemotePriority(t *testing.T) {
+	tests := []struct {
+		start   Priority
+		lowest  Priority
+		promote Priority
+		demote  Priority
+	}{
+		{PriorityNone, PriorityF, PriorityA, PriorityNone},
+		{PriorityA, PriorityF, PriorityA, PriorityB},
+		{PriorityB, PriorityF, PriorityA, PriorityC},
+		{PriorityF, PriorityF, PriorityE, PriorityNone},
+		{PriorityC, PriorityC, PriorityB, PriorityNone},
+		{PriorityE, PriorityC, PriorityC, PriorityNone},
+	}
+
+	for _, tc := range tests {
+		task := Task{Priority: tc.start}
+		task.PromotePriority(tc.lowest)
+		if task.Priority != tc.promote {
+			t.Errorf("promote %q (lowest %c) = %q, want %q", tc.start, tc.lowest, task.Priority, tc.promote)
+		}
+
+		task = Task{Priority: tc.start}
+		task.DemotePriority(tc.lowest)
+		if task.Priority != tc.demote {
+			t.Errorf("demote %q (lowest %c) = %q, want %q", tc.start, tc.lowest, task.Priority, tc.demote)
+		}
+	}
+}
+
+func TestTask_RenameProject(t *testing.T) {
+	task := Task{Projects: []string{"Errand", "home"}}
+	if !task.RenameProject("Errand", "errand") {
+		t.Fatal("expected a rename")
+	}
+	if !task.HasProject("errand") || task.HasProject("Errand") {
+		t.Errorf("Projects = %v, want errand instead of Errand", task.Projects)
+	}
+
+	// Renaming onto a project the task already has doesn't duplicate it
+	task.RenameProject("home", "errand")
+	if len(task.Projects) != 1 {
+		t.Errorf("Projects = %v, want just errand", task.Projects)
+	}
+	if task.RenameProject("missing", "errand") {
+		t.Error("expected no change for a project the task doesn't have")
+	}
+}
+
+func TestParseTask_PriorityOnly(t *testing.T) {
+	for _, input := range []string{"(A)", "x (A)", "x 2024-03-01 (A)"} {
+		task := ParseTask(input, "", "")
+		if task.Priority != PriorityA || task.Name != "" {
+			t.Errorf("ParseTask(%q) = priority %q name %q, want priority A and no name", input, task.Priority, task.Name)
+		}
+	}
+}
+
+func TestTask_SetProjectsAndContexts(t *testing.T) {
+	var task Task
+	task.SetProjects([]string{"work", " home", "work", "", "errand "})
+	if want := []string{"errand", "home", "work"}; !slices.Equal(task.Projects, want) {
+		t.Errorf("Projects = %v, want %v", task.Projects, want)
+	}
+	task.SetContexts([]string{"phone", "desk", "phone"})
+	if want := []string{"desk", "phone"}; !slices.Equal(task.Contexts, want) {
+		t.Errorf("Contexts = %v, want %v", task.Contexts, want)
+	}
+
+	// Setting the parsed lists back leaves a freshly parsed task unchanged
+	parsed := ParseTask("Call mom +home +errand @phone", "", "")
+	before := parsed.String()
+	parsed.SetProjects(parsed.Projects)
+	parsed.SetContexts(parsed.Contexts)
+	if after := parsed.String(); after != before {
+		t.Errorf("String() = %q after SetProjects/SetContexts, want %q", after, before)
+	}
+}
+
+func TestTask_SingleDueDate(t *testing.T) {
+	// A line with two due: tags keeps the last one and writes it back once
+	task := ParseTask("Pay rent due:2024-01-01 due:2024-02-01", "1", "todo.txt")
+	if task.GetDueDate() != "2024-02-01" {
+		t.Errorf("GetDueDate() = %q, want 2024-02-01", task.GetDueDate())
+	}
+	if got := task.String(); got != "Pay rent due:2024-02-01" {
+		t.Errorf("String() = %q, want a single due tag", got)
+	}
+
+	// Setting replaces the existing value rather than adding a second one
+	task.SetDueDate("2024-03-01")
+	line := task.String()
+	if strings.Count(line, "due:") != 1 || !strings.Contains(line, "due:2024-03-01") {
+		t.Errorf("String() = %q, want exactly due:2024-03-01", line)
+	}
+	if reparsed := ParseTask(line, "1", "todo.txt"); reparsed.GetDueDate() != "2024-03-01" || reparsed.String() != line {
+		t.Errorf("round trip: got %q due %q", reparsed.String(), reparsed.GetDueDate())
+	}
+
+	// Clearing removes the tag instead of leaving an empty "due:"
+	task.SetDueDate("")
+	if got := task.String(); got != "Pay rent" {
+		t.Errorf("String() after clearing = %q, want %q", got, "Pay rent")
+	}
+
+	// A task built without tags can still be given a due date
+	bare := Task{Name: "Call mom"}
+	bare.SetDueDate("2024-04-01")
+	if bare.String() != "Call mom due:2024-04-01" {
+		t.Errorf("String() = %q", bare.String())
+	}
+}
+
+func TestParseTask_PreserveWhitespace(t *testing.T) {
+	defer func(orig bool) { config.Get().PreserveWhitespace = orig }(config.Get().PreserveWhitespace)
+	line := "(A) 2024-03-01  Invoice   total:\t$40   +work @desk due:2024-03-05  "
+
+	config.Get().PreserveWhitespace = false
+	if got := ParseTask(line, "", "").Name; got != "Invoice total: $40" {
+		t.Errorf("collapsed name = %q, want %q", got, "Invoice total: $40")
+	}
+
+	config.Get().PreserveWhitespace = true
+	task := ParseTask(line, "", "")
+	if want := "Invoice   total:\t$40"; task.Name != want {
+		t.Errorf("preserved name = %q, want %q", task.Name, want)
+	}
+	if task.Priority != PriorityA || task.CreatedDate != "2024-03-01" {
+		t.Errorf("priority/created = %q/%q, want A/2024-03-01", task.Priority, task.CreatedDate)
+	}
+	if !slices.Equal(task.Projects, []string{"work"}) || !slices.Equal(task.Contexts, []string{"desk"}) || task.GetDueDate() != "2024-03-05" {
+		t.Errorf("metadata = %v %v %q, want [work] [desk] 2024-03-05", task.Projects, task.Contexts, task.GetDueDate())
+	}
+
+	if again := ParseTask(task.String(), "", ""); again.Name != task.Name {
+		t.Errorf("round-tripped name = %q, want %q", again.Name, task.Name)
+	}
+}
+
+func TestTask_IsActionable(t *testing.T) {
+	today := "2024-03-10"
+	tasks := map[string]Task{
+		"plain":   ParseTask("Write report", "", ""),
+		"context": ParseTask("(A) Call bank @phone due:2024-03-12", "", ""),
+		"done":    ParseTask("x 2024-03-09 Filed taxes @desk", "", ""),
+		"future":  ParseTask("Renew passport @errands t:2024-04-01", "", ""),
+		"blocked": ParseTask("Sign contract @desk blocked:legal", "", ""),
+	}
+	tests := []struct {
+		rules []string
+		want  []string
+	}{
+		{[]string{"pending", "not_future", "not_blocked"}, []string{"context", "plain"}},
+		{[]string{"pending", "has_context"}, []string{"blocked", "context", "future"}},
+		{[]string{"has_due", "has_priority"}, []string{"context"}},
+		{nil, []string{"blocked", "context", "done", "future", "plain"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for name, task := range tasks {
+			if task.IsActionable(tc.rules, today) {
+				got = append(got, name)
+			}
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("rules %v: actionable = %v, want %v", tc.rules, got, tc.want)
+		}
+	}
+}
+
+func TestParseTask_RepeatedTagKey(t *testing.T) {
+	// Every repeated key keeps its last value, like due: (TestTask_SingleDueDate)
+	task := ParseTask("Read paper ref:smith2020 +research ref:jones2021 due:2024-05-01", "1", "todo.txt")
+	if got := task.Tags["ref"]; got != "jones2021" {
+		t.Errorf("ref = %q, want the last value jones2021", got)
+	}
+	line := task.String()
+	if want := "Read paper +research due:2024-05-01 ref:jones2021"; line != want {
+		t.Errorf("String() = %q, want %q", line, want)
+	}
+	if reparsed := ParseTask(line, "1", "todo.txt"); reparsed.String() != line || !maps.Equal(reparsed.Tags, task.Tags) {
+		t.Errorf("round trip: got %q tags %v", reparsed.String(), reparsed.Tags)
+	}
+}
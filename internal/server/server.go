@@ -0,0 +1,339 @@
+// Package server exposes a TaskService over HTTP as JSON for integrations
+// such as editor plugins and widgets.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/internal/service"
+	"github.com/wyattlefevre/wydocli/logs"
+)
+
+// TaskJSON is the wire form of a task
+type TaskJSON struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Priority       string            `json:"priority,omitempty"`
+	Projects       []string          `json:"projects"`
+	Contexts       []string          `json:"contexts"`
+	Tags           map[string]string `json:"tags"`
+	Due            string            `json:"due,omitempty"`
+	CreatedDate    string            `json:"created_date,omitempty"`
+	CompletionDate string            `json:"completion_date,omitempty"`
+	Done           bool              `json:"done"`
+	Line           string            `json:"line"`
+}
+
+func newTaskJSON(t data.Task) TaskJSON {
+	tj := TaskJSON{
+		ID:             t.ID,
+		Name:           t.Name,
+		Projects:       t.Projects,
+		Contexts:       t.Contexts,
+		Tags:           t.Tags,
+		Due:            t.GetDueDate(),
+		CreatedDate:    t.CreatedDate,
+		CompletionDate: t.CompletionDate,
+		Done:           t.Done,
+		Line:           t.String(),
+	}
+	if t.Priority != data.PriorityNone {
+		tj.Priority = string(t.Priority)
+	}
+	if tj.Projects == nil {
+		tj.Projects = []string{}
+	}
+	if tj.Contexts == nil {
+		tj.Contexts = []string{}
+	}
+	if tj.Tags == nil {
+		tj.Tags = map[string]string{}
+	}
+	return tj
+}
+
+// addRequest is the body of POST /tasks
+type addRequest struct {
+	Line string `json:"line"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server serves the task API. The service isn't safe for concurrent use, so
+// every request holds mu, and reloads the files first so edits made by the
+// CLI or TUI since the last request are seen.
+type Server struct {
+	svc         service.TaskService
+	addr        string
+	allowWrites bool
+	mu          sync.Mutex
+}
+
+// New returns a server for svc listening on addr (host:port). When
+// allowWrites is false the mutating endpoints respond 403 Forbidden.
+func New(svc service.TaskService, addr string, allowWrites bool) *Server {
+	return &Server{svc: svc, addr: addr, allowWrites: allowWrites}
+}
+
+// Handler returns the HTTP routes:
+//
+//	GET    /tasks                 ?status=pending|done|all&project=&context=
+//	POST   /tasks                 {"line": "todo.txt line"}
+//	POST   /tasks/{id}/complete
+//	DELETE /tasks/{id}
+//
+// POST requests must be sent as application/json. Requests whose Host
+// isn't the bound address, and writes from another Origin, are refused so
+// that web pages can't reach the API through the user's browser.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tasks", s.handleList)
+	mux.HandleFunc("POST /tasks", s.guardWrite(s.handleAdd))
+	mux.HandleFunc("POST /tasks/{id}/complete", s.guardWrite(s.handleComplete))
+	mux.HandleFunc("DELETE /tasks/{id}", s.guardWrite(s.handleDelete))
+	return s.checkHost(mux)
+}
+
+// IsLoopback reports whether addr (host:port) only accepts local
+// connections. An empty host listens on every interface, so it isn't.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// checkHost refuses requests addressed to a name other than the bound
+// address, which is how a DNS rebinding attack reaches a local server
+func (s *Server) checkHost(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.hostAllowed(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("unexpected Host %q", r.Host))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hostAllowed reports whether a request's Host matches the bound address.
+// Loopback names match each other, so a server on 127.0.0.1 can be reached
+// as localhost. Any host is allowed when listening on every interface.
+func (s *Server) hostAllowed(host string) bool {
+	bindHost, bindPort, err := net.SplitHostPort(s.addr)
+	if err != nil {
+		return false
+	}
+	if ip := net.ParseIP(bindHost); bindHost == "" || ip != nil && ip.IsUnspecified() {
+		return true
+	}
+	reqHost, reqPort, err := net.SplitHostPort(host)
+	if err != nil {
+		// No port, so the default one
+		reqHost, reqPort = host, "80"
+	}
+	if reqPort != bindPort {
+		return false
+	}
+	if strings.EqualFold(reqHost, bindHost) {
+		return true
+	}
+	return IsLoopback(s.addr) && IsLoopback(net.JoinHostPort(reqHost, reqPort))
+}
+
+// guardWrite refuses writes when they're disabled, when they come from a
+// page on another origin, and when a POST isn't JSON. A cross-origin page can
+// only send a plain form or text POST without the browser asking first, so
+// requiring application/json keeps it from completing or adding tasks.
+func (s *Server) guardWrite(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowWrites {
+			writeError(w, http.StatusForbidden, "writes are disabled when not bound to localhost")
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && !sameOrigin(origin, r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("cross-origin writes are not allowed (Origin %s)", origin))
+			return
+		}
+		if r.Method == http.MethodPost {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+// sameOrigin reports whether an Origin header names the server itself
+func sameOrigin(origin, host string) bool {
+	u, err := url.Parse(origin)
+	return err == nil && u.Scheme == "http" && strings.EqualFold(u.Host, host)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reload(w) {
+		return
+	}
+
+	q := r.URL.Query()
+	var tasks []data.Task
+	var err error
+	switch q.Get("status") {
+	case "", "pending":
+		tasks, err = s.svc.ListPending()
+	case "done":
+		tasks, err = s.svc.ListDone()
+	case "all":
+		tasks, err = s.svc.List()
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid status %q, use pending, done, or all", q.Get("status")))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := []TaskJSON{}
+	for _, t := range tasks {
+		if p := q.Get("project"); p != "" && !t.HasProject(p) {
+			continue
+		}
+		if c := q.Get("context"); c != "" && !t.HasContext(c) {
+			continue
+		}
+		result = append(result, newTaskJSON(t))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleAdd(w http.ResponseWriter, r *http.Request) {
+	var req addRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+	if req.Line == "" {
+		writeError(w, http.StatusBadRequest, "line is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reload(w) {
+		return
+	}
+	task, err := s.svc.Add(req.Line)
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, newTaskJSON(*task))
+}
+
+func (s *Server) handleComplete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reload(w) {
+		return
+	}
+
+	task, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if err := s.svc.Complete(task.ID); err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	// Completing moves the task to done.txt, which gives it a new ID, so
+	// report the completed copy instead of looking the old ID up again
+	if !task.Done {
+		task.Complete(data.Today(), config.Get().GetPreservePriority())
+	}
+	writeJSON(w, http.StatusOK, newTaskJSON(*task))
+}
+
+func (s *Server) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reload(w) {
+		return
+	}
+
+	task, ok := s.lookup(w, r.PathValue("id"))
+	if !ok {
+		return
+	}
+	if err := s.svc.Delete(task.ID); err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reload rereads the task files, writing a 500 if they can't be read
+func (s *Server) reload(w http.ResponseWriter) bool {
+	if err := s.svc.Reload(); err != nil {
+		writeError(w, http.StatusInternalServerError, "reloading tasks: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// lookup finds a task by ID, writing a 404 if there is none
+func (s *Server) lookup(w http.ResponseWriter, id string) (*data.Task, bool) {
+	task, err := s.svc.Get(id)
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return nil, false
+	}
+	if task == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("task not found: %s", id))
+		return nil, false
+	}
+	return task, true
+}
+
+// errorStatus returns the HTTP status for an error from the service
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAmbiguousID):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logs.Logger.Printf("Error encoding response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
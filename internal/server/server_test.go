@@ -0,0 +1,294 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/service"
+)
+
+func newTestServer(t *testing.T, todo string, allowWrites bool) (http.Handler, service.TaskService) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "todo.txt"), []byte(todo), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+	config.Reset()
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	svc, err := service.NewTaskService()
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	return New(svc, testAddr, allowWrites).Handler(), svc
+}
+
+// testAddr is the address test servers are bound to
+const testAddr = "127.0.0.1:8080"
+
+// newRequest builds a request to the test server as a local client would
+// send it: to the bound address, with POST bodies as JSON
+func newRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = testAddr
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func doRequest(t *testing.T, h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest(method, path, body))
+	return rec
+}
+
+func decodeTasks(t *testing.T, rec *httptest.ResponseRecorder) []TaskJSON {
+	t.Helper()
+	var tasks []TaskJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &tasks); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	return tasks
+}
+
+func TestList(t *testing.T) {
+	h, _ := newTestServer(t, "(A) Pay rent +home due:2024-03-01\nWrite report +work @office\nx 2024-02-01 Old task +home\n", true)
+
+	rec := doRequest(t, h, "GET", "/tasks", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	tasks := decodeTasks(t, rec)
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 pending tasks, got %d", len(tasks))
+	}
+	if tasks[0].Name != "Pay rent" || tasks[0].Priority != "A" || tasks[0].Due != "2024-03-01" {
+		t.Errorf("unexpected first task %+v", tasks[0])
+	}
+
+	tasks = decodeTasks(t, doRequest(t, h, "GET", "/tasks?project=home&status=all", ""))
+	if len(tasks) != 2 {
+		t.Errorf("expected 2 +home tasks with status=all, got %d", len(tasks))
+	}
+	tasks = decodeTasks(t, doRequest(t, h, "GET", "/tasks?context=office", ""))
+	if len(tasks) != 1 || tasks[0].Name != "Write report" {
+		t.Errorf("expected only the @office task, got %+v", tasks)
+	}
+
+	if rec := doRequest(t, h, "GET", "/tasks?status=bogus", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d for invalid status filter, want 400", rec.Code)
+	}
+}
+
+func TestList_SeesOutsideEdits(t *testing.T) {
+	h, _ := newTestServer(t, "Pay rent\n", true)
+	if tasks := decodeTasks(t, doRequest(t, h, "GET", "/tasks", "")); len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+
+	// Another wydo process adds a task behind the server's back
+	if err := os.WriteFile(filepath.Join(config.Get().TodoDir, "todo.txt"), []byte("Pay rent\nCall mom\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+	tasks := decodeTasks(t, doRequest(t, h, "GET", "/tasks", ""))
+	if len(tasks) != 2 || tasks[1].Name != "Call mom" {
+		t.Errorf("expected the outside edit to be listed, got %+v", tasks)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	h, svc := newTestServer(t, "", true)
+
+	rec := doRequest(t, h, "POST", "/tasks", `{"line": "Buy milk +home @store"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var created TaskJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if created.Name != "Buy milk" || len(created.Projects) != 1 || created.Projects[0] != "home" {
+		t.Errorf("unexpected created task %+v", created)
+	}
+
+	pending, _ := svc.ListPending()
+	if len(pending) != 1 || pending[0].Name != "Buy milk" {
+		t.Errorf("expected task to be saved, got %v", pending)
+	}
+
+	if rec := doRequest(t, h, "POST", "/tasks", `{}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d for empty line, want 400", rec.Code)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	h, svc := newTestServer(t, "Pay rent\nCall mom\n", true)
+	pending, _ := svc.ListPending()
+	id := pending[0].ID
+
+	rec := doRequest(t, h, "POST", "/tasks/"+id+"/complete", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var completed TaskJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &completed); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !completed.Done || completed.Name != "Pay rent" {
+		t.Errorf("unexpected completed task %+v", completed)
+	}
+
+	done, _ := svc.ListDone()
+	if len(done) != 1 || done[0].Name != "Pay rent" {
+		t.Errorf("expected task to be completed, got %v", done)
+	}
+
+	if rec := doRequest(t, h, "POST", "/tasks/nope/complete", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d for unknown ID, want 404", rec.Code)
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{fmt.Errorf("%w with ID: x", service.ErrTaskNotFound), http.StatusNotFound},
+		{fmt.Errorf("%w 'abcd'", service.ErrAmbiguousID), http.StatusConflict},
+		{fmt.Errorf("%w todo.txt: disk full", service.ErrWriteFailed), http.StatusInternalServerError},
+	}
+	for _, tc := range tests {
+		if got := errorStatus(tc.err); got != tc.want {
+			t.Errorf("errorStatus(%v) = %d, want %d", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWritesDisabled(t *testing.T) {
+	h, svc := newTestServer(t, "Pay rent\n", false)
+	pending, _ := svc.ListPending()
+
+	for _, r := range []struct{ method, path, body string }{
+		{"POST", "/tasks", `{"line": "Buy milk"}`},
+		{"POST", "/tasks/" + pending[0].ID + "/complete", ""},
+		{"DELETE", "/tasks/" + pending[0].ID, ""},
+	} {
+		if rec := doRequest(t, h, r.method, r.path, r.body); rec.Code != http.StatusForbidden {
+			t.Errorf("%s %s: status = %d, want 403", r.method, r.path, rec.Code)
+		}
+	}
+	if rec := doRequest(t, h, "GET", "/tasks", ""); rec.Code != http.StatusOK {
+		t.Errorf("GET /tasks: status = %d, want 200 when read-only", rec.Code)
+	}
+}
+
+func TestWritesRequireJSON(t *testing.T) {
+	h, svc := newTestServer(t, "Pay rent\n", true)
+	pending, _ := svc.ListPending()
+
+	for _, r := range []struct{ path, body string }{
+		{"/tasks", `{"line": "Buy milk"}`},
+		{"/tasks/" + pending[0].ID + "/complete", ""},
+	} {
+		req := newRequest("POST", r.path, r.body)
+		req.Header.Set("Content-Type", "text/plain")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("text/plain POST %s: status = %d, want 415", r.path, rec.Code)
+		}
+	}
+
+	req := newRequest("POST", "/tasks", `{"line": "Buy milk"}`)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("JSON with charset: status = %d, want 201", rec.Code)
+	}
+	if tasks, _ := svc.ListPending(); len(tasks) != 2 {
+		t.Errorf("expected only the JSON request to add a task, got %d tasks", len(tasks))
+	}
+}
+
+func TestWritesRejectCrossOrigin(t *testing.T) {
+	h, svc := newTestServer(t, "Pay rent\n", true)
+	pending, _ := svc.ListPending()
+
+	for _, origin := range []string{"http://evil.example", "null", "https://" + testAddr} {
+		req := newRequest("POST", "/tasks/"+pending[0].ID+"/complete", "")
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Origin %s: status = %d, want 403", origin, rec.Code)
+		}
+	}
+	if tasks, _ := svc.ListDone(); len(tasks) != 0 {
+		t.Fatalf("expected no cross-origin completion, got %d done", len(tasks))
+	}
+
+	req := newRequest("POST", "/tasks", `{"line": "Buy milk"}`)
+	req.Header.Set("Origin", "http://"+testAddr)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Errorf("same origin: status = %d, want 201", rec.Code)
+	}
+}
+
+func TestRejectsForeignHost(t *testing.T) {
+	h, _ := newTestServer(t, "Pay rent\n", true)
+
+	for host, want := range map[string]int{
+		"127.0.0.1:8080":    http.StatusOK,
+		"localhost:8080":    http.StatusOK,
+		"[::1]:8080":        http.StatusOK,
+		"evil.example":      http.StatusForbidden,
+		"evil.example:8080": http.StatusForbidden,
+		"localhost:9090":    http.StatusForbidden,
+	} {
+		req := newRequest("GET", "/tasks", "")
+		req.Host = host
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Host %s: status = %d, want %d", host, rec.Code, want)
+		}
+	}
+}
+
+func TestHostAllowed_AnyInterface(t *testing.T) {
+	s := New(nil, ":8080", false)
+	if !s.hostAllowed("wydo.lan:8080") {
+		t.Error("expected any Host when listening on every interface")
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
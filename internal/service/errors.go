@@ -0,0 +1,11 @@
+package service
+
+import "github.com/wyattlefevre/wydocli/internal/data"
+
+// Errors the service returns, wrapped with context, for callers to check
+// with errors.Is. They're the data layer's, so errors from either match.
+var (
+	ErrTaskNotFound = data.ErrTaskNotFound
+	ErrAmbiguousID  = data.ErrAmbiguousID
+	ErrWriteFailed  = data.ErrWriteFailed
+)
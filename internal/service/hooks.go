@@ -0,0 +1,59 @@
+package service
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+	"github.com/wyattlefevre/wydocli/logs"
+)
+
+// Task events a hook command can be configured for
+const (
+	hookAdd      = "add"
+	hookComplete = "complete"
+	hookDelete   = "delete"
+)
+
+// startHook starts a hook command without waiting for it to finish. It is a
+// variable so tests can record hooks instead of running them.
+var startHook = func(name string, args []string, env []string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() // reap it in the background
+	return nil
+}
+
+// hookCommand returns the configured command for event ("" for none)
+func hookCommand(event string) string {
+	switch event {
+	case hookAdd:
+		return config.Get().GetOnAdd()
+	case hookComplete:
+		return config.Get().GetOnComplete()
+	case hookDelete:
+		return config.Get().GetOnDelete()
+	}
+	return ""
+}
+
+// runHook starts the command configured for event, if any, with the task's
+// ID and line appended to its arguments and set in its environment. A hook
+// that fails to start is logged; it never fails the change that fired it.
+func runHook(event string, task data.Task) {
+	fields := strings.Fields(hookCommand(event))
+	if len(fields) == 0 {
+		return
+	}
+	line := task.String()
+	args := append(fields[1:], task.ID, line)
+	env := []string{"WYDO_EVENT=" + event, "WYDO_TASK_ID=" + task.ID, "WYDO_TASK_LINE=" + line}
+	if err := startHook(fields[0], args, env); err != nil {
+		logs.Logger.Printf("%s hook %q failed: %v", event, fields[0], err)
+	}
+}
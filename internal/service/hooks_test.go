@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+)
+
+type hookCall struct {
+	name string
+	args []string
+	env  []string
+}
+
+// recordHooks replaces startHook with one that records its calls
+func recordHooks(t *testing.T) *[]hookCall {
+	t.Helper()
+	var calls []hookCall
+	orig := startHook
+	t.Cleanup(func() { startHook = orig })
+	startHook = func(name string, args []string, env []string) error {
+		calls = append(calls, hookCall{name, args, env})
+		return nil
+	}
+	return &calls
+}
+
+func TestHooks_OnComplete(t *testing.T) {
+	svc := newTestService(t)
+	calls := recordHooks(t)
+	config.Get().OnComplete = "sync-task --quiet"
+
+	task, err := svc.Add("Pay rent +home")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Fatalf("expected no hook without on_add, got %v", *calls)
+	}
+	if err := svc.Complete(task.ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if len(*calls) != 1 {
+		t.Fatalf("expected 1 hook call, got %d: %v", len(*calls), *calls)
+	}
+	done, _ := svc.ListDone()
+	call := (*calls)[0]
+	if len(done) != 1 || call.name != "sync-task" || !slices.Equal(call.args, []string{"--quiet", task.ID, done[0].String()}) {
+		t.Errorf("hook = %s %q, want sync-task --quiet <id> <line>", call.name, call.args)
+	}
+	if !slices.Contains(call.env, "WYDO_TASK_ID="+task.ID) || !slices.Contains(call.env, "WYDO_EVENT=complete") {
+		t.Errorf("hook env = %q, want the event and task ID", call.env)
+	}
+
+	// Completing again changes nothing, so no hook
+	if err := svc.Complete(done[0].ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(*calls) != 1 {
+		t.Errorf("expected no hook for an already done task, got %d calls", len(*calls))
+	}
+}
+
+func TestHooks_UpdateManyAndDelete(t *testing.T) {
+	svc := newTestService(t)
+	calls := recordHooks(t)
+	config.Get().OnAdd = "on-add"
+	config.Get().OnComplete = "on-complete"
+	config.Get().OnDelete = "on-delete"
+
+	task, err := svc.Add("Water plants")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	done := *task
+	done.Complete("2024-03-01", false)
+	if err := svc.Update(done); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := svc.Delete(task.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	var names []string
+	for _, c := range *calls {
+		names = append(names, c.name)
+	}
+	if want := []string{"on-add", "on-complete", "on-delete"}; !slices.Equal(names, want) {
+		t.Errorf("hooks = %v, want %v", names, want)
+	}
+}
+
+func TestHooks_FailureIsNotFatal(t *testing.T) {
+	svc := newTestService(t)
+	orig := startHook
+	t.Cleanup(func() { startHook = orig })
+	startHook = func(string, []string, []string) error { return errors.New("not found") }
+	config.Get().OnAdd = "missing-command"
+
+	if _, err := svc.Add("Still added"); err != nil {
+		t.Errorf("Add with a failing hook: %v, want success", err)
+	}
+}
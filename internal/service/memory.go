@@ -0,0 +1,301 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+// The files in-memory tasks are filed under. Nothing is read from or
+// written to them.
+const (
+	memoryTodoFile = "todo.txt"
+	memoryDoneFile = "done.txt"
+)
+
+// memoryTaskService is a TaskService backed by a slice instead of todo.txt
+// and done.txt
+type memoryTaskService struct {
+	tasks  []data.Task
+	nextID int
+}
+
+// NewInMemoryTaskService creates a TaskService that keeps tasks in memory,
+// for tests and embedding. It never touches disk: tasks are filed under
+// "todo.txt" and "done.txt" in name only. Tasks in initial without an ID or
+// file are given one. Config-driven behavior (created dates, project
+// defaults, allowed tags, preserved priorities, purging done.txt) and hooks
+// don't apply.
+func NewInMemoryTaskService(initial []data.Task) TaskService {
+	s := &memoryTaskService{}
+	for _, t := range initial {
+		if t.ID == "" {
+			t.ID = s.newID()
+		}
+		if t.File == "" {
+			t.File = memoryTodoFile
+			if t.Done {
+				t.File = memoryDoneFile
+			}
+		}
+		t.Tags = maps.Clone(t.Tags)
+		if t.Tags == nil {
+			t.Tags = make(map[string]string)
+		}
+		s.tasks = append(s.tasks, t)
+	}
+	return s
+}
+
+// newID returns an ID no other in-memory task has
+func (s *memoryTaskService) newID() string {
+	s.nextID++
+	return data.HashTaskLine(fmt.Sprintf("%d:memory", s.nextID))
+}
+
+// filter returns the tasks keep accepts
+func (s *memoryTaskService) filter(keep func(data.Task) bool) []data.Task {
+	var filtered []data.Task
+	for _, t := range s.tasks {
+		if keep(t) {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+func (s *memoryTaskService) List() ([]data.Task, error) {
+	return slices.Clone(s.tasks), nil
+}
+
+func (s *memoryTaskService) ListByProject(project string) ([]data.Task, error) {
+	return s.filter(func(t data.Task) bool { return t.HasProject(project) }), nil
+}
+
+func (s *memoryTaskService) ListByContext(context string) ([]data.Task, error) {
+	return s.filter(func(t data.Task) bool { return t.HasContext(context) }), nil
+}
+
+func (s *memoryTaskService) ListPending() ([]data.Task, error) {
+	return s.filter(func(t data.Task) bool { return !t.Done }), nil
+}
+
+func (s *memoryTaskService) ListDone() ([]data.Task, error) {
+	return s.filter(func(t data.Task) bool { return t.Done }), nil
+}
+
+func (s *memoryTaskService) ListDonePaged(offset, limit int) ([]data.Task, error) {
+	if offset < 0 || limit < 0 {
+		return nil, fmt.Errorf("invalid page: offset %d, limit %d", offset, limit)
+	}
+	archived := s.filter(func(t data.Task) bool { return t.File == memoryDoneFile })
+	if offset >= len(archived) {
+		return []data.Task{}, nil
+	}
+	return archived[offset:min(offset+limit, len(archived))], nil
+}
+
+func (s *memoryTaskService) ListDueBetween(start, end time.Time) ([]data.Task, error) {
+	return dueBetween(s.tasks, start, end), nil
+}
+
+func (s *memoryTaskService) Stats() (ServiceStats, error) {
+	return ComputeStats(s.tasks, data.Today()), nil
+}
+
+func (s *memoryTaskService) Get(id string) (*data.Task, error) {
+	for _, t := range s.tasks {
+		if t.ID == id {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("%w with ID: %s", ErrTaskNotFound, id)
+}
+
+// parse turns a raw line into a new pending-file task with a fresh ID
+func (s *memoryTaskService) parse(rawLine string) (data.Task, error) {
+	rawLine = strings.TrimSpace(rawLine)
+	if rawLine == "" {
+		return data.Task{}, fmt.Errorf("empty task line")
+	}
+	return data.ParseTask(rawLine, s.newID(), memoryTodoFile), nil
+}
+
+func (s *memoryTaskService) Add(rawLine string) (*data.Task, error) {
+	task, err := s.parse(rawLine)
+	if err != nil {
+		return nil, err
+	}
+	s.tasks = append(s.tasks, task)
+	return &task, nil
+}
+
+func (s *memoryTaskService) Insert(rawLine string, index int) (*data.Task, error) {
+	if index < 0 {
+		return nil, fmt.Errorf("invalid index %d", index)
+	}
+	task, err := s.parse(rawLine)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the slice position of the index-th task in todo.txt
+	pos := len(s.tasks)
+	seen := 0
+	for i, t := range s.tasks {
+		if t.File != memoryTodoFile {
+			continue
+		}
+		if seen == index {
+			pos = i
+			break
+		}
+		seen++
+	}
+	s.tasks = slices.Insert(s.tasks, pos, task)
+	return &task, nil
+}
+
+func (s *memoryTaskService) Capture(rawLine string) error {
+	_, err := s.Add(rawLine)
+	return err
+}
+
+func (s *memoryTaskService) Update(task data.Task) error {
+	return s.UpdateMany([]data.Task{task})
+}
+
+func (s *memoryTaskService) UpdateMany(tasks []data.Task) error {
+	return s.UpdateAndDelete(tasks, nil)
+}
+
+func (s *memoryTaskService) UpdateAndDelete(tasks []data.Task, deleted []string) error {
+	for _, task := range tasks {
+		task.Tags = maps.Clone(task.Tags)
+		s.tasks = data.UpdateTask(s.tasks, task)
+	}
+	for _, id := range deleted {
+		s.tasks = data.DeleteTask(s.tasks, id)
+	}
+	return nil
+}
+
+func (s *memoryTaskService) Complete(id string) error {
+	task, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	if !task.Done {
+		task.Complete(data.Today(), false)
+	}
+	task.File = memoryDoneFile
+	s.tasks = data.UpdateTask(s.tasks, *task)
+	return nil
+}
+
+func (s *memoryTaskService) Uncomplete(id string) error {
+	task, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+	task.Uncomplete()
+	task.File = memoryTodoFile
+	s.tasks = data.UpdateTask(s.tasks, *task)
+	return nil
+}
+
+func (s *memoryTaskService) Split(id string, sep string, completeOriginal bool) ([]data.Task, error) {
+	task, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	today := data.Today()
+	parts := task.Split(sep, today)
+	if parts == nil {
+		return nil, fmt.Errorf("task name has no %q-separated parts to split", sep)
+	}
+
+	if completeOriginal {
+		if !task.Done {
+			task.Complete(today, false)
+		}
+		s.tasks = data.UpdateTask(s.tasks, *task)
+	} else {
+		s.tasks = data.DeleteTask(s.tasks, id)
+	}
+	for i := range parts {
+		parts[i].ID = s.newID()
+		parts[i].File = memoryTodoFile
+		s.tasks = append(s.tasks, parts[i])
+	}
+	return parts, nil
+}
+
+func (s *memoryTaskService) Delete(id string) error {
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+	s.tasks = data.DeleteTask(s.tasks, id)
+	return nil
+}
+
+func (s *memoryTaskService) Archive() error {
+	return s.CleanProject("", false)
+}
+
+func (s *memoryTaskService) CleanProject(project string, purge bool) error {
+	inProject := func(t data.Task) bool { return project == "" || t.HasProject(project) }
+	if !purge {
+		for i, t := range s.tasks {
+			if t.Done && inProject(t) {
+				s.tasks[i].File = memoryDoneFile
+			}
+		}
+		return nil
+	}
+	s.tasks = s.filter(func(t data.Task) bool {
+		return !t.Done || t.File == memoryDoneFile || !inProject(t)
+	})
+	return nil
+}
+
+func (s *memoryTaskService) PurgeDone() error {
+	return s.CleanProject("", true)
+}
+
+func (s *memoryTaskService) RenameTagKey(oldKey, newKey string) error {
+	if !tagKeyPattern.MatchString(oldKey) || !tagKeyPattern.MatchString(newKey) {
+		return fmt.Errorf("invalid tag key: keys are letters and digits only")
+	}
+	for i := range s.tasks {
+		s.tasks[i].Tags = maps.Clone(s.tasks[i].Tags)
+		s.tasks[i].RenameTag(oldKey, newKey)
+	}
+	return nil
+}
+
+// GetProjects returns a project (without a note) for each project the tasks
+// name
+func (s *memoryTaskService) GetProjects() map[string]data.Project {
+	projects := make(map[string]data.Project)
+	for _, t := range s.tasks {
+		for _, p := range t.Projects {
+			projects[p] = data.Project{Name: p}
+		}
+	}
+	return projects
+}
+
+func (s *memoryTaskService) ProjectsSorted() []data.Project {
+	return data.SortProjects(s.GetProjects())
+}
+
+// Reload does nothing: there's nothing on disk to reload from
+func (s *memoryTaskService) Reload() error {
+	return nil
+}
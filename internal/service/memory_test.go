@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+func TestInMemoryTaskService(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC) }
+
+	svc := NewInMemoryTaskService([]data.Task{
+		data.ParseTask("Pay rent +home", "", ""),
+		data.ParseTask("x 2024-03-01 Filed taxes", "", ""),
+	})
+
+	added, err := svc.Add("Write report +work due:2024-03-20")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if added.ID == "" {
+		t.Error("expected the added task to get an ID")
+	}
+	if _, err := svc.Insert("Call bank", 0); err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	pending, _ := svc.ListPending()
+	if got := taskNames(pending); !reflect.DeepEqual(got, []string{"Call bank", "Pay rent", "Write report"}) {
+		t.Errorf("pending = %v", got)
+	}
+	work, _ := svc.ListByProject("work")
+	if len(work) != 1 || work[0].ID != added.ID {
+		t.Errorf("ListByProject(work) = %v, want the added task", taskNames(work))
+	}
+
+	if err := svc.Complete(added.ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	task, _ := svc.Get(added.ID)
+	if !task.Done || task.CompletionDate != "2024-03-15" {
+		t.Errorf("expected the task done on 2024-03-15, got %+v", task)
+	}
+	done, _ := svc.ListDone()
+	if len(done) != 2 {
+		t.Errorf("expected 2 done tasks, got %v", taskNames(done))
+	}
+
+	if err := svc.Delete(added.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := svc.Get(added.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrTaskNotFound", err)
+	}
+	if err := svc.Delete(added.ID); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrTaskNotFound", err)
+	}
+
+	all, _ := svc.List()
+	if got := taskNames(all); !reflect.DeepEqual(got, []string{"Call bank", "Pay rent", "Filed taxes"}) {
+		t.Errorf("all = %v", got)
+	}
+}
+
+func TestInMemoryTaskService_ArchiveAndPurge(t *testing.T) {
+	svc := NewInMemoryTaskService([]data.Task{
+		data.ParseTask("x Mow lawn +home", "", ""),
+		data.ParseTask("x Pay rent +home", "", "todo.txt"),
+		data.ParseTask("Call bank", "", ""),
+	})
+	// A done task in initial is filed in done.txt unless given a file
+	pending, _ := svc.ListPending()
+	if err := svc.Complete(pending[0].ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if err := svc.PurgeDone(); err != nil {
+		t.Fatalf("PurgeDone: %v", err)
+	}
+	all, _ := svc.List()
+	if got := taskNames(all); !reflect.DeepEqual(got, []string{"Mow lawn", "Call bank"}) {
+		t.Errorf("after purge = %v, want only the archived tasks", got)
+	}
+	archived, _ := svc.ListDonePaged(0, 10)
+	if len(archived) != 2 {
+		t.Errorf("ListDonePaged = %v, want the archived tasks", taskNames(archived))
+	}
+}
+
+func taskNames(tasks []data.Task) []string {
+	var names []string
+	for _, t := range tasks {
+		names = append(names, t.Name)
+	}
+	return names
+}
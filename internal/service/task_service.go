@@ -2,8 +2,14 @@ package service
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/wyattlefevre/wydocli/internal/config"
 	"github.com/wyattlefevre/wydocli/internal/data"
 	"github.com/wyattlefevre/wydocli/logs"
 )
@@ -26,31 +32,136 @@ type TaskService interface {
 	// ListDone returns only completed tasks
 	ListDone() ([]data.Task, error)
 
+	// ListDonePaged returns up to limit archived tasks from done.txt starting
+	// at offset, reading the file directly so large archives don't need to
+	// be loaded in full
+	ListDonePaged(offset, limit int) ([]data.Task, error)
+
+	// ListDueBetween returns pending tasks due within [start, end] (compared
+	// by calendar date), sorted by due date. Tasks without a valid due date
+	// are excluded.
+	ListDueBetween(start, end time.Time) ([]data.Task, error)
+
+	// Stats returns task counts for reports and integrations
+	Stats() (ServiceStats, error)
+
 	// Get returns a single task by ID
 	Get(id string) (*data.Task, error)
 
 	// Add creates a new task from a raw todo.txt line
 	Add(rawLine string) (*data.Task, error)
 
+	// Insert creates a new task from a raw todo.txt line at position index
+	// in todo.txt (0 is the top), keeping the other tasks' order
+	Insert(rawLine string, index int) (*data.Task, error)
+
+	// Capture appends a raw line to todo.txt verbatim, skipping the parsing
+	// and normalizing that Add does
+	Capture(rawLine string) error
+
 	// Update modifies an existing task
 	Update(task data.Task) error
 
-	// Complete marks a task as done
+	// UpdateMany modifies (or adds) several tasks with a single write
+	UpdateMany(tasks []data.Task) error
+
+	// UpdateAndDelete modifies (or adds) tasks and deletes the tasks with
+	// the deleted IDs, with a single write. Unknown IDs are ignored.
+	UpdateAndDelete(tasks []data.Task, deleted []string) error
+
+	// Complete marks a task as done and moves it to done.txt with a single
+	// write. A task that's already done but still in todo.txt is archived.
 	Complete(id string) error
 
+	// Uncomplete marks a done task as pending and moves it back to todo.txt
+	Uncomplete(id string) error
+
+	// Split replaces a task with one new task per sep-separated part of its
+	// name, completing the original instead of deleting it when
+	// completeOriginal is set. Returns the new tasks.
+	Split(id string, sep string, completeOriginal bool) ([]data.Task, error)
+
 	// Delete removes a task by ID
 	Delete(id string) error
 
 	// Archive moves all completed tasks to done.txt
 	Archive() error
 
+	// CleanProject archives the completed tasks in project, or deletes them
+	// when purge is set (including done.txt when purge_done_file is set).
+	// Other projects' tasks are left alone.
+	CleanProject(project string, purge bool) error
+
+	// PurgeDone deletes completed tasks from todo.txt without archiving them,
+	// and clears done.txt too when purge_done_file is set
+	PurgeDone() error
+
+	// RenameTagKey renames tag key oldKey to newKey on every task (pending
+	// and done) with a single write. A task that already has newKey keeps
+	// that value and drops oldKey.
+	RenameTagKey(oldKey, newKey string) error
+
 	// GetProjects returns the project map
 	GetProjects() map[string]data.Project
 
+	// ProjectsSorted returns the projects sorted by name
+	ProjectsSorted() []data.Project
+
 	// Reload refreshes the in-memory data from disk
 	Reload() error
 }
 
+// ServiceStats summarizes the tasks
+type ServiceStats struct {
+	Total   int
+	Pending int
+	Done    int
+	// Overdue counts pending tasks whose due date is before today (data.Now)
+	Overdue int
+	// ByPriority counts pending tasks per priority (data.PriorityNone for
+	// none); done tasks usually have theirs stripped on completion
+	ByPriority map[data.Priority]int
+	// ByProject counts each project's tasks; a task in several projects
+	// counts toward each of them
+	ByProject map[string]StatusCounts
+}
+
+// StatusCounts splits a count into pending and done tasks
+type StatusCounts struct {
+	Pending int
+	Done    int
+}
+
+// ComputeStats summarizes tasks, using today (yyyy-MM-dd) to find overdue ones
+func ComputeStats(tasks []data.Task, today string) ServiceStats {
+	stats := ServiceStats{
+		ByPriority: make(map[data.Priority]int),
+		ByProject:  make(map[string]StatusCounts),
+	}
+	for _, t := range tasks {
+		stats.Total++
+		if t.Done {
+			stats.Done++
+		} else {
+			stats.Pending++
+			stats.ByPriority[t.Priority]++
+			if due := t.ValidDueDate(); due != "" && due < today {
+				stats.Overdue++
+			}
+		}
+		for _, p := range t.Projects {
+			counts := stats.ByProject[p]
+			if t.Done {
+				counts.Done++
+			} else {
+				counts.Pending++
+			}
+			stats.ByProject[p] = counts
+		}
+	}
+	return stats
+}
+
 // taskServiceImpl is the concrete implementation of TaskService
 type taskServiceImpl struct {
 	tasks    []data.Task
@@ -120,16 +231,57 @@ func (s *taskServiceImpl) ListDone() ([]data.Task, error) {
 	return done, nil
 }
 
+func (s *taskServiceImpl) ListDonePaged(offset, limit int) ([]data.Task, error) {
+	return data.LoadDonePage(offset, limit)
+}
+
+func (s *taskServiceImpl) ListDueBetween(start, end time.Time) ([]data.Task, error) {
+	return dueBetween(s.tasks, start, end), nil
+}
+
+// dueBetween returns the pending tasks due within [start, end], sorted by
+// due date (see ListDueBetween)
+func dueBetween(tasks []data.Task, start, end time.Time) []data.Task {
+	from := start.Format(data.DateFormat)
+	to := end.Format(data.DateFormat)
+
+	var due []data.Task
+	for _, t := range tasks {
+		if t.Done {
+			continue
+		}
+		d := t.ValidDueDate()
+		if d != "" && d >= from && d <= to {
+			due = append(due, t)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].ValidDueDate() < due[j].ValidDueDate()
+	})
+	return due
+}
+
+func (s *taskServiceImpl) Stats() (ServiceStats, error) {
+	return ComputeStats(s.tasks, data.Today()), nil
+}
+
 func (s *taskServiceImpl) Get(id string) (*data.Task, error) {
 	for _, t := range s.tasks {
 		if t.ID == id {
 			return &t, nil
 		}
 	}
-	return nil, fmt.Errorf("task not found: %s", id)
+	return nil, fmt.Errorf("%w with ID: %s", ErrTaskNotFound, id)
 }
 
 func (s *taskServiceImpl) Add(rawLine string) (*data.Task, error) {
+	if err := checkAllowedTags(data.ParseTask(rawLine, "", "")); err != nil {
+		return nil, err
+	}
+	rawLine = applyProjectDefaults(rawLine)
+	if config.Get().GetAddCreatedDate() {
+		rawLine = data.StampCreatedDate(strings.TrimSpace(rawLine), data.Today())
+	}
 	task, err := data.AppendTask(rawLine)
 	if err != nil {
 		return nil, err
@@ -138,16 +290,138 @@ func (s *taskServiceImpl) Add(rawLine string) (*data.Task, error) {
 	if err := s.Reload(); err != nil {
 		return nil, err
 	}
+	runHook(hookAdd, *task)
 	return task, nil
 }
 
+func (s *taskServiceImpl) Insert(rawLine string, index int) (*data.Task, error) {
+	if err := checkAllowedTags(data.ParseTask(rawLine, "", "")); err != nil {
+		return nil, err
+	}
+	rawLine = applyProjectDefaults(rawLine)
+	if config.Get().GetAddCreatedDate() {
+		rawLine = data.StampCreatedDate(strings.TrimSpace(rawLine), data.Today())
+	}
+	task, err := data.InsertTask(rawLine, index)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	runHook(hookAdd, *task)
+	return task, nil
+}
+
+// applyProjectDefaults gives a new task the context and priority configured
+// in project_defaults for its projects, the first project with one winning.
+// A task that already has a context or priority keeps it.
+func applyProjectDefaults(rawLine string) string {
+	defaults := config.Get().GetProjectDefaults()
+	if len(defaults) == 0 {
+		return rawLine
+	}
+	task := data.ParseTask(rawLine, "", "")
+	if task.Done {
+		return rawLine
+	}
+
+	changed := false
+	for _, project := range task.Projects {
+		d, ok := defaults[project]
+		if !ok {
+			continue
+		}
+		if len(task.Contexts) == 0 && d.Context != "" {
+			task.AddContext(strings.TrimPrefix(d.Context, "@"))
+			changed = true
+		}
+		if task.Priority == data.PriorityNone && d.Priority != "" {
+			task.Priority = data.Priority(d.Priority[0])
+			changed = true
+		}
+	}
+	if !changed {
+		return rawLine
+	}
+	return task.String()
+}
+
+// builtinTags are the tag keys wydo writes itself, allowed even in strict mode
+var builtinTags = []string{"due", "t", "pri", "rec"}
+
+// checkAllowedTags rejects a task using a tag key outside allowed_tags.
+// Without allowed_tags every tag is accepted.
+func checkAllowedTags(task data.Task) error {
+	allowed := config.Get().GetAllowedTags()
+	if len(allowed) == 0 {
+		return nil
+	}
+	keys := slices.Sorted(maps.Keys(task.Tags))
+	for _, key := range keys {
+		if !slices.Contains(allowed, key) && !slices.Contains(builtinTags, key) {
+			return fmt.Errorf("tag %q is not in allowed_tags", key)
+		}
+	}
+	return nil
+}
+
+func (s *taskServiceImpl) Capture(rawLine string) error {
+	if config.Get().GetAddCreatedDate() {
+		rawLine = data.StampCreatedDate(strings.TrimSpace(rawLine), data.Today())
+	}
+	if err := data.CaptureLine(rawLine); err != nil {
+		return err
+	}
+	return s.Reload()
+}
+
 func (s *taskServiceImpl) Update(task data.Task) error {
 	logs.Logger.Printf("Service: Update Task: %s\n", task.ID)
-	data.UpdateTask(s.tasks, task)
+	return s.UpdateMany([]data.Task{task})
+}
+
+func (s *taskServiceImpl) UpdateMany(tasks []data.Task) error {
+	return s.UpdateAndDelete(tasks, nil)
+}
+
+func (s *taskServiceImpl) UpdateAndDelete(tasks []data.Task, deleted []string) error {
+	logs.Logger.Printf("Service: Update %d Task(s), Delete %d\n", len(tasks), len(deleted))
+	for _, task := range tasks {
+		if err := checkAllowedTags(task); err != nil {
+			return err
+		}
+	}
+	// Tasks going from pending to done fire the complete hook
+	var completed []data.Task
+	for _, task := range tasks {
+		if old, err := s.Get(task.ID); err == nil && !old.Done && task.Done {
+			completed = append(completed, task)
+		}
+	}
+	for _, task := range tasks {
+		s.tasks = data.UpdateTask(s.tasks, task)
+	}
+	var removed []data.Task
+	for _, id := range deleted {
+		if old, err := s.Get(id); err == nil {
+			removed = append(removed, *old)
+			s.tasks = data.DeleteTask(s.tasks, id)
+		}
+	}
 	if err := data.WriteData(s.tasks); err != nil {
 		return err
 	}
-	return s.Reload()
+	if err := s.Reload(); err != nil {
+		return err
+	}
+	for _, task := range completed {
+		runHook(hookComplete, task)
+	}
+	for _, task := range removed {
+		runHook(hookDelete, task)
+	}
+	return nil
 }
 
 func (s *taskServiceImpl) Complete(id string) error {
@@ -156,10 +430,34 @@ func (s *taskServiceImpl) Complete(id string) error {
 		return err
 	}
 
-	task.Done = true
-	task.CompletionDate = time.Now().Format("2006-01-02")
+	wasDone := task.Done
+	if !task.Done {
+		task.Complete(data.Today(), config.Get().GetPreservePriority())
+	}
 	task.File = data.GetDoneFilePath()
 
+	data.UpdateTask(s.tasks, *task)
+	if err := data.WriteData(s.tasks); err != nil {
+		return err
+	}
+	if err := s.Reload(); err != nil {
+		return err
+	}
+	if !wasDone {
+		runHook(hookComplete, *task)
+	}
+	return nil
+}
+
+func (s *taskServiceImpl) Uncomplete(id string) error {
+	task, err := s.Get(id)
+	if err != nil {
+		return err
+	}
+
+	task.Uncomplete()
+	task.File = data.GetTodoFilePath()
+
 	data.UpdateTask(s.tasks, *task)
 	if err := data.WriteData(s.tasks); err != nil {
 		return err
@@ -167,12 +465,51 @@ func (s *taskServiceImpl) Complete(id string) error {
 	return s.Reload()
 }
 
+func (s *taskServiceImpl) Split(id string, sep string, completeOriginal bool) ([]data.Task, error) {
+	task, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	today := data.Today()
+	parts := task.Split(sep, today)
+	if parts == nil {
+		return nil, fmt.Errorf("task name has no %q-separated parts to split", sep)
+	}
+
+	if completeOriginal {
+		if !task.Done {
+			task.Complete(today, config.Get().GetPreservePriority())
+		}
+		s.tasks = data.UpdateTask(s.tasks, *task)
+	} else {
+		s.tasks = data.DeleteTask(s.tasks, id)
+	}
+	for i := range parts {
+		parts[i].File = data.GetTodoFilePath()
+		s.tasks = append(s.tasks, parts[i])
+	}
+
+	if err := data.WriteData(s.tasks); err != nil {
+		return nil, err
+	}
+	return parts, s.Reload()
+}
+
 func (s *taskServiceImpl) Delete(id string) error {
+	deleted, err := s.Get(id)
+	if err != nil {
+		return err
+	}
 	s.tasks = data.DeleteTask(s.tasks, id)
 	if err := data.WriteData(s.tasks); err != nil {
 		return err
 	}
-	return s.Reload()
+	if err := s.Reload(); err != nil {
+		return err
+	}
+	runHook(hookDelete, *deleted)
+	return nil
 }
 
 func (s *taskServiceImpl) Archive() error {
@@ -182,6 +519,53 @@ func (s *taskServiceImpl) Archive() error {
 	return s.Reload()
 }
 
+func (s *taskServiceImpl) PurgeDone() error {
+	s.tasks = data.PurgeDone(s.tasks, config.Get().GetPurgeDoneFile())
+	if err := data.WriteData(s.tasks); err != nil {
+		return err
+	}
+	return s.Reload()
+}
+
+func (s *taskServiceImpl) CleanProject(project string, purge bool) error {
+	if !purge {
+		if err := data.ArchiveDoneInProject(s.tasks, project); err != nil {
+			return err
+		}
+		return s.Reload()
+	}
+	s.tasks = data.PurgeDoneInProject(s.tasks, project, config.Get().GetPurgeDoneFile())
+	if err := data.WriteData(s.tasks); err != nil {
+		return err
+	}
+	return s.Reload()
+}
+
+// tagKeyPattern matches the keys ParseTags accepts
+var tagKeyPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+func (s *taskServiceImpl) RenameTagKey(oldKey, newKey string) error {
+	if !tagKeyPattern.MatchString(oldKey) || !tagKeyPattern.MatchString(newKey) {
+		return fmt.Errorf("invalid tag key: keys are letters and digits only")
+	}
+	var changed []data.Task
+	for _, t := range s.tasks {
+		// Rename on a copy so s.tasks only changes through UpdateMany
+		t.Tags = maps.Clone(t.Tags)
+		if t.RenameTag(oldKey, newKey) {
+			changed = append(changed, t)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return s.UpdateMany(changed)
+}
+
 func (s *taskServiceImpl) GetProjects() map[string]data.Project {
 	return s.projects
 }
+
+func (s *taskServiceImpl) ProjectsSorted() []data.Project {
+	return data.SortProjects(s.projects)
+}
@@ -0,0 +1,566 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+func newTestService(t *testing.T) TaskService {
+	t.Helper()
+
+	config.Reset()
+	config.SetCLIFlags(config.CLIFlags{TodoDir: t.TempDir()})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	svc, err := NewTaskService()
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	return svc
+}
+
+func TestComplete_UsesClock(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC) }
+
+	svc := newTestService(t)
+	task, err := svc.Add("Pay rent")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := svc.Complete(task.ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	done, _ := svc.ListDone()
+	if len(done) != 1 {
+		t.Fatalf("Expected 1 done task, got %d", len(done))
+	}
+	if done[0].CompletionDate != "2024-03-15" {
+		t.Errorf("CompletionDate = %q, want %q", done[0].CompletionDate, "2024-03-15")
+	}
+}
+
+func TestListDueBetween(t *testing.T) {
+	svc := newTestService(t)
+	for _, line := range []string{
+		"Late due:2024-03-11",
+		"End due:2024-03-10",
+		"Start due:2024-03-01",
+		"Before due:2024-02-29",
+		"No due date",
+		"Bad due:soon",
+		"x 2024-03-02 Done due:2024-03-05",
+		"Middle due:2024-03-05",
+	} {
+		if _, err := svc.Add(line); err != nil {
+			t.Fatalf("Add(%q): %v", line, err)
+		}
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	tasks, err := svc.ListDueBetween(start, end)
+	if err != nil {
+		t.Fatalf("ListDueBetween: %v", err)
+	}
+
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	want := []string{"Start", "Middle", "End"}
+	if len(names) != len(want) {
+		t.Fatalf("got %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("got %v, want %v", names, want)
+			break
+		}
+	}
+}
+
+func TestLoad_ReconcilesDoneWithFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "todo.txt"), []byte("x 2024-03-01 Filed taxes\nPay rent\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "done.txt"), []byte("Renewed passport\n"), 0644); err != nil {
+		t.Fatalf("Failed to write done.txt: %v", err)
+	}
+	config.Reset()
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	svc, err := NewTaskService()
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	tasks, _ := svc.List()
+	want := map[string]bool{
+		"Filed taxes":      true, // x-prefixed in todo.txt stays done
+		"Pay rent":         false,
+		"Renewed passport": true, // done.txt without "x" is treated as done
+	}
+	for _, task := range tasks {
+		if task.Done != want[task.Name] {
+			t.Errorf("%q: Done = %v, want %v", task.Name, task.Done, want[task.Name])
+		}
+	}
+	if len(tasks) != len(want) {
+		t.Errorf("expected %d tasks, got %d", len(want), len(tasks))
+	}
+
+	if got := data.StrayDoneWarning(tasks); !strings.HasPrefix(got, "1 completed task(s) in todo.txt") {
+		t.Errorf("StrayDoneWarning = %q, want it to count Filed taxes", got)
+	}
+
+	pending, _ := svc.ListPending()
+	if len(pending) != 1 || pending[0].Name != "Pay rent" {
+		t.Errorf("expected only %q pending, got %v", "Pay rent", pending)
+	}
+}
+
+// newServiceWithDone creates a service whose done.txt holds n archived tasks
+func newServiceWithDone(tb testing.TB, n int) TaskService {
+	tb.Helper()
+
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "x 2024-01-01 Archived task %d +archive\n", i)
+		if i%10 == 0 {
+			b.WriteString("\n") // blank lines must not shift the window
+		}
+	}
+	dir := tb.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "done.txt"), []byte(b.String()), 0644); err != nil {
+		tb.Fatalf("Failed to write done.txt: %v", err)
+	}
+	config.Reset()
+	config.SetCLIFlags(config.CLIFlags{TodoDir: dir})
+	if _, err := config.Load(); err != nil {
+		tb.Fatalf("Failed to load config: %v", err)
+	}
+	svc, err := NewTaskService()
+	if err != nil {
+		tb.Fatalf("Failed to create service: %v", err)
+	}
+	return svc
+}
+
+func TestListDonePaged_MatchesFullLoad(t *testing.T) {
+	svc := newServiceWithDone(t, 95)
+	full, _ := svc.ListDone()
+
+	for _, tc := range []struct{ offset, limit int }{
+		{0, 10}, {10, 10}, {90, 10}, {95, 10}, {200, 10}, {3, 0},
+	} {
+		page, err := svc.ListDonePaged(tc.offset, tc.limit)
+		if err != nil {
+			t.Fatalf("ListDonePaged(%d, %d): %v", tc.offset, tc.limit, err)
+		}
+		start := min(tc.offset, len(full))
+		want := full[start:min(start+tc.limit, len(full))]
+		if len(page) != len(want) {
+			t.Fatalf("ListDonePaged(%d, %d): got %d tasks, want %d", tc.offset, tc.limit, len(page), len(want))
+		}
+		for i := range want {
+			if page[i].ID != want[i].ID || page[i].String() != want[i].String() {
+				t.Errorf("ListDonePaged(%d, %d)[%d] = %q (%s), want %q (%s)",
+					tc.offset, tc.limit, i, page[i].String(), page[i].ID, want[i].String(), want[i].ID)
+			}
+		}
+	}
+
+	if _, err := svc.ListDonePaged(-1, 10); err == nil {
+		t.Error("expected an error for a negative offset")
+	}
+}
+
+func BenchmarkListDonePaged(b *testing.B) {
+	svc := newServiceWithDone(b, 50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := svc.ListDonePaged(100, 50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListDoneFullLoad(b *testing.B) {
+	svc := newServiceWithDone(b, 50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := svc.Reload(); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := svc.ListDone(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStats_ComplexFixture(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	data.Now = func() time.Time { return time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	config.Reset()
+	config.SetCLIFlags(config.CLIFlags{TodoDir: filepath.Join("..", "..", "testdata", "complex")})
+	if _, err := config.Load(); err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	svc, err := NewTaskService()
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	stats, err := svc.Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	want := ServiceStats{
+		Total:   15,
+		Pending: 10,
+		Done:    5,
+		Overdue: 1, // due:2024-01-25; due:2026-01-23 is still ahead
+		ByPriority: map[data.Priority]int{
+			data.PriorityA:    1,
+			data.PriorityB:    3,
+			data.PriorityC:    2,
+			data.PriorityD:    1,
+			data.PriorityNone: 3,
+		},
+		ByProject: map[string]StatusCounts{
+			"backend":    {Pending: 3, Done: 3},
+			"devops":     {Pending: 2, Done: 2},
+			"docs":       {Pending: 2},
+			"new":        {Pending: 1},
+			"personal":   {Pending: 2},
+			"learning":   {Pending: 2},
+			"frontend":   {Pending: 1},
+			"management": {Pending: 1},
+		},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("Stats() =\n%+v\nwant\n%+v", stats, want)
+	}
+}
+
+func TestProjectsSorted(t *testing.T) {
+	svc := newTestService(t)
+	for _, line := range []string{"Mow lawn +home", "Write report +work +alpha", "Buy milk +errands"} {
+		if _, err := svc.Add(line); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if err := svc.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	var names []string
+	for _, p := range svc.ProjectsSorted() {
+		names = append(names, p.Name)
+	}
+	if want := []string{"alpha", "errands", "home", "work"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ProjectsSorted() = %v, want %v", names, want)
+	}
+}
+
+func TestAllowedTags_StrictMode(t *testing.T) {
+	svc := newTestService(t)
+	config.Get().AllowedTags = []string{"est"}
+
+	// rec is written by wydo itself, so it's always allowed
+	if _, err := svc.Add("Write report est:1h due:2024-03-01 rec:1w"); err != nil {
+		t.Fatalf("Add with allowed tags: %v", err)
+	}
+	_, err := svc.Add("Write report owner:sam")
+	if err == nil || !strings.Contains(err.Error(), `"owner"`) {
+		t.Fatalf("Add with a disallowed tag: err = %v, want one naming owner", err)
+	}
+
+	tasks, _ := svc.List()
+	if len(tasks) != 1 {
+		t.Fatalf("expected only the allowed task to be added, got %d tasks", len(tasks))
+	}
+	task := tasks[0]
+	task.Tags["owner"] = "sam"
+	if err := svc.Update(task); err == nil || !strings.Contains(err.Error(), `"owner"`) {
+		t.Errorf("Update with a disallowed tag: err = %v, want one naming owner", err)
+	}
+
+	// Without allowed_tags anything goes
+	config.Get().AllowedTags = nil
+	if _, err := svc.Add("Write report owner:sam"); err != nil {
+		t.Errorf("Add in non-strict mode: %v", err)
+	}
+}
+
+func TestInsert_KeepsFileOrder(t *testing.T) {
+	svc := newTestService(t)
+	for _, line := range []string{"second", "fourth"} {
+		if _, err := svc.Add(line); err != nil {
+			t.Fatalf("Add(%q): %v", line, err)
+		}
+	}
+
+	if _, err := svc.Insert("first", 0); err != nil {
+		t.Fatalf("Insert at top: %v", err)
+	}
+	task, err := svc.Insert("third", 2)
+	if err != nil {
+		t.Fatalf("Insert in middle: %v", err)
+	}
+	if _, err := svc.Insert("fifth", 99); err != nil {
+		t.Fatalf("Insert past the end: %v", err)
+	}
+
+	content, err := os.ReadFile(data.GetTodoFilePath())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if got, want := string(content), "first\nsecond\nthird\nfourth\nfifth\n"; got != want {
+		t.Errorf("todo.txt = %q, want %q", got, want)
+	}
+
+	// The returned ID matches the one the task has once reloaded
+	got, err := svc.Get(task.ID)
+	if err != nil || got.Name != "third" {
+		t.Errorf("Get(%s) = %v, %v; want the inserted task", task.ID, got, err)
+	}
+
+	if _, err := svc.Insert("bad", -1); err == nil {
+		t.Error("expected an error for a negative index")
+	}
+}
+
+func TestArchive_SortDoneByCompletion(t *testing.T) {
+	defer func(orig func() time.Time) { data.Now = orig }(data.Now)
+	svc := newTestService(t)
+	config.Get().SortDoneByCompletion = true
+
+	for _, line := range []string{
+		"x Undated",
+		"x 2024-03-01 Oldest",
+		"Newest",
+		"x 2024-03-05 Middle",
+	} {
+		if _, err := svc.Add(line); err != nil {
+			t.Fatalf("Add(%q): %v", line, err)
+		}
+	}
+	pending, _ := svc.ListPending()
+	data.Now = func() time.Time { return time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC) }
+	if err := svc.Complete(pending[0].ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if err := svc.Archive(); err != nil {
+		t.Fatalf("Archive: %v", err)
+	}
+
+	content, err := os.ReadFile(data.GetDoneFilePath())
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	want := "x 2024-03-09 Newest\nx 2024-03-05 Middle\nx 2024-03-01 Oldest\nx Undated\n"
+	if string(content) != want {
+		t.Errorf("done.txt = %q, want %q", string(content), want)
+	}
+}
+
+func TestAdd_ProjectDefaults(t *testing.T) {
+	svc := newTestService(t)
+	config.Get().ProjectDefaults = map[string]config.ProjectDefault{
+		"work": {Context: "office", Priority: "B"},
+	}
+
+	tests := []struct {
+		line     string
+		contexts []string
+		priority data.Priority
+	}{
+		{"Write report +work", []string{"office"}, data.PriorityB},
+		{"Call client +work @phone", []string{"phone"}, data.PriorityB},
+		{"(A) Fix outage +work", []string{"office"}, data.PriorityA},
+		{"Buy milk +home", nil, data.PriorityNone},
+	}
+	for _, tc := range tests {
+		task, err := svc.Add(tc.line)
+		if err != nil {
+			t.Fatalf("Add(%q): %v", tc.line, err)
+		}
+		if !reflect.DeepEqual(task.Contexts, tc.contexts) {
+			t.Errorf("Add(%q) contexts = %v, want %v", tc.line, task.Contexts, tc.contexts)
+		}
+		if task.Priority != tc.priority {
+			t.Errorf("Add(%q) priority = %q, want %q", tc.line, task.Priority, tc.priority)
+		}
+	}
+
+	// The defaults are written to the file
+	pending, _ := svc.ListPending()
+	if len(pending) != 4 || !pending[0].HasContext("office") || pending[0].Priority != data.PriorityB {
+		t.Errorf("expected the first task saved with @office and (B), got %+v", pending[0])
+	}
+}
+
+func TestErrors_MissingAndAmbiguousIDs(t *testing.T) {
+	svc := newTestService(t)
+
+	if _, err := svc.Get("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Get: err = %v, want ErrTaskNotFound", err)
+	}
+	if err := svc.Complete("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Complete: err = %v, want ErrTaskNotFound", err)
+	}
+	if err := svc.Delete("missing"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("Delete: err = %v, want ErrTaskNotFound", err)
+	}
+
+	tasks := []data.Task{{ID: "abcd1234"}, {ID: "abcd5678"}}
+	if _, err := data.FindByPartialID(tasks, "abcd"); !errors.Is(err, ErrAmbiguousID) {
+		t.Errorf("FindByPartialID: err = %v, want ErrAmbiguousID", err)
+	}
+	if _, err := data.FindByPartialID(tasks, "ffff"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("FindByPartialID: err = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestRenameTagKey(t *testing.T) {
+	svc := newTestService(t)
+	for _, line := range []string{
+		"Write report est:2h",
+		"Review PR est:30m",
+		"Plan sprint est:1d estimate:4h",
+		"Buy milk",
+	} {
+		if _, err := svc.Add(line); err != nil {
+			t.Fatalf("Add(%q): %v", line, err)
+		}
+	}
+
+	if err := svc.RenameTagKey("est", "estimate"); err != nil {
+		t.Fatalf("RenameTagKey: %v", err)
+	}
+
+	want := map[string]string{
+		"Write report": "2h",
+		"Review PR":    "30m",
+		"Plan sprint":  "4h",
+		"Buy milk":     "",
+	}
+	tasks, _ := svc.List()
+	for _, task := range tasks {
+		if _, ok := task.Tags["est"]; ok {
+			t.Errorf("%q still has est:", task.Name)
+		}
+		if got := task.Tags["estimate"]; got != want[task.Name] {
+			t.Errorf("%q estimate = %q, want %q", task.Name, got, want[task.Name])
+		}
+	}
+
+	content, _ := os.ReadFile(data.GetTodoFilePath())
+	if strings.Contains(string(content), "est:") || !strings.Contains(string(content), "estimate:2h") {
+		t.Errorf("todo.txt not rewritten:\n%s", content)
+	}
+
+	if err := svc.RenameTagKey("estimate", "bad key"); err == nil {
+		t.Error("expected an error for an invalid key")
+	}
+}
+
+func TestIdenticalLines_CompleteAndDeleteEach(t *testing.T) {
+	svc := newTestService(t)
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Add("Buy milk +home"); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	tasks, _ := svc.List()
+	if len(tasks) != 2 || tasks[0].ID == tasks[1].ID {
+		t.Fatalf("expected two tasks with distinct IDs, got %+v", tasks)
+	}
+	first, second := tasks[0].ID, tasks[1].ID
+
+	if err := svc.Complete(second); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	pending, _ := svc.ListPending()
+	done, _ := svc.ListDone()
+	if len(pending) != 1 || pending[0].ID != first || len(done) != 1 {
+		t.Fatalf("expected only the second copy done, got pending %+v, done %+v", pending, done)
+	}
+
+	if err := svc.Delete(first); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	remaining, _ := svc.List()
+	if len(remaining) != 1 || !remaining[0].Done {
+		t.Errorf("expected only the done copy left, got %+v", remaining)
+	}
+}
+
+func TestAdd_AfterBlankLineGetsUnusedID(t *testing.T) {
+	svc := newTestService(t)
+	if err := os.WriteFile(data.GetTodoFilePath(), []byte("Buy milk\n\nBuy milk\n"), 0644); err != nil {
+		t.Fatalf("Failed to write todo.txt: %v", err)
+	}
+	if err := svc.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	before, _ := svc.List()
+
+	added, err := svc.Add("Buy milk")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	for _, task := range before {
+		if task.ID == added.ID {
+			t.Errorf("added task reused the ID of an existing task: %s", added.ID)
+		}
+	}
+	// The returned ID is the one the task has after reloading
+	after, _ := svc.List()
+	if len(after) != 3 || after[2].ID != added.ID {
+		t.Errorf("expected the added task last with ID %s, got %+v", added.ID, after)
+	}
+}
+
+func TestUpdateAndDelete_SingleWrite(t *testing.T) {
+	svc := newTestService(t)
+	for _, line := range []string{"Plan trip; pack", "Water plants"} {
+		if _, err := svc.Add(line); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	tasks, _ := svc.List()
+	trip, plants := tasks[0], tasks[1]
+
+	plants.Name = "Water the plants"
+	part := data.Task{ID: "new", Name: "pack", Tags: map[string]string{}, File: data.GetTodoFilePath()}
+	if err := svc.UpdateAndDelete([]data.Task{plants, part}, []string{trip.ID, "unknown"}); err != nil {
+		t.Fatalf("UpdateAndDelete: %v", err)
+	}
+
+	tasks, _ = svc.List()
+	var names []string
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	if want := []string{"Water the plants", "pack"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("tasks = %v, want %v", names, want)
+	}
+}
@@ -1,7 +1,11 @@
 package ui
 
 import (
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/wyattlefevre/wydocli/internal/data"
@@ -15,54 +19,397 @@ var (
 	tagStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
 	nameStyle     = lipgloss.NewStyle()
 	dateStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	struckStyle   = doneStyle.Strikethrough(true)
+	warningStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("1"))
+	timeStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	blockedStyle  = lipgloss.NewStyle().Faint(true).Italic(true)
+	recurStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
 )
 
+// InvalidDateGlyph marks tags whose date value can't be parsed
+const InvalidDateGlyph = "⚠"
+
+// BlockedGlyph marks pending tasks that are blocked or waiting
+const BlockedGlyph = "⏸"
+
+// RecurGlyph marks the interval of a recurring task (rec: tag)
+const RecurGlyph = "↻"
+
+// Ellipsis marks a task name truncated to fit the available width
+const Ellipsis = "…"
+
+// minNameWidth keeps some of the name visible even when metadata alone
+// would fill the line
+const minNameWidth = 10
+
+// LineOptions controls optional parts of task line rendering
+type LineOptions struct {
+	// StrikeDone renders completed task names struck-through
+	StrikeDone bool
+	// Width is the available line width; 0 means unlimited. Long names are
+	// truncated with an ellipsis so the line fits.
+	Width int
+	// Wrap wraps long names onto continuation lines instead of truncating
+	Wrap bool
+	// CreatedAge shows the created date as a relative age ("12d ago")
+	// measured from Now (data.Now when zero)
+	CreatedAge bool
+	Now        time.Time
+	// NextOccurrence adds the due date a recurring task's next instance
+	// would get if it were done Now to its interval badge
+	NextOccurrence bool
+	// MetaColumn, when set, pads names so the metadata after them starts at
+	// this column, lining it up across tasks (see MetaColumn)
+	MetaColumn int
+}
+
+// namedColors maps color names accepted by the color: tag to ANSI color codes
+var namedColors = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
 // StyledTaskLine renders a task in a simple, readable format.
 // Format: [x] (A) Name +project @context due:date
 func StyledTaskLine(t data.Task) string {
-	var parts []string
+	return StyledTaskLineWithOptions(t, LineOptions{})
+}
+
+// StyledTaskLineWithOptions renders a task like StyledTaskLine with optional extras
+func StyledTaskLineWithOptions(t data.Task, opts LineOptions) string {
+	head, tail := lineParts(t, opts)
+	if t.Name == "" && opts.MetaColumn == 0 {
+		if tail == "" {
+			return head
+		}
+		return head + " " + tail
+	}
+
+	// Name, fitted to the width left over by the metadata
+	style := NameStyle(t, opts.StrikeDone)
+	avail := 0 // unlimited
+	if opts.Width > 0 {
+		avail = opts.Width - lipgloss.Width(head) - 1
+		if tail != "" {
+			avail -= lipgloss.Width(tail) + 1
+		}
+		avail = max(avail, minNameWidth)
+	}
+	if opts.MetaColumn > 0 {
+		column := max(opts.MetaColumn-lipgloss.Width(head)-1, minNameWidth)
+		if avail == 0 || column < avail {
+			avail = column
+		}
+	}
+	nameLines := []string{t.Name}
+	if avail > 0 {
+		if opts.Wrap {
+			nameLines = wrapText(t.Name, avail)
+		} else {
+			nameLines = []string{truncateText(t.Name, avail)}
+		}
+	}
+
+	indent := strings.Repeat(" ", lipgloss.Width(head)+1)
+	var b strings.Builder
+	for i, line := range nameLines {
+		if i == 0 {
+			b.WriteString(head + " ")
+		} else {
+			b.WriteString("\n" + indent)
+		}
+		b.WriteString(style.Render(line))
+	}
+	if tail != "" {
+		if opts.MetaColumn > 0 {
+			// Padded outside the name's style so it isn't struck through
+			b.WriteString(strings.Repeat(" ", max(avail-lipgloss.Width(nameLines[len(nameLines)-1]), 0)))
+		}
+		b.WriteString(" " + tail)
+	}
+	return b.String()
+}
+
+// MetaColumn returns the column where metadata starts when tasks are
+// rendered aligned: just past the longest checkbox, dates and name, pulled
+// in so the widest metadata still fits in opts.Width
+func MetaColumn(tasks []data.Task, opts LineOptions) int {
+	column, widestTail := 0, 0
+	for _, t := range tasks {
+		head, tail := lineParts(t, opts)
+		column = max(column, lipgloss.Width(head)+1+lipgloss.Width(t.Name))
+		widestTail = max(widestTail, lipgloss.Width(tail))
+	}
+	if opts.Width > 0 && widestTail > 0 {
+		column = min(column, opts.Width-widestTail-1)
+	}
+	return max(column, 0)
+}
+
+// lineParts renders what goes before a task's name (checkbox, priority,
+// dates) and after it (projects, contexts, time tracking, tags)
+func lineParts(t data.Task, opts LineOptions) (string, string) {
+	var prefix, suffix []string
 
 	// Status checkbox
 	if t.Done {
-		parts = append(parts, doneStyle.Render("[x]"))
+		prefix = append(prefix, doneStyle.Render("[x]"))
 	} else {
-		parts = append(parts, "[ ]")
+		prefix = append(prefix, "[ ]")
+	}
+
+	if !t.Done && t.IsBlocked() {
+		prefix = append(prefix, blockedStyle.Render(BlockedGlyph))
 	}
 
 	// Priority
 	if t.Priority != 0 {
-		parts = append(parts, priorityStyle.Render("("+string(t.Priority)+")"))
+		prefix = append(prefix, priorityStyle.Render("("+string(t.Priority)+")"))
 	}
 	if t.CreatedDate != "" {
-		parts = append(parts, dateStyle.Render(t.CreatedDate))
+		created := data.FormatDisplayDate(t.CreatedDate)
+		if opts.CreatedAge {
+			now := opts.Now
+			if now.IsZero() {
+				now = data.Now()
+			}
+			if age := RelativeAge(t.CreatedDate, now); age != "" {
+				created = age
+			}
+		}
+		prefix = append(prefix, dateStyle.Render(created))
 	}
 	if t.CompletionDate != "" {
-		parts = append(parts, dateStyle.Render(t.CompletionDate))
-	}
-
-	// Name
-	if t.Name != "" {
-		if t.Done {
-			parts = append(parts, doneStyle.Render(t.Name))
-		} else {
-			parts = append(parts, nameStyle.Render(t.Name))
-		}
+		prefix = append(prefix, dateStyle.Render(data.FormatDisplayDate(t.CompletionDate)))
 	}
 
 	// Projects
 	for _, p := range t.Projects {
-		parts = append(parts, projectStyle.Render("+"+p))
+		suffix = append(suffix, projectStyle.Render("+"+p))
 	}
 
 	// Contexts
 	for _, c := range t.Contexts {
-		parts = append(parts, contextStyle.Render("@"+c))
+		suffix = append(suffix, contextStyle.Render("@"+c))
+	}
+
+	// Time tracking: est:/spent: render together as spent/estimate
+	if timeStr := TimeTrackingString(t); timeStr != "" {
+		suffix = append(suffix, timeStyle.Render(timeStr))
 	}
 
-	// Tags (including due date)
-	for k, v := range t.Tags {
-		parts = append(parts, tagStyle.Render(k+":"+v))
+	// Tags (including due date), sorted for a stable layout
+	keys := make([]string, 0, len(t.Tags))
+	for k := range t.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := t.Tags[k]
+		if isTimeTrackingTag(t, k) {
+			continue
+		}
+		if k == "rec" {
+			if badge := RecurrenceBadge(t, opts); badge != "" {
+				suffix = append(suffix, recurStyle.Render(badge))
+				continue
+			}
+		}
+		if k == "due" && t.HasInvalidDueDate() {
+			suffix = append(suffix, warningStyle.Render(InvalidDateGlyph+" "+k+":"+v))
+			continue
+		}
+		if isDateTag(k) {
+			v = data.FormatDisplayDate(v)
+		}
+		suffix = append(suffix, tagStyle.Render(k+":"+v))
 	}
 
-	return strings.Join(parts, " ")
+	return strings.Join(prefix, " "), strings.Join(suffix, " ")
+}
+
+// RecurrenceBadge renders a recurring task's interval ("↻1w"), followed by
+// its next due date ("↻1w→2024-03-08") with opts.NextOccurrence. Returns ""
+// for tasks without a valid rec: tag.
+func RecurrenceBadge(t data.Task, opts LineOptions) string {
+	rec, ok := t.GetRecurrence()
+	if !ok {
+		return ""
+	}
+	badge := RecurGlyph + rec.String()
+	if opts.NextOccurrence {
+		now := opts.Now
+		if now.IsZero() {
+			now = data.Now()
+		}
+		badge += "→" + data.FormatDisplayDate(t.NextOccurrence(now))
+	}
+	return badge
+}
+
+// RelativeAge renders how long before now a yyyy-MM-dd date was, in the
+// largest whole unit: "today", "12d ago", "3w ago", "5mo ago", "2y ago".
+// Returns "" for an unparseable date.
+func RelativeAge(date string, now time.Time) string {
+	then, err := time.Parse(data.DateFormat, date)
+	if err != nil {
+		return ""
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(today.Sub(then).Hours() / 24)
+	switch {
+	case days <= 0:
+		return "today"
+	case days < 14:
+		return fmt.Sprintf("%dd ago", days)
+	case days < 60:
+		return fmt.Sprintf("%dw ago", days/7)
+	case days < 730:
+		return fmt.Sprintf("%dmo ago", days/30)
+	default:
+		return fmt.Sprintf("%dy ago", days/365)
+	}
+}
+
+// truncateText shortens s to at most width cells, ending it with an
+// ellipsis when anything was cut
+func truncateText(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	head, _ := splitAtWidth(s, width-lipgloss.Width(Ellipsis))
+	return head + Ellipsis
+}
+
+// wrapText breaks s into lines of at most width cells, splitting on spaces
+// where possible and hard-splitting words longer than a line
+func wrapText(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for lipgloss.Width(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			var head string
+			head, word = splitAtWidth(word, width)
+			lines = append(lines, head)
+		}
+		switch {
+		case line == "":
+			line = word
+		case lipgloss.Width(line)+1+lipgloss.Width(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// splitAtWidth splits s after the longest prefix that fits in width cells
+func splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
+
+// TimeTrackingString renders the spent: and est: tags compactly as
+// spent/estimate (e.g. "2h/5h"), with "-" for a missing side.
+// Returns "" if the task has neither.
+func TimeTrackingString(t data.Task) string {
+	est, hasEst := t.GetEstimate()
+	spent, hasSpent := t.GetSpent()
+	if !hasEst && !hasSpent {
+		return ""
+	}
+	estStr, spentStr := "-", "-"
+	if hasEst {
+		estStr = data.FormatMinutes(est)
+	}
+	if hasSpent {
+		spentStr = data.FormatMinutes(spent)
+	}
+	return spentStr + "/" + estStr
+}
+
+// isDateTag reports whether a tag's value is a date shown in the display
+// date format
+func isDateTag(key string) bool {
+	return key == "due" || key == "t"
+}
+
+// isTimeTrackingTag reports whether a tag is folded into TimeTrackingString.
+// Unparseable values are shown as regular tags.
+func isTimeTrackingTag(t data.Task, key string) bool {
+	switch key {
+	case "est":
+		_, ok := t.GetEstimate()
+		return ok
+	case "spent":
+		_, ok := t.GetSpent()
+		return ok
+	}
+	return false
+}
+
+// TaskNameStyle returns the style used to render a task's name.
+// Done tasks are always dimmed and blocked ones faint; otherwise a valid
+// color: tag overrides the default.
+func TaskNameStyle(t data.Task) lipgloss.Style {
+	if t.Done {
+		return doneStyle
+	}
+	if t.IsBlocked() {
+		return blockedStyle
+	}
+	if color, ok := ResolveColor(t.Tags["color"]); ok {
+		return nameStyle.Foreground(color)
+	}
+	return nameStyle
+}
+
+// NameStyle is TaskNameStyle, struck-through for done tasks when strike is
+// set. The task list and the editor both use it so done names match.
+func NameStyle(t data.Task, strike bool) lipgloss.Style {
+	if t.Done && strike {
+		return struckStyle
+	}
+	return TaskNameStyle(t)
+}
+
+// ResolveColor converts a color: tag value (a name like "red" or an ANSI
+// code from 0-255) into a lipgloss color. Returns false for unknown values.
+func ResolveColor(value string) (lipgloss.Color, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", false
+	}
+	if code, ok := namedColors[value]; ok {
+		return lipgloss.Color(code), true
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || n > 255 {
+		return "", false
+	}
+	return lipgloss.Color(strconv.Itoa(n)), true
 }
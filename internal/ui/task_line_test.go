@@ -0,0 +1,324 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wyattlefevre/wydocli/internal/config"
+	"github.com/wyattlefevre/wydocli/internal/data"
+)
+
+func TestTaskNameStyle_ColorTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		expected lipgloss.TerminalColor
+	}{
+		{
+			name:     "numeric color",
+			tags:     map[string]string{"color": "2"},
+			expected: lipgloss.Color("2"),
+		},
+		{
+			name:     "named color",
+			tags:     map[string]string{"color": "red"},
+			expected: lipgloss.Color("1"),
+		},
+		{
+			name:     "unknown color falls back to default",
+			tags:     map[string]string{"color": "chartreuse"},
+			expected: nameStyle.GetForeground(),
+		},
+		{
+			name:     "out of range color falls back to default",
+			tags:     map[string]string{"color": "300"},
+			expected: nameStyle.GetForeground(),
+		},
+		{
+			name:     "no color tag",
+			tags:     map[string]string{},
+			expected: nameStyle.GetForeground(),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			task := data.Task{Name: "Test task", Tags: tc.tags}
+			got := TaskNameStyle(task).GetForeground()
+			if got != tc.expected {
+				t.Errorf("foreground = %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestTaskNameStyle_DoneIgnoresColorTag(t *testing.T) {
+	task := data.Task{Name: "Test task", Done: true, Tags: map[string]string{"color": "2"}}
+	if got := TaskNameStyle(task).GetForeground(); got != doneStyle.GetForeground() {
+		t.Errorf("foreground = %v, want done style %v", got, doneStyle.GetForeground())
+	}
+}
+
+func TestStyledTaskLineWithOptions_StrikeDone(t *testing.T) {
+	if !struckStyle.GetStrikethrough() {
+		t.Fatal("expected struck style to use strikethrough")
+	}
+
+	task := data.Task{Name: "Finished", Done: true}
+	struck := StyledTaskLineWithOptions(task, LineOptions{StrikeDone: true})
+	if want := doneStyle.Render("[x]") + " " + struckStyle.Render("Finished"); struck != want {
+		t.Errorf("struck line = %q, want %q", struck, want)
+	}
+
+	// Pending tasks are never struck through
+	pending := data.Task{Name: "Open"}
+	if got := StyledTaskLineWithOptions(pending, LineOptions{StrikeDone: true}); got != StyledTaskLine(pending) {
+		t.Errorf("pending line = %q, want %q", got, StyledTaskLine(pending))
+	}
+}
+
+func TestStyledTaskLine_FlagsInvalidDueDate(t *testing.T) {
+	invalid := data.ParseTask("Pay rent due:2024-13-40", "abc", "todo.txt")
+	if line := StyledTaskLine(invalid); !strings.Contains(line, InvalidDateGlyph) {
+		t.Errorf("expected invalid due date to be flagged, got %q", line)
+	}
+
+	valid := data.ParseTask("Pay rent due:2024-01-31", "abc", "todo.txt")
+	if line := StyledTaskLine(valid); strings.Contains(line, InvalidDateGlyph) {
+		t.Errorf("expected valid due date not to be flagged, got %q", line)
+	}
+}
+
+func TestStyledTaskLine_MarksBlockedTasks(t *testing.T) {
+	for _, line := range []string{"Sign contract blocked:legal", "Sign contract @waiting"} {
+		task := data.ParseTask(line, "abc", "todo.txt")
+		if got := StyledTaskLine(task); !strings.Contains(got, BlockedGlyph) {
+			t.Errorf("%q: expected the blocked glyph, got %q", line, got)
+		}
+		if !TaskNameStyle(task).GetFaint() {
+			t.Errorf("%q: expected a faint name style", line)
+		}
+	}
+
+	open := data.ParseTask("Sign contract @office", "abc", "todo.txt")
+	if got := StyledTaskLine(open); strings.Contains(got, BlockedGlyph) {
+		t.Errorf("expected no blocked glyph, got %q", got)
+	}
+	done := data.ParseTask("x Sign contract blocked:legal", "abc", "todo.txt")
+	if got := StyledTaskLine(done); strings.Contains(got, BlockedGlyph) {
+		t.Errorf("expected no blocked glyph on a done task, got %q", got)
+	}
+}
+
+func TestStyledTaskLine_TruncatesLongNames(t *testing.T) {
+	task := data.ParseTask("abcdefghijklmnopqrstuvwxyz0123456789", "1", "")
+
+	line := StyledTaskLineWithOptions(task, LineOptions{Width: 20})
+	expected := "[ ] abcdefghijklmno" + Ellipsis
+	if line != expected {
+		t.Errorf("line = %q, want %q", line, expected)
+	}
+	if w := lipgloss.Width(line); w != 20 {
+		t.Errorf("width = %d, want 20", w)
+	}
+
+	// Metadata keeps its place after the truncated name
+	task = data.ParseTask("abcdefghijklmnopqrstuvwxyz +home", "1", "")
+	line = StyledTaskLineWithOptions(task, LineOptions{Width: 30})
+	expected = "[ ] abcdefghijklmnopqrs" + Ellipsis + " +home"
+	if line != expected {
+		t.Errorf("line = %q, want %q", line, expected)
+	}
+
+	// Names that fit, or no width, are left alone
+	if line := StyledTaskLineWithOptions(task, LineOptions{}); strings.Contains(line, Ellipsis) {
+		t.Errorf("expected no truncation without a width, got %q", line)
+	}
+}
+
+func TestStyledTaskLine_WrapsLongNames(t *testing.T) {
+	task := data.ParseTask("write the quarterly report for the team", "1", "")
+
+	line := StyledTaskLineWithOptions(task, LineOptions{Width: 24, Wrap: true})
+	expected := "[ ] write the quarterly\n    report for the team"
+	if line != expected {
+		t.Errorf("line = %q, want %q", line, expected)
+	}
+	for _, l := range strings.Split(line, "\n") {
+		if w := lipgloss.Width(l); w > 24 {
+			t.Errorf("wrapped line %q is %d wide, want <= 24", l, w)
+		}
+	}
+}
+
+func TestWrapText_SplitsLongWords(t *testing.T) {
+	got := wrapText("abcdefghijklmnop qr", 10)
+	expected := []string{"abcdefghij", "klmnop qr"}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("wrapText = %q, want %q", got, expected)
+	}
+}
+
+func TestTimeTrackingString(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Write report est:5h spent:2h", "2h/5h"},
+		{"Write report est:90", "-/1h30m"},
+		{"Write report spent:45m", "45m/-"},
+		{"Write report", ""},
+		{"Write report est:soon", ""},
+	}
+
+	for _, tc := range tests {
+		task := data.ParseTask(tc.input, "1", "")
+		if got := TimeTrackingString(task); got != tc.expected {
+			t.Errorf("TimeTrackingString(%q) = %q, want %q", tc.input, got, tc.expected)
+		}
+	}
+}
+
+func TestStyledTaskLine_RendersTimeTracking(t *testing.T) {
+	task := data.ParseTask("Write report +work est:5h spent:2h due:2024-01-01", "1", "")
+	line := StyledTaskLine(task)
+	expected := "[ ] Write report +work 2h/5h due:2024-01-01"
+	if line != expected {
+		t.Errorf("line = %q, want %q", line, expected)
+	}
+
+	// Unparseable values stay visible as plain tags
+	task = data.ParseTask("Write report est:soon", "1", "")
+	if line := StyledTaskLine(task); !strings.Contains(line, "est:soon") {
+		t.Errorf("expected raw est tag, got %q", line)
+	}
+}
+
+func TestRelativeAge(t *testing.T) {
+	now := time.Date(2024, 3, 15, 18, 30, 0, 0, time.Local)
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2024-03-15", "today"},
+		{"2024-03-20", "today"}, // created in the future
+		{"2024-03-14", "1d ago"},
+		{"2024-03-03", "12d ago"},
+		{"2024-03-02", "13d ago"},
+		{"2024-03-01", "2w ago"},
+		{"2024-01-16", "8w ago"},
+		{"2024-01-15", "2mo ago"},
+		{"2022-03-17", "24mo ago"},
+		{"2022-03-16", "2y ago"},
+		{"not-a-date", ""},
+	}
+	for _, tc := range tests {
+		if got := RelativeAge(tc.date, now); got != tc.want {
+			t.Errorf("RelativeAge(%q) = %q, want %q", tc.date, got, tc.want)
+		}
+	}
+}
+
+func TestStyledTaskLine_CreatedAge(t *testing.T) {
+	now := time.Date(2024, 3, 15, 9, 0, 0, 0, time.UTC)
+	task := data.Task{Name: "Renew passport", CreatedDate: "2024-03-03", Tags: map[string]string{}}
+
+	line := StyledTaskLineWithOptions(task, LineOptions{CreatedAge: true, Now: now})
+	if !strings.Contains(line, "12d ago") || strings.Contains(line, "2024-03-03") {
+		t.Errorf("expected the age instead of the date, got %q", line)
+	}
+	if line := StyledTaskLine(task); !strings.Contains(line, "2024-03-03") {
+		t.Errorf("expected the date by default, got %q", line)
+	}
+
+	undated := data.Task{Name: "Renew passport", Tags: map[string]string{}}
+	if got := StyledTaskLineWithOptions(undated, LineOptions{CreatedAge: true, Now: now}); got != StyledTaskLine(undated) {
+		t.Errorf("expected nothing extra without a created date, got %q", got)
+	}
+}
+
+func TestStyledTaskLine_DisplayDateFormat(t *testing.T) {
+	defer func(orig string) { config.Get().DisplayDateFormat = orig }(config.Get().DisplayDateFormat)
+
+	line := "x 2024-03-10 2024-03-01 File taxes due:2024-04-15 t:2024-03-20"
+	task := data.ParseTask(line, "1", "todo.txt")
+
+	config.Get().DisplayDateFormat = "short"
+	rendered := StyledTaskLine(task)
+	for _, want := range []string{"Mar 10", "Mar 01", "due:Apr 15", "t:Mar 20"} {
+		if !strings.Contains(rendered, want) {
+			t.Errorf("expected %q in %q", want, rendered)
+		}
+	}
+	if strings.Contains(rendered, "2024-") {
+		t.Errorf("expected no ISO dates in %q", rendered)
+	}
+	if task.String() != line {
+		t.Errorf("stored form changed: %q, want %q", task.String(), line)
+	}
+
+	config.Get().DisplayDateFormat = "02.01.2006"
+	if rendered := StyledTaskLine(task); !strings.Contains(rendered, "due:15.04.2024") {
+		t.Errorf("expected a custom layout date in %q", rendered)
+	}
+}
+
+func TestNameStyle_StrikeDone(t *testing.T) {
+	done := data.ParseTask("x 2024-03-01 Finished", "", "")
+	pending := data.ParseTask("Not yet", "", "")
+
+	if !NameStyle(done, true).GetStrikethrough() {
+		t.Error("expected a struck-through name for a done task with strike on")
+	}
+	if NameStyle(done, false).GetStrikethrough() {
+		t.Error("expected no strikethrough with strike off")
+	}
+	if NameStyle(pending, true).GetStrikethrough() {
+		t.Error("expected pending names never to be struck through")
+	}
+}
+
+func TestMetaColumn_FitsWidestMetadata(t *testing.T) {
+	tasks := []data.Task{
+		data.ParseTask("short +home", "1", ""),
+		data.ParseTask("a rather long task name that runs on +work @office", "2", ""),
+	}
+
+	if got, want := MetaColumn(tasks, LineOptions{}), len("[ ] a rather long task name that runs on"); got != want {
+		t.Errorf("unlimited width column = %d, want %d", got, want)
+	}
+
+	// The column is pulled in so the widest metadata still fits
+	opts := LineOptions{Width: 40}
+	opts.MetaColumn = MetaColumn(tasks, opts)
+	if want := 40 - len("+work @office") - 1; opts.MetaColumn != want {
+		t.Errorf("column = %d, want %d", opts.MetaColumn, want)
+	}
+	for _, task := range tasks {
+		if line := StyledTaskLineWithOptions(task, opts); lipgloss.Width(line) > 40 {
+			t.Errorf("line %q is wider than 40", line)
+		}
+	}
+}
+
+func TestStyledTaskLine_RecurrenceBadge(t *testing.T) {
+	task := data.ParseTask("Water plants rec:1w", "1", "")
+	if line := StyledTaskLine(task); !strings.Contains(line, RecurGlyph+"1w") || strings.Contains(line, "rec:") {
+		t.Errorf("expected the rec: tag shown as a %s1w badge, got %q", RecurGlyph, line)
+	}
+
+	// With the toggle, the badge also shows the next due date
+	now := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	line := StyledTaskLineWithOptions(task, LineOptions{NextOccurrence: true, Now: now})
+	if !strings.Contains(line, RecurGlyph+"1w→2024-03-22") {
+		t.Errorf("expected the next occurrence in the badge, got %q", line)
+	}
+
+	// Unparseable intervals stay plain tags
+	task = data.ParseTask("Water plants rec:sometimes", "1", "")
+	if line := StyledTaskLine(task); !strings.Contains(line, "rec:sometimes") || strings.Contains(line, RecurGlyph) {
+		t.Errorf("expected an invalid rec: to render as a tag, got %q", line)
+	}
+}
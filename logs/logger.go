@@ -64,6 +64,17 @@ func Initialize(logDir string) error {
     return nil
 }
 
+// Path returns the location of the log file.
+func Path() string {
+    mu.Lock()
+    defer mu.Unlock()
+
+    if logFile == nil {
+        return ""
+    }
+    return logFile.Name()
+}
+
 // Close closes the log file. Useful for cleanup in tests.
 func Close() error {
     mu.Lock()